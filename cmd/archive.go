@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/archive"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archivePath          string
+	archiveSyncParallel  int
+	archiveSyncPageSize  int
+	archiveSyncRecheck   string
+	archiveSearchChannel string
+	archiveSearchFrom    string
+	archiveSearchLimit   int
+	archiveSearchContext int
+	archiveVersionsTS    string
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Maintain a local SQLite archive of channels",
+}
+
+var archiveSyncCmd = &cobra.Command{
+	Use:   "sync <channel>...",
+	Short: "Archive new messages, threads, and users for channels",
+	Long: `Fetch messages newer than the last sync (plus updated threads and new authors)
+for each channel and store them in the local archive.
+
+Archived messages that have disappeared from Slack are kept, marked with the time the
+deletion was detected (deleted_at). A sync only re-reads threads whose replies changed;
+--recheck also re-fetches messages archived since a time (e.g. 7d), to detect edits and
+deletions there.
+
+With --stdin-channels, channels are also read from stdin (one per line). In a terminal,
+omitting the channel opens a picker of known channels.
+
+Examples:
+  slack-reader archive sync "#general" --workspace myteam
+  slack-reader archive sync "#general" "#ops" C0123ABC --workspace myteam --db ./team.db
+  slack-reader archive sync "#big-channel" --workspace myteam --parallel 8
+  slack-reader archive sync "#moderated" --workspace myteam --recheck 7d
+  slack-reader channel list --workspace myteam --all --output text | slack-reader archive sync --workspace myteam --stdin-channels`,
+	Args: channelArgs(cobra.MinimumNArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		client := newClient()
+		args, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		var recheck time.Time
+		if archiveSyncRecheck != "" {
+			if recheck, err = islack.ParseTimeSpec(archiveSyncRecheck, time.Now()); err != nil {
+				output.Exit(fmt.Errorf("--recheck: %w", err), output.ExitUsage)
+			}
+		}
+
+		db := openArchive(ctx)
+		defer db.Close()
+
+		results := make([]*archive.SyncResult, 0, len(args))
+		for _, arg := range args {
+			channelID, err := islack.ResolveChannelID(ctx, client, arg)
+			if err != nil {
+				output.PrintError(err)
+			}
+
+			result, err := archive.Sync(ctx, client, db, channelID, archive.SyncOptions{
+				Parallel: archiveSyncParallel,
+				PageSize: archiveSyncPageSize,
+				Recheck:  recheck,
+			})
+			if err != nil {
+				islack.InvalidateChannelOnError(client, arg, err)
+				output.PrintError(err)
+			}
+			results = append(results, result)
+		}
+
+		output.PrintJSON(map[string]any{
+			"channels": results,
+		})
+	},
+}
+
+var archiveStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show archived channels, message counts, and sync state",
+	Long: `Show archived channels, message counts, and sync state.
+
+Examples:
+  slack-reader archive status --workspace myteam`,
+	Run: func(_ *cobra.Command, _ []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+		db := openArchive(ctx)
+		defer db.Close()
+
+		status, err := db.Status(ctx)
+		if err != nil {
+			output.PrintError(err)
+		}
+		status.Path = archivePath
+		output.PrintJSON(status)
+	},
+}
+
+var archiveSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over the local archive",
+	Long: `Search archived messages offline. All terms must match; results include
+surrounding messages for context and permalinks.
+
+Examples:
+  slack-reader archive search "rollback plan" --workspace myteam
+  slack-reader archive search "rollback plan" --workspace myteam --channel "#ops" --from @alice
+  slack-reader archive search deploy --workspace myteam --context 5 --limit 50`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+		db := openArchive(ctx)
+		defer db.Close()
+
+		opts := archive.SearchOptions{
+			Query:   args[0],
+			Limit:   archiveSearchLimit,
+			Context: archiveSearchContext,
+			Domain:  workspace,
+		}
+
+		if archiveSearchChannel != "" {
+			opts.ChannelID = archiveChannelID(ctx, db, archiveSearchChannel)
+		}
+		if archiveSearchFrom != "" {
+			id, err := db.LookupUser(ctx, strings.TrimPrefix(strings.TrimSpace(archiveSearchFrom), "@"))
+			if err != nil {
+				output.PrintError(err)
+			}
+			opts.UserID = id
+		}
+
+		matches, err := db.Search(ctx, opts)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		output.PrintJSON(map[string]any{
+			"matches": matches,
+		})
+	},
+}
+
+var archiveVersionsCmd = &cobra.Command{
+	Use:   "versions <channel>",
+	Short: "Show the archived edit history of a message",
+	Long: `Show the versions of an archived message, oldest first, ending with the current text.
+
+Prior versions are kept when a sync re-fetches a message whose text has changed
+(new messages and replies in updated threads), so edits made between syncs can be
+compared. A message link can stand in for the channel and --ts.
+
+Examples:
+  slack-reader archive versions "#ops" --ts 1700000000.000100 --workspace myteam
+  slack-reader archive versions https://myteam.slack.com/archives/C0123ABC/p1700000000000100 --workspace myteam`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx, cancel := commandContext()
+		defer cancel()
+		db := openArchive(ctx)
+		defer db.Close()
+
+		if archiveVersionsTS == "" {
+			archiveVersionsTS, _ = linkTimestamps(args[0])
+		}
+		if archiveVersionsTS == "" {
+			output.Exit(errors.New("--ts is required"), output.ExitUsage)
+		}
+		channelID := archiveChannelID(ctx, db, args[0])
+
+		versions, err := db.MessageVersions(ctx, channelID, archiveVersionsTS)
+		if err != nil {
+			output.PrintError(err)
+		}
+		if versions == nil {
+			output.PrintError(fmt.Errorf("message not in archive: %s", archiveVersionsTS))
+		}
+
+		output.PrintJSON(map[string]any{
+			"channel":  channelID,
+			"ts":       archiveVersionsTS,
+			"versions": versions,
+		})
+	},
+}
+
+// archiveChannelID resolves a channel name, ID, or link against the archive.
+func archiveChannelID(ctx context.Context, db *archive.DB, input string) string {
+	name, isID := islack.NormalizeChannelInput(input)
+	if isID {
+		return name
+	}
+	id, err := db.LookupChannel(ctx, name)
+	if err != nil {
+		output.PrintError(err)
+	}
+	return id
+}
+
+// openArchive opens the archive at --db, defaulting to the per-workspace location.
+func openArchive(ctx context.Context) *archive.DB {
+	if archivePath == "" {
+		path, err := archive.DefaultPath(requireWorkspace())
+		if err != nil {
+			output.PrintError(err)
+		}
+		archivePath = path
+	}
+
+	db, err := archive.Open(ctx, archivePath)
+	if err != nil {
+		output.PrintError(err)
+	}
+	return db
+}
+
+func init() {
+	archiveCmd.PersistentFlags().StringVar(&archivePath, "db", "", "Archive database path (default: ~/.local/share/slack-reader/<workspace>.db)")
+
+	archiveCmd.AddCommand(archiveSyncCmd)
+	archiveSyncCmd.Flags().BoolVar(&stdinChannels, "stdin-channels", false, "Also read channels from stdin, one per line")
+	archiveSyncCmd.Flags().IntVar(&archiveSyncPageSize, "page-size", 200, "Messages requested per API call (max 200)")
+	archiveSyncCmd.Flags().IntVar(&archiveSyncParallel, "parallel", 1, "Split the fetched time range into N windows fetched concurrently")
+	archiveSyncCmd.Flags().StringVar(&archiveSyncRecheck, "recheck", "", "Also re-fetch messages archived since this time (e.g., 7d, 2024-03-01) to detect edits and deletions")
+
+	archiveSearchCmd.Flags().StringVar(&archiveSearchChannel, "channel", "", "Only search this channel (e.g., \"#ops\")")
+	archiveSearchCmd.Flags().StringVar(&archiveSearchFrom, "from", "", "Only search messages from this user (e.g., \"@alice\")")
+	archiveSearchCmd.Flags().IntVar(&archiveSearchLimit, "limit", 20, "Maximum number of matches")
+	archiveSearchCmd.Flags().IntVar(&archiveSearchContext, "context", 2, "Messages of context before and after each match")
+
+	archiveVersionsCmd.Flags().StringVar(&archiveVersionsTS, "ts", "", "Message timestamp (required unless a message link is given)")
+
+	archiveCmd.AddCommand(archiveStatusCmd)
+	archiveCmd.AddCommand(archiveSearchCmd)
+	archiveCmd.AddCommand(archiveVersionsCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
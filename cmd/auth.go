@@ -2,9 +2,9 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/sethrylan/slack-reader/internal/output"
 	islack "github.com/sethrylan/slack-reader/internal/slack"
@@ -20,13 +20,11 @@ var authWhoamiCmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Show current authentication info (calls auth.test)",
 	Run: func(_ *cobra.Command, _ []string) {
-		domain := requireWorkspace()
-		client, err := islack.NewClient(domain)
-		if err != nil {
-			output.PrintError(err)
-		}
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
 
-		resp, err := client.API(context.Background(), "auth.test", nil)
+		resp, err := client.API(ctx, "auth.test", nil)
 		if err != nil {
 			output.PrintError(err)
 		}
@@ -40,13 +38,17 @@ var authCredsCmd = &cobra.Command{
 	Short: "Import credentials from Slack Desktop (cookie-based auth)",
 	Run: func(_ *cobra.Command, _ []string) {
 		domain := requireWorkspace()
-		client := islack.NewClientNoCreds(domain)
+		requireDesktop()
+		client := islack.NewClientNoCreds(domain, transportOptions()...)
 		if err := client.ImportCreds(); err != nil {
 			output.PrintError(err)
 		}
 
+		ctx, cancel := commandContext()
+		defer cancel()
+
 		// Verify by calling auth.test
-		resp, err := client.API(context.Background(), "auth.test", nil)
+		resp, err := client.API(ctx, "auth.test", nil)
 		if err != nil {
 			output.PrintError(err)
 		}
@@ -61,6 +63,7 @@ var authTokenCmd = &cobra.Command{
 	Short: "Print token and cookies from Slack Desktop (for use as SLACK_TOKEN and SLACK_COOKIES)",
 	Run: func(_ *cobra.Command, _ []string) {
 		domain := requireWorkspace()
+		requireDesktop()
 		auth, err := islack.GetCookieAuth(domain)
 		if err != nil {
 			output.PrintError(err)
@@ -69,13 +72,30 @@ var authTokenCmd = &cobra.Command{
 	},
 }
 
+// requireWorkspace returns the workspace: --workspace (or a Slack link's), else the
+// config file's, else $SLACK_READER_WORKSPACE or $SLACK_WORKSPACE.
 func requireWorkspace() string {
 	if workspace == "" {
-		output.PrintError(errors.New("--workspace is required (e.g., --workspace myteam)"))
+		workspace = configWorkspace
+	}
+	for _, env := range []string{"SLACK_READER_WORKSPACE", "SLACK_WORKSPACE"} {
+		if workspace == "" {
+			workspace = os.Getenv(env)
+		}
+	}
+	if workspace == "" {
+		output.PrintError(errors.New("--workspace is required (e.g., --workspace myteam, or set SLACK_WORKSPACE)"))
 	}
 	return workspace
 }
 
+// requireDesktop fails commands that read Slack Desktop credentials under --non-interactive.
+func requireDesktop() {
+	if nonInteractive {
+		output.PrintError(fmt.Errorf("%w: reading Slack Desktop credentials is disabled by --non-interactive", islack.ErrAuthFailed))
+	}
+}
+
 func init() {
 	authCmd.AddCommand(authWhoamiCmd)
 	authCmd.AddCommand(authCredsCmd)
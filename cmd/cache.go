@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sethrylan/slack-reader/internal/cache"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	warmUsers    bool
+	warmChannels bool
+	clearAll     bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage local caches",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached data (channel IDs, users, API responses)",
+	Long: `Remove cached data, including cached API responses, for the workspace (from --workspace,
+the config file, or SLACK_WORKSPACE), or for every workspace with --all.
+
+Examples:
+  slack-reader cache clear --workspace myteam
+  slack-reader cache clear --all`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		target := ""
+		if clearAll {
+			if workspace != "" {
+				output.Exit(errors.New("--all and --workspace are mutually exclusive"), output.ExitUsage)
+			}
+		} else {
+			target = requireWorkspace()
+		}
+		if err := cache.Clear(target); err != nil {
+			output.PrintError(err)
+		}
+		fmt.Println("Cache cleared.")
+	},
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Fill the user and channel caches in one go",
+	Long: `Download the member directory (users.list) and the conversation list
+(conversations.list) into the workspace's caches, so that later commands resolve user
+and channel names without API calls, e.g. before a large export or digest.
+
+--users and --channels warm only one cache; with neither, both are warmed. Both caches
+are kept for a day.
+
+Examples:
+  slack-reader cache warm --workspace myteam
+  slack-reader cache warm --workspace myteam --users`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !warmUsers && !warmChannels {
+			warmUsers, warmChannels = true, true
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		if warmUsers {
+			users, err := islack.RefreshUsers(ctx, client)
+			if err != nil {
+				output.PrintError(err)
+			}
+			fmt.Printf("Cached %d users.\n", len(users))
+		}
+		if warmChannels {
+			n, err := islack.WarmChannels(ctx, client)
+			if err != nil {
+				output.PrintError(err)
+			}
+			fmt.Printf("Cached %d channels.\n", n)
+		}
+	},
+}
+
+func init() {
+	cacheWarmCmd.Flags().BoolVar(&warmUsers, "users", false, "Warm the member directory")
+	cacheWarmCmd.Flags().BoolVar(&warmChannels, "channels", false, "Warm channel names")
+	cacheClearCmd.Flags().BoolVar(&clearAll, "all", false, "Clear the caches of every workspace")
+
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheWarmCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
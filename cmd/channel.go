@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"iter"
 
 	"github.com/sethrylan/slack-reader/internal/output"
 	islack "github.com/sethrylan/slack-reader/internal/slack"
@@ -9,9 +11,29 @@ import (
 )
 
 var (
-	channelUser  string
-	channelAll   bool
-	channelLimit int
+	channelUser     string
+	channelAll      bool
+	channelLimit    int
+	channelOutput   string
+	channelTypes    []string
+	channelSort     string
+	channelMatch    string
+	channelRegex    string
+	channelMemberOf []string
+
+	channelEnrich      bool
+	channelLastMessage bool
+	channelSidebar     bool
+	unmutedOnly        bool
+
+	canvasOutput string
+
+	unreadsFetch  bool
+	unreadsLimit  int
+	unreadsOutput string
+
+	membersOutput string
+	guestsOnly    bool
 )
 
 var channelCmd = &cobra.Command{
@@ -24,48 +46,428 @@ var channelListCmd = &cobra.Command{
 	Short: "List conversations",
 	Long: `List conversations for the current user (default), a specific user, or all workspace conversations.
 
+Archived conversations are left out unless --include-archived is set; they are
+marked with "is_archived": true.
+
+Without --sort, one page of results is returned in Slack's order. With --sort, every
+conversation is fetched and sorted before --limit is applied: by name, members (most
+first), created (newest first), or recent-activity (most recently active first, from
+--last-message when set, else the latest and updated fields Slack returns).
+
+--match (a glob, e.g. "incident-*") and --regex filter channels by name as pages are
+fetched, so --limit counts matching channels.
+
+--member-of lists only the channels that all of the given users belong to (among those
+you can see), to find where two teams already talk.
+
+--enrich adds creator_name, topic_text, purpose_text, and num_members (with a
+conversations.info call per channel when the listing lacks it). --last-message adds
+last_message_ts and last_message_at, probing each channel's history.
+
+Group DMs gain display_name, their participants' names ("alice, bob, carol"), in place
+of the opaque mpdm-alice--bob--carol-1 name.
+
+--sidebar adds your sidebar arrangement: the "section" each conversation is filed under
+and whether it is "muted". --unmuted-only leaves out the conversations you have muted.
+
 Examples:
   slack-reader channel list --workspace myteam
   slack-reader channel list --workspace myteam --user "@alice" --limit 50
-  slack-reader channel list --workspace myteam --all --limit 100`,
+  slack-reader channel list --workspace myteam --all --limit 100
+  slack-reader channel list --workspace myteam --all --include-archived
+  slack-reader channel list --workspace myteam --types public,private
+  slack-reader channel list --workspace myteam --all --sort members --limit 20
+  slack-reader channel list --workspace myteam --all --match "incident-*" --output text
+  slack-reader channel list --workspace myteam --all --regex "^team-(web|api)-"
+  slack-reader channel list --workspace myteam --enrich --last-message --sort recent-activity
+  slack-reader channel list --workspace myteam --member-of "@alice,@bob"
+  slack-reader channel list --workspace myteam --sidebar --unmuted-only
+  slack-reader channel list --workspace myteam --output text`,
 	Run: func(_ *cobra.Command, _ []string) {
-		domain := requireWorkspace()
-		client, err := islack.NewClient(domain)
+		types, err := islack.ParseConversationTypes(channelTypes)
 		if err != nil {
-			output.PrintError(err)
+			output.Exit(err, output.ExitUsage)
 		}
+		opts := islack.ConversationOptions{IncludeArchived: includeArchived, Types: types}
 
-		ctx := context.Background()
-		var resp map[string]any
+		if channelSort != "" {
+			// Validate before any API calls.
+			if err := islack.SortConversations(nil, channelSort); err != nil {
+				output.Exit(err, output.ExitUsage)
+			}
+		}
+		match, err := islack.MatchChannelNames(channelMatch, channelRegex)
+		if err != nil {
+			output.Exit(err, output.ExitUsage)
+		}
 
-		switch {
-		case channelAll:
-			resp, err = islack.ListAllConversations(ctx, client, channelLimit, "")
-		case channelUser != "":
+		client := newClient()
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		var sidebar *islack.Sidebar
+		if channelSidebar || unmutedOnly {
+			if sidebar, err = islack.GetSidebar(ctx, client); err != nil {
+				output.PrintError(err)
+			}
+		}
+		if unmutedOnly {
+			matchName := match
+			match = func(c map[string]any) bool {
+				id, _ := c["id"].(string)
+				return !sidebar.Muted[id] && matchName(c)
+			}
+		}
+
+		userID := ""
+		if channelUser != "" {
 			// Resolve @handle to user ID
-			userID, resolveErr := islack.ResolveUserID(ctx, client, channelUser)
-			if resolveErr != nil {
-				output.PrintError(resolveErr)
+			if userID, err = islack.ResolveUserID(ctx, client, channelUser); err != nil {
+				output.PrintError(err)
+			}
+		}
+
+		var resp map[string]any
+		switch {
+		case len(channelMemberOf) > 0:
+			userIDs := make([]string, len(channelMemberOf))
+			for i, handle := range channelMemberOf {
+				if userIDs[i], err = islack.ResolveUserID(ctx, client, handle); err != nil {
+					output.PrintError(err)
+				}
+			}
+			resp, err = collectChannels(ctx, client, islack.IterSharedConversations(ctx, client, userIDs, opts), match)
+		case channelSort != "" || channelMatch != "" || channelRegex != "" || unmutedOnly:
+			seq := islack.IterUserConversations(ctx, client, userID, opts)
+			if channelAll {
+				seq = islack.IterAllConversations(ctx, client, opts)
 			}
-			resp, err = islack.ListUserConversations(ctx, client, userID, channelLimit, "")
+			resp, err = collectChannels(ctx, client, seq, match)
+		case channelAll:
+			resp, err = islack.ListAllConversations(ctx, client, channelLimit, "", opts)
 		default:
-			resp, err = islack.ListUserConversations(ctx, client, "", channelLimit, "")
+			resp, err = islack.ListUserConversations(ctx, client, userID, channelLimit, "", opts)
 		}
 
 		if err != nil {
 			output.PrintError(err)
 		}
+		if channelSort == "" {
+			// With --sort, collectChannels enriches before sorting instead.
+			channels, _ := resp["channels"].([]any)
+			enrichChannels(ctx, client, channels)
+		}
+		if channelOutput != "text" {
+			addGroupDMNames(ctx, client, resp)
+		}
+		if channelSidebar {
+			channels, _ := resp["channels"].([]any)
+			for _, c := range channels {
+				if channel, _ := c.(map[string]any); channel != nil {
+					sidebar.Annotate(channel)
+				}
+			}
+		}
+
+		if channelOutput == "text" {
+			// One ID per line, for piping into --stdin-channels.
+			channels, _ := resp["channels"].([]any)
+			for _, c := range channels {
+				channel, _ := c.(map[string]any)
+				if id, _ := channel["id"].(string); id != "" {
+					fmt.Println(id)
+				}
+			}
+			return
+		}
 
 		output.PrintJSON(resp)
 	},
 }
 
+var channelInfoCmd = &cobra.Command{
+	Use:   "info <channel>",
+	Short: "Show a conversation's metadata",
+	Long: `Show a conversation's metadata (conversations.info), with the same --enrich and
+--last-message fields as channel list.
+
+Examples:
+  slack-reader channel info "#general" --workspace myteam
+  slack-reader channel info C0123ABC --workspace myteam --enrich --last-message`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, err := islack.ResolveChannelID(ctx, client, args[0])
+		if err != nil {
+			output.PrintError(err)
+		}
+		channel, err := islack.GetChannelInfo(ctx, client, channelID)
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+		enrichChannels(ctx, client, []any{channel})
+		output.PrintJSON(map[string]any{"channel": channel})
+	},
+}
+
+var channelCanvasCmd = &cobra.Command{
+	Use:   "canvas <channel>",
+	Short: "Print a channel's canvas as markdown",
+	Long: `Print a channel's canvas. The canvas is located with conversations.info (the channel
+canvas, else the first canvas tab), and its content downloaded and converted to markdown.
+
+--output html prints the canvas as Slack serves it; --output json prints its file ID,
+title, update time, and link along with the HTML.
+
+Examples:
+  slack-reader channel canvas "#oncall" --workspace myteam
+  slack-reader channel canvas "#oncall" --workspace myteam --output html > runbook.html`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if canvasOutput != "markdown" && canvasOutput != "html" && canvasOutput != "json" {
+			output.Exit(fmt.Errorf("invalid --output %q (want markdown, html, or json)", canvasOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, err := islack.ResolveChannelID(ctx, client, args[0])
+		if err != nil {
+			output.PrintError(err)
+		}
+		canvas, err := islack.GetChannelCanvas(ctx, client, channelID)
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+
+		switch canvasOutput {
+		case "html":
+			fmt.Print(canvas.HTML)
+		case "json":
+			output.PrintJSON(map[string]any{"channel_id": channelID, "canvas": canvas})
+		default:
+			md, err := output.CanvasMarkdown(canvas.HTML)
+			if err != nil {
+				output.PrintError(err)
+			}
+			fmt.Print(md)
+		}
+	},
+}
+
+var channelMembersCmd = &cobra.Command{
+	Use:   "members <channel>",
+	Short: "List a conversation's members, flagging guests",
+	Long: `List a conversation's members (conversations.members), each with their names, team, and
+whether they are a guest: "multi_channel" for multi-channel guests (is_restricted) or
+"single_channel" for single-channel guests (is_ultra_restricted). Members are looked up
+in the cached user directory, and with users.info when not found there.
+
+--guests lists only guests. --output text prints one member per line: ID, @name, and
+guest kind, tab-separated.
+
+Examples:
+  slack-reader channel members "#shared-vendor" --workspace myteam
+  slack-reader channel members "#shared-vendor" --workspace myteam --guests --output text`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if membersOutput != "json" && membersOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", membersOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, err := islack.ResolveChannelID(ctx, client, args[0])
+		if err != nil {
+			output.PrintError(err)
+		}
+		members, err := islack.ChannelMembers(ctx, client, channelID)
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+
+		guests := 0
+		kept := members[:0]
+		for _, m := range members {
+			if m.Guest != "" {
+				guests++
+			}
+			if m.Guest != "" || !guestsOnly {
+				kept = append(kept, m)
+			}
+		}
+		members = kept
+
+		if membersOutput == "text" {
+			for _, m := range members {
+				fmt.Printf("%s\t@%s\t%s\n", m.ID, m.Name, m.Guest)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"members":    members,
+			"guests":     guests,
+		})
+	},
+}
+
+var channelUnreadsCmd = &cobra.Command{
+	Use:   "unreads",
+	Short: "List conversations with unread messages",
+	Long: `List your conversations with unread messages, most unread first, with unread and
+mention counts and the last_read marker (client.counts, then conversations.info for each).
+Unread thread replies are not counted.
+
+--fetch also pulls each conversation's unread messages (those after last_read), at most
+--limit per conversation. --output text prints one conversation and its count per line.
+
+Examples:
+  slack-reader channel unreads --workspace myteam
+  slack-reader channel unreads --workspace myteam --output text
+  slack-reader channel unreads --workspace myteam --fetch --limit 50`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if unreadsOutput != "json" && unreadsOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", unreadsOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		unreads, err := islack.ListUnreads(ctx, client, islack.UnreadOptions{Fetch: unreadsFetch, Limit: unreadsLimit})
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if unreadsOutput == "text" {
+			for _, u := range unreads {
+				name := u.ChannelID
+				switch {
+				case u.Name != "":
+					name = "#" + u.Name
+				case u.User != "":
+					name = "@" + u.User
+				}
+				fmt.Printf("%s\t%d\n", name, u.UnreadCount)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{"channels": unreads})
+	},
+}
+
+// collectChannels lists conversations page by page, keeping those that match. Without
+// --sort it stops at --limit matches; with --sort it fetches every page, then sorts and truncates.
+func collectChannels(ctx context.Context, client *islack.Client, seq iter.Seq2[map[string]any, error], match func(map[string]any) bool) (map[string]any, error) {
+	var channels []map[string]any
+	for c, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		if !match(c) {
+			continue
+		}
+		channels = append(channels, c)
+		if channelSort == "" && channelLimit > 0 && len(channels) >= channelLimit {
+			break
+		}
+	}
+
+	list := make([]any, len(channels))
+	for i, c := range channels {
+		list[i] = c
+	}
+	if channelSort != "" {
+		// Enrich first, so member counts and last messages can inform the order.
+		enrichChannels(ctx, client, list)
+		if err := islack.SortConversations(channels, channelSort); err != nil {
+			return nil, err
+		}
+		for i, c := range channels {
+			list[i] = c
+		}
+	}
+	if channelLimit > 0 && len(list) > channelLimit {
+		list = list[:channelLimit]
+	}
+	return map[string]any{"channels": list}, nil
+}
+
+// enrichChannels adds resolved and probed fields to listed channels, per --enrich and --last-message.
+// addGroupDMNames adds display_name, the participants, to the group DMs in a listing.
+func addGroupDMNames(ctx context.Context, client *islack.Client, resp map[string]any) {
+	list, _ := resp["channels"].([]any)
+	channels := make([]map[string]any, 0, len(list))
+	for _, c := range list {
+		if channel, _ := c.(map[string]any); channel != nil {
+			channels = append(channels, channel)
+		}
+	}
+	if err := islack.AddGroupDMNames(ctx, client, islack.NewUserProvider(client), channels); err != nil {
+		output.PrintError(err)
+	}
+}
+
+func enrichChannels(ctx context.Context, client *islack.Client, list []any) {
+	if !channelEnrich && !channelLastMessage {
+		return
+	}
+	channels := make([]map[string]any, 0, len(list))
+	for _, c := range list {
+		if channel, _ := c.(map[string]any); channel != nil {
+			channels = append(channels, channel)
+		}
+	}
+	opts := islack.EnrichOptions{MemberCounts: channelEnrich, LastMessage: channelLastMessage}
+	if err := islack.EnrichChannels(ctx, client, islack.NewUserProvider(client), channels, opts); err != nil {
+		output.PrintError(err)
+	}
+}
+
 func init() {
 	channelListCmd.Flags().StringVar(&channelUser, "user", "", "List conversations for a specific user (e.g., \"@alice\")")
 	channelListCmd.Flags().BoolVar(&channelAll, "all", false, "List all workspace conversations (conversations.list)")
 	channelListCmd.Flags().IntVar(&channelLimit, "limit", 100, "Maximum number of results")
-	channelListCmd.MarkFlagsMutuallyExclusive("user", "all")
+	channelListCmd.Flags().StringSliceVar(&channelTypes, "types", nil, "Conversation types to list, comma-separated: public, private, im, mpim (default all)")
+	channelListCmd.Flags().StringVar(&channelSort, "sort", "", "Sort all results before --limit: name, members, created, or recent-activity")
+	channelListCmd.Flags().StringVar(&channelMatch, "match", "", "Only list channels whose name matches a glob pattern (e.g., \"incident-*\")")
+	channelListCmd.Flags().StringVar(&channelRegex, "regex", "", "Only list channels whose name matches a regular expression")
+	channelListCmd.Flags().BoolVar(&channelEnrich, "enrich", false, "Add creator name, topic and purpose text, and member counts")
+	channelListCmd.Flags().BoolVar(&channelLastMessage, "last-message", false, "Add each channel's last message time (one history call per channel)")
+	channelListCmd.Flags().StringVarP(&channelOutput, "output", "o", "json", "Output format: json or text (one channel ID per line)")
+	channelListCmd.Flags().StringSliceVar(&channelMemberOf, "member-of", nil, "Only channels that all of these users belong to, comma-separated (e.g., \"@alice,@bob\")")
+	channelListCmd.Flags().BoolVar(&channelSidebar, "sidebar", false, "Add each conversation's sidebar section and muted state")
+	channelListCmd.Flags().BoolVar(&unmutedOnly, "unmuted-only", false, "Leave out conversations you have muted")
+	channelListCmd.MarkFlagsMutuallyExclusive("user", "all", "member-of")
+
+	channelInfoCmd.Flags().BoolVar(&channelEnrich, "enrich", false, "Add creator name, topic and purpose text, and member count")
+	channelInfoCmd.Flags().BoolVar(&channelLastMessage, "last-message", false, "Add the channel's last message time")
 
 	channelCmd.AddCommand(channelListCmd)
+	channelCanvasCmd.Flags().StringVarP(&canvasOutput, "output", "o", "markdown", "Output format: markdown, html, or json")
+
+	channelUnreadsCmd.Flags().BoolVar(&unreadsFetch, "fetch", false, "Also fetch each conversation's unread messages")
+	channelUnreadsCmd.Flags().IntVar(&unreadsLimit, "limit", 0, "Maximum unread messages fetched per conversation (0 = all)")
+	channelUnreadsCmd.Flags().StringVarP(&unreadsOutput, "output", "o", "json", "Output format: json or text (conversation and unread count per line)")
+
+	channelCmd.AddCommand(channelInfoCmd)
+	channelMembersCmd.Flags().BoolVar(&guestsOnly, "guests", false, "Only list guests")
+	channelMembersCmd.Flags().StringVarP(&membersOutput, "output", "o", "json", "Output format: json or text (one member per line)")
+
+	channelCmd.AddCommand(channelUnreadsCmd)
+	channelCmd.AddCommand(channelMembersCmd)
+	channelCmd.AddCommand(channelCanvasCmd)
 	rootCmd.AddCommand(channelCmd)
 }
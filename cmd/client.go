@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// newClient creates an authenticated client for --workspace, honoring the global flags.
+func newClient() *islack.Client {
+	client, err := islack.NewClient(requireWorkspace(), clientOptions()...)
+	if err != nil {
+		output.PrintError(err)
+	}
+	return client
+}
+
+func clientOptions() []islack.Option {
+	opts := transportOptions()
+	if !noCache {
+		opts = append(opts, islack.WithResponseCache(cacheTTL))
+	}
+	if nonInteractive {
+		opts = append(opts, islack.WithEnvAuthOnly())
+	}
+	if fuzzyChannels {
+		opts = append(opts, islack.WithFuzzyChannelNames())
+	}
+	if includeArchived {
+		opts = append(opts, islack.WithArchivedChannels())
+	}
+	return opts
+}
+
+// transportOptions returns the HTTP settings from the global flags.
+func transportOptions() []islack.Option {
+	opts := []islack.Option{
+		islack.WithStats(&apiStats),
+		islack.WithRequestTimeout(requestTimeout),
+		islack.WithMaxRetries(maxRetries),
+		islack.WithMaxIdleConns(maxIdleConns),
+	}
+	if debugHTTP {
+		opts = append(opts, islack.WithHTTPTrace())
+	}
+	if caCert != "" {
+		cfg, err := tlsConfigWithCA(caCert)
+		if err != nil {
+			output.PrintError(err)
+		}
+		opts = append(opts, islack.WithTLSConfig(cfg))
+	}
+	return opts
+}
+
+// tlsConfigWithCA trusts the PEM certificates in path in addition to the system roots.
+func tlsConfigWithCA(path string) (*tls.Config, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read --ca-cert: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in --ca-cert %s", path)
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// commandContext returns the context for a command, bounded by --timeout if set. Calls
+// cut short by the deadline fail with an error naming --timeout (exit code 6). The
+// deadline starts here, so interactive prompts belong before it.
+func commandContext() (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	errTimeout := fmt.Errorf("timed out after %s (--timeout): %w", timeout, context.DeadlineExceeded)
+	return context.WithTimeoutCause(context.Background(), timeout, errTimeout)
+}
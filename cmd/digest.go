@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/digest"
+	"github.com/sethrylan/slack-reader/internal/filter"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestChannels []string
+	digestSince    string
+	digestUntil    string
+	digestOutput   string
+	digestOutDir   string
+	digestMailFrom string
+	digestMailTo   string
+	digestSumCmd   string
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize recent channel activity as markdown or email",
+	Long: `Collect each channel's messages over a period into a digest.
+
+With --output markdown (the default), digests are printed to stdout. With --output eml or mbox,
+one email file per channel is written to --out-dir, with HTML and plain-text bodies, ready to
+hand to a mail pipeline.
+
+--since and --until accept a duration before now (24h, 7d), a date (2026-01-31),
+an RFC 3339 time, or a Slack timestamp.
+
+System messages (joins, leaves, topic changes, integrations added) are left out unless
+--show-system is given.
+
+--subtype and --exclude-subtype keep or leave out messages by subtype (e.g., bot_message).
+
+--unmuted-only skips the channels you have muted in Slack, so a standing --channels list
+follows your own triage.
+
+--summarize-cmd pipes each channel's transcript (markdown) to a command, such as an LLM CLI,
+and puts its output in a summary section at the top of the digest.
+
+Examples:
+  slack-reader digest --workspace myteam --channels "#eng,#ops" --since 24h
+  slack-reader digest --workspace myteam --channels "#eng,#ops" --since 24h --output mbox --out-dir ./digests
+  slack-reader digest --workspace myteam --channels "#eng" --since 7d --output eml --mail-to team@example.com
+  slack-reader digest --workspace myteam --channels "#eng,#ops,#random" --since 24h --unmuted-only
+  slack-reader digest --workspace myteam --channels "#eng" --since 24h --summarize-cmd "llm -s 'Summarize this Slack channel'"`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if len(digestChannels) == 0 {
+			output.PrintError(errors.New("--channels is required"))
+		}
+		if digestOutput != "markdown" && digestOutput != "eml" && digestOutput != "mbox" {
+			output.PrintError(fmt.Errorf("invalid --output %q (want markdown, eml, or mbox)", digestOutput))
+		}
+
+		now := time.Now()
+		since, err := islack.ParseTimeSpec(digestSince, now)
+		if err != nil {
+			output.PrintError(fmt.Errorf("--since: %w", err))
+		}
+		until := now
+		if digestUntil != "" {
+			if until, err = islack.ParseTimeSpec(digestUntil, now); err != nil {
+				output.PrintError(fmt.Errorf("--until: %w", err))
+			}
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		users := islack.NewUserProvider(client)
+		if labelExternal {
+			labelExternalUsers(ctx, client, users)
+		}
+		var muted map[string]bool
+		if unmutedOnly {
+			if muted, err = islack.MutedConversations(ctx, client); err != nil {
+				output.PrintError(err)
+			}
+		}
+
+		var files []string
+		for _, input := range digestChannels {
+			channelID, channelName := resolveChannel(ctx, client, input)
+			if muted[channelID] {
+				slog.Info("skipping muted channel", "channel", input)
+				continue
+			}
+
+			seq := islack.IterChannelHistory(ctx, client, channelID, islack.HistoryOptions{
+				Oldest: islack.FormatTimestamp(since),
+				Latest: islack.FormatTimestamp(until),
+			})
+			if systemHidden() {
+				seq = islack.FilterMessages(seq, func(msg map[string]any) bool { return !islack.IsSystemMessage(msg) }, 0)
+			}
+			messages, err := islack.CollectMessages(subtypeFilter(seq))
+			if err != nil {
+				islack.InvalidateChannelOnError(client, input, err)
+				output.PrintError(err)
+			}
+			filterMessages(ctx, messages)
+			users.Prefetch(ctx, messages)
+
+			d := &digest.Digest{
+				Channel:  cmp.Or(groupDMDisplayName(ctx, client, channelID, channelName), channelHeading(channelID, channelName)),
+				Since:    since,
+				Until:    until,
+				Messages: messages,
+			}
+			if digestSumCmd != "" && len(messages) > 0 {
+				if d.Summary, err = summarize(ctx, d, users); err != nil {
+					output.PrintError(err)
+				}
+			}
+
+			if digestOutput == "markdown" {
+				md, err := d.Markdown(users)
+				if err != nil {
+					output.PrintError(err)
+				}
+				fmt.Println(md)
+				continue
+			}
+
+			path, err := writeDigest(d, channelID, channelName, now, users)
+			if err != nil {
+				output.PrintError(err)
+			}
+			files = append(files, path)
+		}
+
+		if digestOutput != "markdown" {
+			output.PrintJSON(map[string]any{"files": files})
+		}
+	},
+}
+
+// summarize pipes the digest's transcript through --summarize-cmd.
+func summarize(ctx context.Context, d *digest.Digest, users *islack.UserProvider) (string, error) {
+	transcript, err := d.Transcript(users)
+	if err != nil {
+		return "", err
+	}
+	summary, err := filter.Pipe(ctx, digestSumCmd, []byte(transcript))
+	if err != nil {
+		return "", fmt.Errorf("--summarize-cmd for %s: %w", d.Channel, err)
+	}
+	return summary, nil
+}
+
+// writeDigest writes d to --out-dir as an .eml or .mbox file and returns its path.
+func writeDigest(d *digest.Digest, channelID, channelName string, now time.Time, users *islack.UserProvider) (string, error) {
+	if err := os.MkdirAll(digestOutDir, 0o750); err != nil {
+		return "", fmt.Errorf("create --out-dir: %w", err)
+	}
+	base := channelName
+	if base == "" {
+		base = channelID
+	}
+	path := filepath.Join(digestOutDir, fmt.Sprintf("%s-%s.%s", base, now.UTC().Format("2006-01-02"), digestOutput))
+
+	f, err := os.Create(path) //nolint:gosec // the path is built from --out-dir and a channel name
+	if err != nil {
+		return "", fmt.Errorf("create digest: %w", err)
+	}
+	defer f.Close()
+
+	env := digest.Envelope{From: digestMailFrom, To: digestMailTo, Date: now}
+	if digestOutput == "mbox" {
+		err = d.WriteMbox(f, env, users)
+	} else {
+		err = d.WriteEML(f, env, users)
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func init() {
+	digestCmd.Flags().StringSliceVar(&digestChannels, "channels", nil, "Channels to digest, comma-separated (required)")
+	digestCmd.Flags().StringVar(&digestSince, "since", "24h", "Start of the period")
+	digestCmd.Flags().StringVar(&digestUntil, "until", "", "End of the period (default now)")
+	digestCmd.Flags().StringVar(&digestOutput, "output", "markdown", "Output format: markdown, eml, or mbox")
+	digestCmd.Flags().StringVar(&digestOutDir, "out-dir", ".", "Directory for eml/mbox files")
+	digestCmd.Flags().StringVar(&digestMailFrom, "mail-from", "slack-reader <slack-reader@localhost>", "From address for eml/mbox output")
+	digestCmd.Flags().StringVar(&digestMailTo, "mail-to", "undisclosed-recipients:;", "To address for eml/mbox output")
+	digestCmd.Flags().BoolVar(&unmutedOnly, "unmuted-only", false, "Skip channels you have muted")
+	addSystemFlags(digestCmd.Flags())
+	digestCmd.Flags().StringSliceVar(&subtypes, "subtype", nil, "Only include messages of these subtypes, comma-separated (e.g., bot_message)")
+	digestCmd.Flags().StringSliceVar(&excludeSubtypes, "exclude-subtype", nil, "Leave out messages of these subtypes, comma-separated")
+	digestCmd.Flags().BoolVar(&labelExternal, "label-external", false, "Label users from other organizations (Slack Connect) with their team name")
+	addExecFilterFlag(digestCmd.Flags())
+	digestCmd.Flags().StringVar(&digestSumCmd, "summarize-cmd", "", "Shell command the transcript is piped through; its output is added as a summary at the top")
+	rootCmd.AddCommand(digestCmd)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/export"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut    string
+	exportFormat string
+	exportSince  string
+	exportUntil  string
+	exportStable bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <channel>...",
+	Short: "Export channels in the layout of Slack's workspace export",
+	Long: `Export channels' messages (thread replies included) and the workspace's users to
+--out, in the directory layout and JSON of Slack's own workspace export, so that tools
+which read official exports can read this one:
+
+  channels.json, groups.json   public and private channels, with their members
+  mpims.json, dms.json         group DMs and DMs
+  users.json                   the workspace's members
+  <channel>/<YYYY-MM-DD>.json  each day's messages (UTC); a DM's directory is its ID
+
+--since and --until accept a duration before now (24h, 7d), a date (2026-01-31),
+an RFC 3339 time, or a Slack timestamp; without them, the whole history is exported.
+Files already in --out are overwritten, and others are left in place.
+
+With --deterministic, re-exporting writes byte-identical files unless messages were posted,
+edited, or deleted, so that an export kept in git diffs cleanly: your own read state and
+the times Slack last touched channels and users (last_read, unread counts, updated,
+presence) are left out, members are sorted, and --since is moved back to midnight UTC so
+that the first day's file is whole. Files are always named by channel and day, messages
+ordered by timestamp, and JSON keys sorted.
+
+--out can also be an s3:// or gs:// URL, to stream each file straight to object storage
+through the aws or gcloud CLI (installed and authenticated), which uploads large files
+in parts; nothing is staged on local disk.
+
+With --stdin-channels, channels are also read from stdin (one per line). In a terminal,
+omitting the channel opens a picker of known channels.
+
+Examples:
+  slack-reader export "#general" --workspace myteam --out ./export
+  slack-reader export "#eng" "#ops" --workspace myteam --out ./export --since 30d
+  slack-reader export "#general" --workspace myteam --out ./export --since 2026-01-01 --until 2026-04-01
+  slack-reader export "#eng" --workspace myteam --out s3://backups/slack/ --since 1d
+  slack-reader export "#eng" --workspace myteam --out ./slack-archive --since 7d --deterministic`,
+	Args: channelArgs(cobra.MinimumNArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if exportOut == "" {
+			output.Exit(errors.New("--out is required"), output.ExitUsage)
+		}
+		if exportFormat != "slack-export" {
+			output.Exit(fmt.Errorf("invalid --format %q (want slack-export)", exportFormat), output.ExitUsage)
+		}
+		opts := export.Options{Deterministic: exportStable}
+		now := time.Now()
+		if exportSince != "" {
+			since, err := islack.ParseTimeSpec(exportSince, now)
+			if err != nil {
+				output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+			}
+			opts.Oldest = islack.FormatTimestamp(since)
+		}
+		if exportUntil != "" {
+			until, err := islack.ParseTimeSpec(exportUntil, now)
+			if err != nil {
+				output.Exit(fmt.Errorf("--until: %w", err), output.ExitUsage)
+			}
+			opts.Latest = islack.FormatTimestamp(until)
+		}
+
+		client := newClient()
+		inputs, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelIDs := make([]string, 0, len(inputs))
+		for _, input := range inputs {
+			channelID, err := islack.ResolveChannelID(ctx, client, input)
+			if err != nil {
+				output.PrintError(err)
+			}
+			channelIDs = append(channelIDs, channelID)
+		}
+		users, err := islack.ListUsers(ctx, client)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		dest, err := export.NewDestination(ctx, exportOut)
+		if err != nil {
+			output.Exit(fmt.Errorf("--out: %w", err), output.ExitUsage)
+		}
+		result, err := export.Export(ctx, client, dest, channelIDs, users, opts)
+		if err == nil {
+			err = dest.Close()
+		}
+		if err != nil {
+			output.PrintError(err)
+		}
+		output.PrintJSON(result)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "Directory, or s3:// or gs:// URL, to export to (required)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "slack-export", "Export format: slack-export (Slack's workspace export layout)")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "Only messages after this time (default: the whole history)")
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "Only messages before this time (default now)")
+	exportCmd.Flags().BoolVar(&exportStable, "deterministic", false, "Leave out volatile fields, so that re-exports are identical for version control")
+	exportCmd.Flags().BoolVar(&stdinChannels, "stdin-channels", false, "Also read channels from stdin, one per line")
+	rootCmd.AddCommand(exportCmd)
+}
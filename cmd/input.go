@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sethrylan/slack-reader/internal/picker"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+// stdinChannels makes channel-taking commands read additional channels from stdin.
+var stdinChannels bool
+
+// channelArgs validates positional args with validate, unless channels may come from
+// elsewhere: stdin with --stdin-channels, or the picker when the channel is omitted in a terminal.
+func channelArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if stdinChannels || (len(args) == 0 && interactive()) {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+}
+
+// channelInputs returns the channel args, followed by those read from stdin with --stdin-channels.
+// With no channels at all in a terminal, the user picks one; call it before commandContext,
+// so that the time the user takes does not count toward --timeout.
+func channelInputs(client *islack.Client, args []string) ([]string, error) {
+	if stdinChannels {
+		lines, err := readLines(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read channels from stdin: %w", err)
+		}
+		args = append(args, lines...)
+	}
+	if len(args) > 0 {
+		return args, nil
+	}
+	if !interactive() {
+		return nil, errors.New("no channels given on the command line or stdin")
+	}
+
+	channelID, err := pickChannel(client)
+	if err != nil {
+		return nil, err
+	}
+	return []string{channelID}, nil
+}
+
+// linkTimestamps returns the message and thread timestamps of a message link given as a
+// channel, so that the link can stand in for --ts and --thread-ts.
+func linkTimestamps(input string) (ts, threadTS string) {
+	_, ts, threadTS, _ = islack.ParseArchiveURL(strings.TrimSpace(input))
+	return ts, threadTS
+}
+
+// workspaceFromLinks defaults --workspace to the workspace of the first Slack link in
+// args, so a pasted message link is enough on its own.
+func workspaceFromLinks(args []string) {
+	if workspace != "" {
+		return
+	}
+	for _, arg := range args {
+		if w, ok := islack.ParseWorkspaceURL(arg); ok {
+			workspace = w
+			return
+		}
+	}
+}
+
+// interactive reports whether the user can be prompted: stdin and stderr are terminals,
+// stdin isn't being used for input, and --non-interactive is not set.
+func interactive() bool {
+	return !nonInteractive && !stdinChannels &&
+		isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stderr.Fd())
+}
+
+// pickChannel lets the user choose among known channels, prompting on stderr. Only
+// listing the channels is bounded by --timeout; the prompt waits for the user.
+func pickChannel(client *islack.Client) (string, error) {
+	ctx, cancel := commandContext()
+	known, err := islack.KnownChannels(ctx, client)
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	items := make([]picker.Item, 0, len(known))
+	for name, id := range known {
+		items = append(items, picker.Item{Label: "#" + name, Value: id})
+	}
+	item, err := picker.Pick(os.Stdin, os.Stderr, "Channel", items)
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
+
+// userArgs validates positional args with validate, unless the user is omitted in a
+// terminal, where it is picked (see pickUser).
+func userArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && interactive() {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+}
+
+// userInput returns the user arg, or with none, lets the user pick one. Like
+// channelInputs, call it before commandContext.
+func userInput(client *islack.Client, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	items, err := userItems(client)
+	if err != nil {
+		return "", err
+	}
+	item, err := picker.Pick(os.Stdin, os.Stderr, "User", items)
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
+
+// referenceInput returns the reference arg of read, or with none, lets the user pick a
+// channel or a person to read DMs with.
+func referenceInput(client *islack.Client, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	ctx, cancel := commandContext()
+	known, err := islack.KnownChannels(ctx, client)
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	users, err := userItems(client)
+	if err != nil {
+		return "", err
+	}
+	items := make([]picker.Item, 0, len(known)+len(users))
+	for name := range known {
+		items = append(items, picker.Item{Label: "#" + name, Value: "#" + name})
+	}
+	items = append(items, users...)
+	item, err := picker.Pick(os.Stdin, os.Stderr, "Channel or person", items)
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
+
+// userItems returns the workspace's active people as picker items ("@alice (Alice Smith)",
+// valued "@alice"), from the member directory, which is cached for a day. Only listing
+// them is bounded by --timeout.
+func userItems(client *islack.Client) ([]picker.Item, error) {
+	ctx, cancel := commandContext()
+	members, err := islack.ListUsers(ctx, client)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	var items []picker.Item
+	for _, m := range members {
+		name, _ := m["name"].(string)
+		deleted, _ := m["deleted"].(bool)
+		bot, _ := m["is_bot"].(bool)
+		if name == "" || deleted || bot || m["id"] == "USLACKBOT" {
+			continue
+		}
+		label := "@" + name
+		profile, _ := m["profile"].(map[string]any)
+		if realName, _ := profile["real_name"].(string); realName != "" && realName != name {
+			label += " (" + realName + ")"
+		}
+		items = append(items, picker.Item{Label: label, Value: "@" + name})
+	}
+	return items, nil
+}
+
+// readTimestamps expands a "-" among the --ts values into the timestamps listed on stdin.
+func readTimestamps(values []string) ([]string, error) {
+	var tss []string
+	for _, v := range values {
+		if v != "-" {
+			tss = append(tss, v)
+			continue
+		}
+		lines, err := readLines(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read timestamps from stdin: %w", err)
+		}
+		tss = append(tss, lines...)
+	}
+	return tss, nil
+}
+
+// readLines returns the non-blank lines of r, trimmed.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -1,18 +1,70 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/sethrylan/slack-reader/internal/filter"
 	"github.com/sethrylan/slack-reader/internal/output"
+	"github.com/sethrylan/slack-reader/internal/report"
 	islack "github.com/sethrylan/slack-reader/internal/slack"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
-	messageTS     string
-	messageLimit  int
-	messageOutput string
+	messageTS       string
+	messageGetTS    []string
+	messageThreadTS string
+	messageLimit    int
+	messagePageSize int
+	messageOutput   string
+	validateChannel bool
+	noResolveUsers  bool
+	labelExternal   bool
+	huddlesOnly     bool
+	messageSince    string
+	messageUntil    string
+	historyWindow   islack.HistoryOptions // --since and --until, parsed
+	messageCursor   string
+	withReactions   bool
+	messageGrep     string
+	grepContext     int
+	grepPattern     *regexp.Regexp // --grep, compiled
+	onlyFiles       bool
+	onlyLinks       bool
+	hideSystem      bool
+	showSystem      bool
+	subtypes        []string
+	messageCount    bool
+	excludeSubtypes []string
+	execFilter      string
+	renderedText    bool
+	fileInfo        bool
+	authorTime      bool
+	avatarDir       string
+
+	messageIncludeThread bool
+	messageGetOutput     string
+
+	messageContextTS     string
+	contextBefore        int
+	contextAfter         int
+	messageContextOutput string
+
+	scheduledOutput string
 )
 
 var messageCmd = &cobra.Command{
@@ -25,92 +77,782 @@ var messageGetCmd = &cobra.Command{
 	Short: "Fetch a single message",
 	Long: `Fetch a single message by channel and timestamp.
 If the message is in a thread, includes thread metadata (reply count).
+Thread replies are found as well; pass --thread-ts (the parent's timestamp) if known.
+
+Repeat --ts (or pass --ts - to read timestamps from stdin, one per line) to fetch several
+messages at once; nearby timestamps share API calls, and the results are returned as an array.
+
+With --include-thread, a message that starts or belongs to a thread comes with the whole
+conversation (thread.messages: the parent, then every reply), so the result stands alone.
+--output markdown prints the message (or its whole thread) as message list does.
+
+The channel can also be a message link (Copy link in Slack), whose timestamps are used
+when --ts and --thread-ts are not given, and whose workspace is used without --workspace.
+
+In a terminal, omitting the channel opens a picker of known channels.
 
 Examples:
   slack-reader message get "#general" --workspace myteam --ts "1770165109.628379"
-  slack-reader message get C0123ABC --workspace myteam --ts "1770165109.628379"`,
-	Args: cobra.ExactArgs(1),
+  slack-reader message get "https://myteam.slack.com/archives/C0123ABC/p1770165109628379"
+  slack-reader message get "#general" --workspace myteam --ts "1770165200.000100" --thread-ts "1770165109.628379"
+  slack-reader message get "https://myteam.slack.com/archives/C0123ABC/p1770165109628379" --workspace myteam --include-thread -o markdown
+  slack-reader message get C0123ABC --workspace myteam --ts "1770165109.628379"
+  slack-reader message get "#general" --workspace myteam --ts "1770165109.628379" --ts "1770165300.000200"
+  cat timestamps.txt | slack-reader message get "#general" --workspace myteam --ts -`,
+	Args: channelArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if messageGetOutput != "json" && messageGetOutput != "markdown" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or markdown)", messageGetOutput), output.ExitUsage)
+		}
+		tss, err := readTimestamps(messageGetTS)
+		if err != nil {
+			output.PrintError(err)
+		}
+		if len(tss) == 0 && len(args) > 0 {
+			if ts, threadTS := linkTimestamps(args[0]); ts != "" {
+				tss = []string{ts}
+				if messageThreadTS == "" {
+					messageThreadTS = threadTS
+				}
+			}
+		}
+		if len(tss) == 0 {
+			output.PrintError(errors.New("--ts is required"))
+		}
+
+		workspaceFromLinks(args)
+		client := newClient()
+
+		inputs, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		input := inputs[0]
+		channelID, channelName := resolveChannel(ctx, client, input)
+
+		var results []*islack.MessageResult
+		if len(tss) > 1 {
+			results, err = islack.GetMessages(ctx, client, channelID, tss)
+		} else {
+			var result *islack.MessageResult
+			result, err = islack.GetMessage(ctx, client, channelID, tss[0], messageThreadTS)
+			results = []*islack.MessageResult{result}
+		}
+		if err != nil {
+			islack.InvalidateChannelOnError(client, input, err)
+			output.PrintError(err)
+		}
+
+		for _, r := range results {
+			if messageIncludeThread {
+				if err := islack.IncludeThread(ctx, client, channelID, r); err != nil {
+					output.PrintError(err)
+				}
+			}
+			addFileInfo(ctx, client, resultMessages(r))
+			filterMessages(ctx, resultMessages(r))
+		}
+
+		if messageGetOutput == "markdown" {
+			users := islack.NewUserProvider(client)
+			for _, r := range results {
+				msgs := resultMessages(r)
+				users.Prefetch(ctx, msgs)
+				output.PrintMarkdownWithOptions(msgs, users, markdownOptions(ctx, users, msgs))
+			}
+			return
+		}
+
+		if renderedText {
+			users := islack.NewUserProvider(client)
+			for _, r := range results {
+				addRendered(ctx, users, resultMessages(r))
+			}
+		}
+		if len(results) > 1 {
+			output.PrintJSON(map[string]any{
+				"channel":  channelName,
+				"messages": results,
+			})
+			return
+		}
+		results[0].Channel = channelName
+		output.PrintJSON(results[0])
+	},
+}
+
+// resultMessages returns the messages of a message get result: the included thread,
+// if any, or else the message itself.
+func resultMessages(r *islack.MessageResult) []map[string]any {
+	if thread, ok := r.Thread["messages"].([]map[string]any); ok {
+		return thread
+	}
+	return []map[string]any{r.Message}
+}
+
+var messageContextCmd = &cobra.Command{
+	Use:   "context <channel>",
+	Short: "Fetch a message with the conversation around it",
+	Long: `Fetch a message together with the messages posted just before and after it in the
+channel, so a search hit or a shared link can be read in its conversation.
+
+-B and -A set how many messages come before and after (10 each by default). The
+surrounding messages are marked "context": true in JSON; the result is oldest first.
+Only top-level messages have channel context; for a thread reply, use message list --ts.
+
+The channel can also be a message link (Copy link in Slack), whose timestamp is used
+when --ts is not given, and whose workspace is used without --workspace.
+
+Examples:
+  slack-reader message context "#general" --workspace myteam --ts "1770165109.628379"
+  slack-reader message context "https://myteam.slack.com/archives/C0123ABC/p1770165109628379" -B 5 -A 20
+  slack-reader message context C0123ABC --workspace myteam --ts "1770165109.628379" --output markdown`,
+	Args: channelArgs(cobra.ExactArgs(1)),
 	Run: func(_ *cobra.Command, args []string) {
-		if messageTS == "" {
+		if messageContextOutput != "json" && messageContextOutput != "markdown" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or markdown)", messageContextOutput), output.ExitUsage)
+		}
+		if contextBefore < 0 || contextAfter < 0 {
+			output.Exit(errors.New("--before and --after must not be negative"), output.ExitUsage)
+		}
+		if messageContextTS == "" && len(args) > 0 {
+			messageContextTS, _ = linkTimestamps(args[0])
+		}
+		if messageContextTS == "" {
 			output.PrintError(errors.New("--ts is required"))
 		}
 
-		domain := requireWorkspace()
-		client, err := islack.NewClient(domain)
+		workspaceFromLinks(args)
+		client := newClient()
+
+		inputs, err := channelInputs(client, args)
 		if err != nil {
 			output.PrintError(err)
 		}
 
-		ctx := context.Background()
-		channelID, err := islack.ResolveChannelID(ctx, client, args[0])
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		input := inputs[0]
+		channelID, channelName := resolveChannel(ctx, client, input)
+
+		messages, err := islack.MessageContext(ctx, client, channelID, messageContextTS, contextBefore, contextAfter)
 		if err != nil {
+			islack.InvalidateChannelOnError(client, input, err)
 			output.PrintError(err)
 		}
+		addFileInfo(ctx, client, messages)
+		filterMessages(ctx, messages)
 
-		result, err := islack.GetMessage(ctx, client, channelID, messageTS)
+		if messageContextOutput == "markdown" {
+			users := islack.NewUserProvider(client)
+			users.Prefetch(ctx, messages)
+			output.PrintMarkdownWithOptions(messages, users, markdownOptions(ctx, users, messages))
+			return
+		}
+		if renderedText {
+			addRendered(ctx, islack.NewUserProvider(client), messages)
+		}
+		output.PrintJSON(map[string]any{
+			"channel":  channelName,
+			"messages": messages,
+		})
+	},
+}
+
+var messageScheduledCmd = &cobra.Command{
+	Use:   "scheduled <channel>",
+	Short: "List messages scheduled to be posted in a channel",
+	Long: `List the messages you have scheduled in a channel that are not yet posted, soonest
+first, with when each will be posted. Nothing is changed.
+
+--output text prints a tab-separated table of post time, ID, and text.
+
+Examples:
+  slack-reader message scheduled "#general" --workspace myteam
+  slack-reader message scheduled "#general" --workspace myteam --output text`,
+	Args: channelArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if scheduledOutput != "json" && scheduledOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", scheduledOutput), output.ExitUsage)
+		}
+
+		workspaceFromLinks(args)
+		client := newClient()
+		inputs, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, channelName := resolveChannel(ctx, client, inputs[0])
+		scheduled, err := islack.ListScheduledMessages(ctx, client, channelID)
 		if err != nil {
+			islack.InvalidateChannelOnError(client, inputs[0], err)
 			output.PrintError(err)
 		}
 
-		output.PrintJSON(result)
+		if scheduledOutput == "text" {
+			for _, m := range scheduled {
+				fmt.Printf("%s\t%s\t%s\n", m.PostAt, m.ID, strings.ReplaceAll(m.Text, "\n", " "))
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"channel_id":         channelID,
+			"channel":            channelName,
+			"scheduled_messages": scheduled,
+		})
 	},
 }
 
 var messageListCmd = &cobra.Command{
-	Use:   "list <channel>",
-	Short: "List messages in a channel or thread",
-	Long: `List recent channel messages, or all messages in a thread by channel and thread root timestamp.
+	Use:   "list <channel>...",
+	Short: "List messages in channels or a thread",
+	Long: `List recent channel messages, or a thread's messages with --ts or a message link as the
+channel. Several channels are fetched concurrently and printed grouped in the order given;
+in a terminal, omitting the channel opens a picker of known channels. System messages
+(joins, topic changes, and the like) are left out unless --show-system is given.
 
 Examples:
   slack-reader message list "#general" --workspace myteam
   slack-reader message list "#general" --workspace myteam --limit 500
+  slack-reader message list "#general" --workspace myteam --since 7d --until 1d
+  slack-reader message list "#general" --workspace myteam --limit 1000 --cursor "bmV4dF90czoxNzcwMTY1MTA5NjI4Mzc5"
+  slack-reader message list "#eng" "#ops" --workspace myteam --limit 50 --output markdown
   slack-reader message list "#general" --workspace myteam --ts "1770165109.628379"
-  slack-reader message list C0123ABC --workspace myteam --ts "1770165109.628379" --output markdown`,
-	Args: cobra.ExactArgs(1),
+  slack-reader message list C0123ABC --workspace myteam --ts "1770165109.628379" --output markdown
+  slack-reader message list "https://myteam.slack.com/archives/C0123ABC/p1770165109628379" --workspace myteam
+  slack-reader message list "#general" --workspace myteam --output markdown --no-resolve-users
+  slack-reader message list "#general" --workspace myteam --output transcript > general.json
+  slack-reader message list "#general" --workspace myteam --output transcript --download-avatars avatars > general.json
+  slack-reader message list "#shared-acme" --workspace myteam --output markdown --label-external
+  slack-reader message list "#decisions" --workspace myteam --since 30d --with-reactions --output markdown
+  slack-reader message list "#eng" --workspace myteam --huddles-only --limit 20 --output markdown
+  slack-reader message list "#ops" --workspace myteam --since 7d --grep '(?i)rollback|revert' -C 2 --output markdown
+  slack-reader message list "#design" --workspace myteam --since 30d --only-files --only-links
+  slack-reader message list "#general" --workspace myteam --exec-filter 'jq -r .text | ./scrub-pii'
+  slack-reader channel list --workspace myteam --output text | slack-reader message list --workspace myteam --stdin-channels --limit 20`,
+	Args: channelArgs(cobra.MinimumNArgs(1)),
 	Run: func(_ *cobra.Command, args []string) {
-		domain := requireWorkspace()
-		client, err := islack.NewClient(domain)
-		if err != nil {
-			output.PrintError(err)
+		parseHistoryWindow()
+		parseGrep()
+		if avatarDir != "" && messageOutput != "transcript" {
+			output.Exit(errors.New("--download-avatars needs --output transcript"), output.ExitUsage)
 		}
+		workspaceFromLinks(args)
+		client := newClient()
 
-		ctx := context.Background()
-		channelID, err := islack.ResolveChannelID(ctx, client, args[0])
+		inputs, err := channelInputs(client, args)
 		if err != nil {
 			output.PrintError(err)
 		}
 
-		var messages []map[string]any
-		if messageTS == "" {
-			// No --ts: list recent channel messages
-			messages, err = islack.ListChannelHistory(ctx, client, channelID, messageLimit)
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		if messageTS == "" && len(inputs) == 1 {
+			ts, threadTS := linkTimestamps(inputs[0])
+			messageTS = cmp.Or(threadTS, ts)
+		}
+		if messageTS != "" && len(inputs) > 1 {
+			output.Exit(errors.New("--ts (a thread) can only be listed from one channel"), output.ExitUsage)
+		}
+		if messageCursor != "" && len(inputs) > 1 {
+			output.Exit(errors.New("--cursor can only continue one channel's listing"), output.ExitUsage)
+		}
+
+		channels := make([]*listedChannel, len(inputs))
+		for i, input := range inputs {
+			channelID, channelName := resolveChannel(ctx, client, input)
+			channels[i] = &listedChannel{input: input, id: channelID, name: channelName}
+			channels[i].groupDM = groupDMDisplayName(ctx, client, channelID, channelName)
+		}
+		fetchChannels(ctx, client, channels)
+		if messageCount {
+			printCounts(channels)
+			return
+		}
+
+		var users *islack.UserProvider
+		if messageOutput == "markdown" && noResolveUsers {
+			users = islack.NewUserProvider(nil)
 		} else {
-			// With --ts: list thread replies
-			messages, err = islack.ListThread(ctx, client, channelID, messageTS, messageLimit)
+			users = islack.NewUserProvider(client)
+			if labelExternal {
+				labelExternalUsers(ctx, client, users)
+			}
 		}
-		if err != nil {
-			output.PrintError(err)
+		if messageOutput == "transcript" {
+			users.CaptureAvatars()
 		}
 
-		if messageOutput == "markdown" {
-			users := islack.NewUserProvider(client)
-			output.PrintMarkdown(messages, users)
+		results := make([]map[string]any, 0, len(channels))
+		var transcripts []*output.Transcript
+		for _, c := range channels {
+			if withReactions {
+				if err := islack.AddReactionUsers(ctx, client, users, c.id, c.messages); err != nil {
+					output.PrintError(err)
+				}
+			}
+			addFileInfo(ctx, client, c.messages)
+			filterMessages(ctx, c.messages)
+
+			if messageOutput == "transcript" {
+				users.Prefetch(ctx, c.messages)
+				t, err := output.FormatTranscript(
+					output.TranscriptGuild{ID: workspace, Name: workspace},
+					output.TranscriptChannel{ID: c.id, Name: cmp.Or(c.groupDM, c.name)},
+					c.messages, users, time.Now())
+				if err != nil {
+					output.PrintError(err)
+				}
+				transcripts = append(transcripts, t)
+				continue
+			}
+
+			if messageOutput == "markdown" {
+				if len(channels) > 1 {
+					fmt.Printf("## %s\n\n", c.heading())
+				}
+				if noResolveUsers {
+					users.Seed(c.messages)
+				} else {
+					users.Prefetch(ctx, c.messages)
+				}
+				output.PrintMarkdownWithOptions(c.messages, users, markdownOptions(ctx, users, c.messages))
+				continue
+			}
+
+			if renderedText {
+				addRendered(ctx, users, c.messages)
+			}
+			results = append(results, map[string]any{
+				"channel_id":   c.id,
+				"channel":      c.name,
+				"display_name": c.groupDM,
+				"messages":     c.messages,
+				"next_cursor":  c.next,
+			})
+		}
+		switch messageOutput {
+		case "markdown", "transcript":
+			if messageOutput == "transcript" {
+				if avatarDir != "" {
+					mirrorAvatars(ctx, client, transcripts)
+				}
+				printTranscripts(transcripts)
+			}
+			for _, c := range channels {
+				if c.next != "" {
+					fmt.Fprintf(os.Stderr, "%s next_cursor: %s\n", channelHeading(c.id, c.name), c.next)
+				}
+			}
 			return
 		}
 
+		if len(channels) == 1 {
+			output.PrintJSON(map[string]any{
+				"channel":      results[0]["channel"],
+				"display_name": results[0]["display_name"],
+				"messages":     results[0]["messages"],
+				"next_cursor":  results[0]["next_cursor"],
+			})
+			return
+		}
 		output.PrintJSON(map[string]any{
-			"messages": messages,
+			"channels": results,
 		})
 	},
 }
 
+// listConcurrency bounds how many channels message list fetches at once.
+const listConcurrency = 4
+
+// listedChannel is one channel of a message list invocation.
+type listedChannel struct {
+	input    string // as given on the command line or stdin
+	id       string
+	name     string
+	messages []map[string]any
+	next     string // cursor to continue the listing from, "" if it reached the end
+	groupDM  string // participants, for a group DM
+}
+
+// heading names the channel in output: its participants for a group DM, else its #name or ID.
+func (c *listedChannel) heading() string {
+	return cmp.Or(c.groupDM, channelHeading(c.id, c.name))
+}
+
+// fetchChannels fetches each channel's messages concurrently, in place.
+func fetchChannels(ctx context.Context, client *islack.Client, channels []*listedChannel) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(listConcurrency)
+	for _, c := range channels {
+		g.Go(func() error {
+			var err error
+			c.messages, err = listMessages(ctx, client, c.id, &c.next)
+			if err != nil {
+				islack.InvalidateChannelOnError(client, c.input, err)
+				if len(channels) > 1 {
+					err = fmt.Errorf("%s: %w", channelHeading(c.id, c.name), err)
+				}
+				return err
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		output.PrintError(err)
+	}
+}
+
+// listMessages fetches a channel's recent messages, or the thread at --ts, starting at
+// --cursor. next receives the cursor to continue from.
+func listMessages(ctx context.Context, client *islack.Client, channelID string, next *string) ([]map[string]any, error) {
+	opts := historyWindow
+	opts.Limit, opts.PageSize = messageLimit, messagePageSize
+	opts.Cursor, opts.Resume = messageCursor, next
+	keep := messageMatcher()
+	if keep != nil {
+		// --limit counts matching messages, so pagination continues past the others.
+		opts.Limit = 0
+	}
+
+	var seq iter.Seq2[map[string]any, error]
+	if messageTS == "" {
+		// No --ts: list recent channel messages
+		seq = islack.IterChannelHistory(ctx, client, channelID, opts)
+	} else {
+		// With --ts: list thread replies
+		seq = islack.IterThread(ctx, client, channelID, messageTS, opts)
+	}
+	if keep != nil {
+		seq = islack.FilterMessagesContext(seq, keep, messageLimit, grepContext)
+	}
+	return islack.CollectMessages(seq)
+}
+
+// printCounts prints how many messages, threads, replies, and participants each channel's
+// listing has, for --count.
+func printCounts(channels []*listedChannel) {
+	results := make([]map[string]any, len(channels))
+	for i, c := range channels {
+		stats, err := report.ComputeChannelStats(func(yield func(map[string]any, error) bool) {
+			for _, msg := range c.messages {
+				if !yield(msg, nil) {
+					return
+				}
+			}
+		}, report.ChannelStatsOptions{})
+		if err != nil {
+			output.PrintError(err)
+		}
+		// Marshal the counts directly rather than via output.PrintJSON so zeros are kept.
+		results[i] = map[string]any{
+			"channel_id":   c.id,
+			"channel":      c.name,
+			"messages":     stats.Messages,
+			"threads":      stats.Threads,
+			"replies":      stats.Replies,
+			"participants": stats.Participants,
+		}
+	}
+	var v any = results
+	if len(results) == 1 {
+		v = results[0]
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		output.PrintError(err)
+	}
+	fmt.Println(string(data))
+}
+
+// parseHistoryWindow parses --since and --until into historyWindow, exiting on a bad value.
+func parseHistoryWindow() {
+	now := time.Now()
+	if messageSince != "" {
+		since, err := islack.ParseTimeSpec(messageSince, now)
+		if err != nil {
+			output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+		}
+		historyWindow.Oldest = islack.FormatTimestamp(since)
+	}
+	if messageUntil != "" {
+		until, err := islack.ParseTimeSpec(messageUntil, now)
+		if err != nil {
+			output.Exit(fmt.Errorf("--until: %w", err), output.ExitUsage)
+		}
+		historyWindow.Latest = islack.FormatTimestamp(until)
+	}
+}
+
+// messageMatcher returns the filter that message list flags select, or nil to keep every message.
+func messageMatcher() func(map[string]any) bool {
+	var filters []func(map[string]any) bool
+	if systemHidden() {
+		filters = append(filters, func(msg map[string]any) bool { return !islack.IsSystemMessage(msg) })
+	}
+	if len(subtypes) > 0 || len(excludeSubtypes) > 0 {
+		filters = append(filters, islack.MatchSubtypes(subtypes, excludeSubtypes))
+	}
+	if huddlesOnly {
+		filters = append(filters, islack.IsHuddle)
+	}
+	if grepPattern != nil {
+		filters = append(filters, islack.MatchText(grepPattern))
+	}
+	if onlyFiles || onlyLinks {
+		// Together, they keep messages with either, to collect every shared resource.
+		filters = append(filters, func(msg map[string]any) bool {
+			return (onlyFiles && islack.HasFiles(msg)) || (onlyLinks && islack.HasLinks(msg))
+		})
+	}
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	}
+	return func(msg map[string]any) bool {
+		for _, f := range filters {
+			if !f(msg) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// systemHidden reports whether system messages (channel_join, channel_topic, bot_add, ...)
+// are left out: by default, unless --show-system (or --hide-system=false) is given, or
+// --subtype selects the subtypes to list.
+func systemHidden() bool {
+	return hideSystem && !showSystem && len(subtypes) == 0
+}
+
+// addSystemFlags registers --hide-system and --show-system, which systemHidden reads.
+func addSystemFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&hideSystem, "hide-system", true, "Leave out system messages (joins, leaves, topic changes, integrations added)")
+	flags.BoolVar(&showSystem, "show-system", false, "Include system messages (joins, leaves, topic changes, integrations added)")
+}
+
+// addExecFilterFlag registers --exec-filter, which filterMessages applies.
+func addExecFilterFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&execFilter, "exec-filter", "", "Shell command each message and attachment (as JSON on stdin) is piped through; its output replaces the text, and blocks and file previews are dropped")
+}
+
+// subtypeFilter wraps seq to apply --subtype and --exclude-subtype, if given.
+func subtypeFilter(seq iter.Seq2[map[string]any, error]) iter.Seq2[map[string]any, error] {
+	if len(subtypes) == 0 && len(excludeSubtypes) == 0 {
+		return seq
+	}
+	return islack.FilterMessages(seq, islack.MatchSubtypes(subtypes, excludeSubtypes), 0)
+}
+
+// parseGrep compiles --grep into grepPattern, exiting on a bad pattern.
+func parseGrep() {
+	if messageGrep == "" {
+		if grepContext > 0 {
+			output.Exit(errors.New("--context requires --grep"), output.ExitUsage)
+		}
+		return
+	}
+	re, err := regexp.Compile(messageGrep)
+	if err != nil {
+		output.Exit(fmt.Errorf("--grep: %w", err), output.ExitUsage)
+	}
+	grepPattern = re
+}
+
+// filterMessages marks edited messages with edited_at, and rewrites message text
+// with --exec-filter, if set.
+func filterMessages(ctx context.Context, messages []map[string]any) {
+	islack.MarkEdited(messages)
+	if execFilter == "" {
+		return
+	}
+	if err := (filter.Exec{Command: execFilter}).Apply(ctx, messages); err != nil {
+		output.PrintError(err)
+	}
+}
+
+// addFileInfo fills in the files shared in messages with files.info metadata, for --file-info.
+func addFileInfo(ctx context.Context, client islack.APIClient, messages []map[string]any) {
+	if !fileInfo {
+		return
+	}
+	if err := islack.AddFileInfo(ctx, client, messages); err != nil {
+		output.PrintError(err)
+	}
+}
+
+// markdownOptions returns how to render messages as markdown: --grep matches highlighted,
+// and with --author-time, the time in each author's own time zone.
+func markdownOptions(ctx context.Context, users *islack.UserProvider, messages []map[string]any) output.MarkdownOptions {
+	opts := output.MarkdownOptions{Highlight: grepPattern}
+	if authorTime {
+		opts.AuthorLocations = users.AuthorLocations(ctx, messages)
+	}
+	return opts
+}
+
+// addRendered adds blocks_raw and text_rendered to messages in JSON output, for --rendered.
+func addRendered(ctx context.Context, users *islack.UserProvider, messages []map[string]any) {
+	users.Prefetch(ctx, messages)
+	if err := output.AddRendered(messages, users); err != nil {
+		output.PrintError(err)
+	}
+}
+
+// mirrorAvatars downloads the avatars of transcript authors and mentions into --download-avatars,
+// pointing avatarUrl at the local copies. Avatars that fail to download keep their URL.
+func mirrorAvatars(ctx context.Context, client *islack.Client, transcripts []*output.Transcript) {
+	var authors []*output.TranscriptAuthor
+	for _, t := range transcripts {
+		for i := range t.Messages {
+			m := &t.Messages[i]
+			authors = append(authors, &m.Author)
+			for j := range m.Mentions {
+				authors = append(authors, &m.Mentions[j])
+			}
+		}
+	}
+	var urls []string
+	for _, a := range authors {
+		if a.AvatarURL != "" && !slices.Contains(urls, a.AvatarURL) {
+			urls = append(urls, a.AvatarURL)
+		}
+	}
+
+	local, err := islack.MirrorAvatars(ctx, client, urls, avatarDir)
+	if err != nil {
+		output.PrintError(err)
+	}
+	for _, a := range authors {
+		if p, ok := local[a.AvatarURL]; ok {
+			a.AvatarURL = filepath.ToSlash(p)
+		}
+	}
+}
+
+// printTranscripts prints one transcript as an object, or several as an array.
+// Empty fields are kept, unlike PrintJSON, since transcript readers expect them.
+func printTranscripts(transcripts []*output.Transcript) {
+	var v any = transcripts
+	if len(transcripts) == 1 {
+		v = transcripts[0]
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		output.PrintError(err)
+	}
+	fmt.Println(string(data))
+}
+
+func channelHeading(channelID, channelName string) string {
+	if channelName != "" {
+		return "#" + channelName
+	}
+	return channelID
+}
+
+// groupDMDisplayName returns a group DM's participants ("alice, bob, carol"), to show in
+// place of its mpdm-... name, or "" for other conversations. Older group DMs have G IDs,
+// whose names are looked up when not known.
+func groupDMDisplayName(ctx context.Context, client *islack.Client, channelID, channelName string) string {
+	if channelName == "" && strings.HasPrefix(channelID, "G") {
+		channelName, _ = islack.ValidateChannelID(ctx, client, channelID)
+	}
+	if !islack.IsGroupDMName(channelName) {
+		return ""
+	}
+	name, err := islack.GroupDMName(ctx, client, islack.NewUserProvider(client), channelID)
+	if err != nil {
+		slog.Info("could not resolve group DM participants", "channel", channelID, "error", err)
+		return ""
+	}
+	return name
+}
+
+// resolveChannel resolves a channel argument to its ID and, when known, its name.
+// Channel IDs are passed through unchecked unless --validate is set.
+func resolveChannel(ctx context.Context, client *islack.Client, input string) (string, string) {
+	channelID, err := islack.ResolveChannelID(ctx, client, input)
+	if err != nil {
+		output.PrintError(err)
+	}
+
+	name, isID := islack.NormalizeChannelInput(input)
+	if !isID {
+		return channelID, name
+	}
+	if !validateChannel {
+		return channelID, ""
+	}
+	name, err = islack.ValidateChannelID(ctx, client, channelID)
+	if err != nil {
+		output.PrintError(err)
+	}
+	return channelID, name
+}
+
 func init() {
-	messageGetCmd.Flags().StringVar(&messageTS, "ts", "", "Message timestamp (required)")
-	messageListCmd.Flags().StringVar(&messageTS, "ts", "", "Thread root timestamp (required)")
-	messageListCmd.Flags().IntVar(&messageLimit, "limit", 0, "Maximum number of messages (0 = unlimited)")
-	messageListCmd.Flags().StringVar(&messageOutput, "output", "json", "Output format: json or markdown")
+	messageCmd.PersistentFlags().BoolVar(&validateChannel, "validate", false, "Check channel IDs with conversations.info before fetching")
+	messageCmd.PersistentFlags().BoolVar(&renderedText, "rendered", false, "Add blocks_raw (blocks as Slack sent them, unpruned) and text_rendered (markdown, mentions resolved) to each message in JSON output")
+	messageCmd.PersistentFlags().BoolVar(&fileInfo, "file-info", false, "Fill in shared files with files.info metadata (size, mimetype, title, text preview, is_external)")
+	messageCmd.PersistentFlags().BoolVar(&authorTime, "author-time", false, "In markdown output, also show each message's time in its author's time zone, e.g. \"09:12 (author local)\"")
+	addExecFilterFlag(messageCmd.PersistentFlags())
+	messageGetCmd.Flags().StringArrayVar(&messageGetTS, "ts", nil, "Message timestamp (required; repeatable, - reads stdin)")
+	messageGetCmd.Flags().StringVar(&messageThreadTS, "thread-ts", "", "Parent timestamp, when the message is a thread reply")
+	messageGetCmd.Flags().BoolVar(&messageIncludeThread, "include-thread", false, "Include the whole thread (parent and every reply) when the message is in one")
+	messageGetCmd.Flags().StringVarP(&messageGetOutput, "output", "o", "json", "Output format: json or markdown")
+	messageContextCmd.Flags().StringVar(&messageContextTS, "ts", "", "Message timestamp (required unless the channel is a message link)")
+	messageContextCmd.Flags().IntVarP(&contextBefore, "before", "B", 10, "Number of messages before the message")
+	messageContextCmd.Flags().IntVarP(&contextAfter, "after", "A", 10, "Number of messages after the message")
+	messageContextCmd.Flags().StringVarP(&messageContextOutput, "output", "o", "json", "Output format: json or markdown")
+	messageScheduledCmd.Flags().StringVarP(&scheduledOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+	messageListCmd.Flags().StringVar(&messageTS, "ts", "", "List the thread with this root timestamp instead of the channel")
+	messageListCmd.Flags().IntVar(&messageLimit, "limit", 0, "Maximum number of messages to list, after filters such as hiding system messages (0 = unlimited)")
+	messageListCmd.Flags().IntVar(&messagePageSize, "page-size", 200, "Messages requested per API call (max 200)")
+	messageListCmd.Flags().StringVar(&messageCursor, "cursor", "", "Continue an earlier listing (same channel and flags) from the next_cursor it printed in JSON, or on stderr for other formats")
+	messageListCmd.Flags().BoolVar(&noResolveUsers, "no-resolve-users", false, "Show user IDs instead of looking up names (markdown output)")
+	messageListCmd.Flags().StringVar(&messageSince, "since", "", "Only messages after this time: a duration before now (24h, 7d), a date (2026-01-31), an RFC 3339 time, or a Slack timestamp")
+	messageListCmd.Flags().StringVar(&messageUntil, "until", "", "Only messages before this time (same forms as --since)")
+	addSystemFlags(messageListCmd.Flags())
+	messageListCmd.Flags().StringSliceVar(&subtypes, "subtype", nil, "Only list messages of these subtypes, comma-separated, system ones included (e.g., bot_message,thread_broadcast; --limit counts these)")
+	messageListCmd.Flags().StringSliceVar(&excludeSubtypes, "exclude-subtype", nil, "Leave out messages of these subtypes, comma-separated")
+	messageListCmd.Flags().BoolVar(&messageCount, "count", false, "Print only the number of messages, threads, replies, and participants as JSON, after filters and the time window")
+	messageListCmd.Flags().BoolVar(&huddlesOnly, "huddles-only", false, "Only list huddles and calls, which markdown and transcript output summarize (--limit counts these)")
+	messageListCmd.Flags().StringVar(&messageGrep, "grep", "", "Only list messages whose text or attachment text matches this regular expression; (?i) ignores case, and markdown output bolds matches (--limit counts these)")
+	messageListCmd.Flags().IntVarP(&grepContext, "context", "C", 0, "With --grep, also list N messages before and after each match, marked \"context\": true in JSON")
+	messageListCmd.Flags().BoolVar(&onlyFiles, "only-files", false, "Only list messages that share files (--limit counts these)")
+	messageListCmd.Flags().BoolVar(&onlyLinks, "only-links", false, "Only list messages that contain links in their text or an unfurled preview; with --only-files, either is kept (--limit counts these)")
+	messageListCmd.Flags().BoolVar(&withReactions, "with-reactions", false, "Include who reacted with each emoji (reactions[].user_names), fetching full lists with reactions.get")
+	messageListCmd.Flags().BoolVar(&labelExternal, "label-external", false, "Label users from other organizations (Slack Connect) with their team name, e.g. \"alice (Acme Corp)\"")
+	messageListCmd.Flags().BoolVar(&stdinChannels, "stdin-channels", false, "Also read channels from stdin, one per line")
+	messageListCmd.Flags().StringVar(&avatarDir, "download-avatars", "", "With --output transcript, save author avatars in this directory and link to the local copies")
+	messageListCmd.Flags().StringVar(&messageOutput, "output", "json", "Output format: json, markdown (shared messages quoted under the message sharing them), or transcript (DiscordChatExporter-style JSON, with author avatars)")
 
 	messageCmd.AddCommand(messageGetCmd)
+	messageCmd.AddCommand(messageContextCmd)
 	messageCmd.AddCommand(messageListCmd)
+	messageCmd.AddCommand(messageScheduledCmd)
 	rootCmd.AddCommand(messageCmd)
 }
+
+// labelExternalUsers makes users label authors outside the authenticated workspace with their team name.
+func labelExternalUsers(ctx context.Context, client islack.APIClient, users *islack.UserProvider) {
+	teamID, err := islack.HomeTeamID(ctx, client)
+	if err != nil {
+		output.PrintError(err)
+	}
+	users.LabelExternal(teamID)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	openTS       string
+	openThreadTS string
+	openLookup   bool
+	openApp      bool
+	openPrint    bool
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <channel>",
+	Short: "Open a message (or channel) in the browser or Slack Desktop",
+	Long: `Open a message's permalink in the browser. The link is composed locally; --lookup asks
+Slack for it instead (chat.getPermalink), which is exact for Enterprise Grid workspaces.
+
+The channel can also be a message link, whose timestamps fill in --ts and --thread-ts
+when they are not given, and whose workspace is used without --workspace. Without --ts,
+opens the channel. --app opens Slack Desktop via a slack:// link instead; Desktop links
+address the channel only, not the message.
+
+Examples:
+  slack-reader open "#general" --workspace myteam --ts "1770165109.628379"
+  slack-reader open "#general" --workspace myteam --ts "1770165200.000100" --thread-ts "1770165109.628379"
+  slack-reader open "#general" --workspace myteam --app
+  slack-reader open C0123ABC --workspace myteam --ts "1770165109.628379" --print`,
+	Args: channelArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		workspaceFromLinks(args)
+		client := newClient()
+		inputs, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		ts, threadTS := linkTimestamps(inputs[0])
+		openTS, openThreadTS = cmp.Or(openTS, ts), cmp.Or(openThreadTS, threadTS)
+		channelID, err := islack.ResolveChannelID(ctx, client, inputs[0])
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		link, err := openLink(ctx, client, channelID)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if openPrint {
+			fmt.Println(link)
+			return
+		}
+		if err := openURL(link); err != nil {
+			output.PrintError(fmt.Errorf("open %s: %w", link, err))
+		}
+	},
+}
+
+// openLink returns the URL the flags ask for.
+func openLink(ctx context.Context, client *islack.Client, channelID string) (string, error) {
+	switch {
+	case openApp:
+		resp, err := client.API(ctx, "auth.test", nil)
+		if err != nil {
+			return "", fmt.Errorf("auth.test: %w", err)
+		}
+		teamID, _ := resp["team_id"].(string)
+		if teamID == "" {
+			return "", errors.New("auth.test: no team_id in response")
+		}
+		return islack.AppLink(teamID, channelID), nil
+	case openTS == "":
+		return fmt.Sprintf("https://%s.slack.com/archives/%s", client.Domain(), channelID), nil
+	case openLookup:
+		return islack.GetPermalink(ctx, client, channelID, openTS)
+	default:
+		return islack.Permalink(client.Domain(), channelID, openTS, openThreadTS), nil
+	}
+}
+
+// openURL hands a URL to the platform's default handler.
+func openURL(link string) error {
+	name, args := "xdg-open", []string{link}
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler", link}
+	}
+	return exec.Command(name, args...).Run() //nolint:gosec // the link is an argument, not shell input
+}
+
+func init() {
+	openCmd.Flags().StringVar(&openTS, "ts", "", "Message timestamp (omit to open the channel)")
+	openCmd.Flags().StringVar(&openThreadTS, "thread-ts", "", "Parent timestamp, when the message is a thread reply")
+	openCmd.Flags().BoolVar(&openLookup, "lookup", false, "Get the permalink from Slack (chat.getPermalink) instead of composing it")
+	openCmd.Flags().BoolVar(&openApp, "app", false, "Open the channel in Slack Desktop (slack://) instead of the browser")
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "Print the link instead of opening it")
+	openCmd.MarkFlagsMutuallyExclusive("app", "lookup")
+	rootCmd.AddCommand(openCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	readLimit  int
+	readOutput string
+)
+
+var readCmd = &cobra.Command{
+	Use:   "read <reference>",
+	Short: "Read a channel, DM, thread, or message from any Slack reference",
+	Long: `Read whatever a Slack reference points at, without choosing a subcommand:
+
+  #channel (or a channel name or ID)   the channel's recent messages
+  @user                                your direct messages with the user
+  #channel/1770165109.628379           the message, or its whole thread if it is in one
+  a channel or message link            the channel, or the message or thread
+
+Recent messages are limited by --limit (50 by default), not counting system messages
+(joins, topic changes, ...), which are left out unless --show-system is given. A DM is
+only read if it exists; none is opened. Output is markdown unless --output json is
+given. In a terminal, omitting the reference opens a picker of channels and people.
+
+Examples:
+  slack-reader read "#general" --workspace myteam
+  slack-reader read @alice --workspace myteam --limit 20
+  slack-reader read "#general/1770165109.628379" --workspace myteam
+  slack-reader read "https://myteam.slack.com/archives/C0123ABC/p1770165109628379" --output json`,
+	Args: userArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if readOutput != "json" && readOutput != "markdown" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or markdown)", readOutput), output.ExitUsage)
+		}
+		var ref islack.Reference
+		var err error
+		if len(args) > 0 {
+			if ref, err = islack.ParseReference(args[0]); err != nil {
+				output.Exit(err, output.ExitUsage)
+			}
+		}
+
+		workspaceFromLinks(args)
+		client := newClient()
+		if len(args) == 0 {
+			input, err := referenceInput(client, args)
+			if err != nil {
+				output.PrintError(err)
+			}
+			if ref, err = islack.ParseReference(input); err != nil {
+				output.PrintError(err)
+			}
+		}
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		var channelID, channelName string
+		if ref.User != "" {
+			userID, err := islack.ResolveUserID(ctx, client, ref.User)
+			if err != nil {
+				output.PrintError(err)
+			}
+			if channelID, err = islack.FindDM(ctx, client, userID); err != nil {
+				output.PrintError(err)
+			}
+			channelName = "@" + ref.User
+		} else {
+			channelID, channelName = resolveChannel(ctx, client, ref.Channel)
+		}
+
+		var messages []map[string]any
+		if ref.TS != "" {
+			// A message: the whole thread when it is in one, or else the message alone
+			result, err := islack.GetMessage(ctx, client, channelID, ref.TS, ref.ThreadTS)
+			if err == nil {
+				err = islack.IncludeThread(ctx, client, channelID, result)
+			}
+			if err != nil {
+				invalidateReference(client, ref, err)
+				output.PrintError(err)
+			}
+			messages = resultMessages(result)
+		} else {
+			opts := islack.HistoryOptions{Limit: readLimit}
+			if systemHidden() {
+				// --limit counts the messages shown, so pagination continues past system ones.
+				opts.Limit = 0
+			}
+			seq := islack.IterChannelHistory(ctx, client, channelID, opts)
+			if systemHidden() {
+				seq = islack.FilterMessages(seq, func(msg map[string]any) bool { return !islack.IsSystemMessage(msg) }, readLimit)
+			}
+			messages, err = islack.CollectMessages(seq)
+			if err != nil {
+				invalidateReference(client, ref, err)
+				output.PrintError(err)
+			}
+		}
+		filterMessages(ctx, messages)
+
+		if readOutput == "markdown" {
+			users := islack.NewUserProvider(client)
+			users.Prefetch(ctx, messages)
+			output.PrintMarkdown(messages, users)
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"channel":    channelName,
+			"messages":   messages,
+		})
+	},
+}
+
+// invalidateReference drops a cached channel name that Slack no longer knows.
+func invalidateReference(client *islack.Client, ref islack.Reference, err error) {
+	if ref.Channel != "" {
+		islack.InvalidateChannelOnError(client, ref.Channel, err)
+	}
+}
+
+func init() {
+	readCmd.Flags().IntVar(&readLimit, "limit", 50, "Maximum number of recent messages for a channel or DM (0 = unlimited)")
+	readCmd.Flags().StringVarP(&readOutput, "output", "o", "markdown", "Output format: markdown or json")
+	addSystemFlags(readCmd.Flags())
+	addExecFilterFlag(readCmd.Flags())
+	rootCmd.AddCommand(readCmd)
+}
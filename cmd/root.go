@@ -2,27 +2,154 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/sethrylan/slack-reader/internal/config"
+	"github.com/sethrylan/slack-reader/internal/output"
 	"github.com/spf13/cobra"
 )
 
-var workspace string
+var (
+	configPath      string
+	configWorkspace string // the config file's workspace, for requireWorkspace
+	workspace       string
+	verbose         int
+	logFormat       string
+	debugHTTP       bool
+	noCache         bool
+	cacheTTL        time.Duration
+
+	timeout        time.Duration
+	requestTimeout time.Duration
+	maxRetries     int
+	maxIdleConns   int
+	caCert         string
+
+	showStats      bool
+	nonInteractive bool
+
+	fuzzyChannels   bool
+	includeArchived bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "slack-reader",
 	Short: "Read-only Slack CLI using cookie-based authentication",
 	Long:  "A CLI tool for reading Slack messages, threads, and channel lists using cookie-based authentication from Slack Desktop.",
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		startTime = time.Now()
+		applyConfig(cmd)
+		setupLogging()
+		if showStats {
+			// Failing commands exit through output.Exit and never reach PersistentPostRun.
+			output.OnExit(printStats)
+		}
+	},
+	PersistentPostRun: func(_ *cobra.Command, _ []string) {
+		if showStats {
+			printStats()
+		}
+	},
 }
 
-// Execute runs the root command.
+// Execute runs the root command. Errors that reach here are usage errors (bad flags or arguments).
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		if nonInteractive {
+			output.Exit(err, output.ExitUsage)
+		}
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(output.ExitUsage)
+	}
+}
+
+// setupLogging logs to stderr at the level --verbose sets: warnings by default, progress
+// (retries, fallbacks) with -v, and every API call, page, and cache hit with -vv (or
+// --debug, which also traces HTTP).
+func setupLogging() {
+	level := slog.LevelWarn
+	switch {
+	case verbose >= 2 || debugHTTP:
+		level = slog.LevelDebug
+	case verbose == 1:
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	switch logFormat {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, opts)))
+	default:
+		output.Exit(fmt.Errorf("invalid --log-format %q (want text or json)", logFormat), output.ExitUsage)
+	}
+}
+
+// applyConfig sets the flags the command line left unset from the configuration file.
+// Flags named for the command itself must exist; global and command group values only
+// apply to the commands that have the flags.
+func applyConfig(cmd *cobra.Command) {
+	path := configPath
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			slog.Info("no config file", "error", err)
+			return
+		}
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		output.Exit(err, output.ExitUsage)
+	}
+
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	for name := range cfg.Commands[command] {
+		if cmd.Flags().Lookup(name) == nil {
+			output.Exit(fmt.Errorf("config %s: %s has no --%s flag", path, command, name), output.ExitUsage)
+		}
+	}
+	for name, value := range cfg.Values(command) {
+		if name == "workspace" {
+			// A default, which a Slack link given on the command line overrides.
+			configWorkspace = value
+			continue
+		}
+		f := cmd.Flags().Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			output.Exit(fmt.Errorf("config %s: --%s: %w", path, name, err), output.ExitUsage)
+		}
 	}
 }
 
 func init() {
+	cobra.OnInitialize(func() {
+		// Runs after flags are parsed but before args are validated: keep stderr to the JSON error.
+		if nonInteractive {
+			rootCmd.SilenceUsage = true
+			rootCmd.SilenceErrors = true
+		}
+	})
+
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file of default flag values (default ~/.config/slack-reader/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&workspace, "workspace", "", "Slack team domain (e.g., \"myteam\" for myteam.slack.com)")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Log progress (e.g., rate limit retries) to stderr; -vv also logs each API call, page, and cache hit")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug", false, "Log every HTTP request and response (params, status, timing, truncated body) with tokens and cookies redacted; implies -vv")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Bypass the API response cache")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 0, "Cache API responses on disk for this long, e.g. 1m (default off)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Overall deadline for the command (0 = none)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", time.Minute, "Timeout for each HTTP request to Slack (0 = none)")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", 5, "Retries for rate-limited or transiently failing requests")
+	rootCmd.PersistentFlags().IntVar(&maxIdleConns, "max-idle-conns", 10, "Idle keep-alive connections kept open to Slack")
+	rootCmd.PersistentFlags().BoolVar(&showStats, "stats", false, "Print API usage (calls, bytes, retries, cache hits, elapsed time) to stderr")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt or read Slack Desktop credentials; authenticate only from SLACK_TOKEN and SLACK_COOKIES (for cron and CI)")
+	rootCmd.PersistentFlags().BoolVar(&fuzzyChannels, "fuzzy", false, "Resolve partial or misspelled channel names to the best match, when unambiguous")
+	rootCmd.PersistentFlags().BoolVar(&includeArchived, "include-archived", false, "Resolve and list archived channels too")
+	rootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "", "PEM file of extra CA certificates to trust (e.g., for a TLS-intercepting proxy)")
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	savedState  string
+	savedLimit  int
+	savedOutput string
+)
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "List your Later (saved) items with due dates",
+	Long: `List the items in your Later list (formerly saved items), with the saved messages,
+their due dates, and whether they are completed: items due soonest first, then the rest,
+most recently saved first.
+
+--state selects in_progress (the default, as in Slack's Later tab), completed, archived,
+or all items. --output markdown prints a task list, with completed items checked.
+
+This reads saved.list, the undocumented method the Slack client uses for its Later view,
+so it may change without notice.
+
+Examples:
+  slack-reader saved --workspace myteam
+  slack-reader saved --workspace myteam --output markdown
+  slack-reader saved --workspace myteam --state completed --limit 20`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if savedOutput != "json" && savedOutput != "markdown" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or markdown)", savedOutput), output.ExitUsage)
+		}
+		states := []string{islack.SavedInProgress, islack.SavedCompleted, islack.SavedArchived, "all"}
+		if !slices.Contains(states, savedState) {
+			output.Exit(fmt.Errorf("invalid --state %q (want in_progress, completed, archived, or all)", savedState), output.ExitUsage)
+		}
+		opts := islack.SavedOptions{State: savedState, Limit: savedLimit}
+		if savedState == "all" {
+			opts.State = ""
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		items, err := islack.ListSavedItems(ctx, client, opts)
+		if err != nil {
+			output.PrintError(err)
+		}
+		for _, item := range items {
+			if item.ChannelID != "" && item.TS != "" {
+				threadTS, _ := item.Message["thread_ts"].(string)
+				item.Permalink = islack.Permalink(client.Domain(), item.ChannelID, item.TS, threadTS)
+			}
+		}
+
+		if savedOutput == "markdown" {
+			var messages []map[string]any
+			for _, item := range items {
+				if item.Message != nil {
+					messages = append(messages, item.Message)
+				}
+			}
+			users := islack.NewUserProvider(client)
+			users.Prefetch(ctx, messages)
+			md, err := output.FormatSavedMarkdown(items, users)
+			if err != nil {
+				output.PrintError(err)
+			}
+			fmt.Print(md)
+			return
+		}
+		output.PrintJSON(map[string]any{"items": items})
+	},
+}
+
+func init() {
+	savedCmd.Flags().StringVar(&savedState, "state", islack.SavedInProgress, "Items to list: in_progress, completed, archived, or all")
+	savedCmd.Flags().IntVar(&savedLimit, "limit", 0, "Maximum number of items (0 = all)")
+	savedCmd.Flags().StringVarP(&savedOutput, "output", "o", "json", "Output format: json or markdown (task list)")
+	rootCmd.AddCommand(savedCmd)
+}
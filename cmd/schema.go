@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	"github.com/sethrylan/slack-reader/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <message|channel|user>",
+	Short: "Print the JSON Schema of an output type",
+	Long: `Print the JSON Schema (draft 2020-12) of a type in slack-reader's JSON output, for
+validating output or generating code from it.
+
+Each schema describes one object (a message, conversation, or user); the envelopes that
+commands wrap them in (e.g., message list's {"channel", "messages"}) are under $defs.
+
+Examples:
+  slack-reader schema message
+  slack-reader schema channel > channel.schema.json`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: schema.Names(),
+	Run: func(_ *cobra.Command, args []string) {
+		data, err := schema.Get(args[0])
+		if err != nil {
+			output.Exit(err, output.ExitUsage)
+		}
+		fmt.Print(string(data))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	"github.com/sethrylan/slack-reader/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var serveListen string
+
+// serveCacheTTL is serve's default --cache-ttl: a server answers the same requests
+// repeatedly, so it caches unless told not to.
+const serveCacheTTL = time.Minute
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a read-only REST API",
+	Long: `Serve Slack data over HTTP, proxying every request through this workspace's credentials
+and the API response cache, so other tools can read Slack without credentials of their
+own. Responses are cached for --cache-ttl, which defaults to 1m here rather than off, so
+that polling dashboards don't each hit Slack.
+
+Endpoints (all GET, all JSON):
+  /v1/channels                          Your conversations (?all=true for the workspace, ?user=, ?limit=)
+  /v1/channels/{channel}/history        Channel messages (?oldest=, ?latest=, ?limit=)
+  /v1/channels/{channel}/messages/{ts}  A single message (?thread_ts= for replies)
+  /v1/threads/{channel}/{ts}            A thread's messages (?limit=)
+  /metrics                              Prometheus metrics: API calls, rate limiting, cache, latency
+
+{channel} is a channel ID or name. Channels, history and threads return 100 items
+unless ?limit= asks for more, up to 1000. The server has no authentication of its own;
+bind it to a trusted interface.
+
+Examples:
+  slack-reader serve --workspace myteam
+  slack-reader serve --workspace myteam --listen 127.0.0.1:9000`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if !cmd.Flags().Changed("cache-ttl") {
+			cacheTTL = serveCacheTTL
+		}
+		client := newClient()
+
+		srv := &http.Server{
+			Addr:              serveListen,
+			Handler:           server.New(client, &apiStats),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		slog.Warn("serving", "addr", serveListen)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.PrintError(err)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
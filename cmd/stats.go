@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	"github.com/sethrylan/slack-reader/internal/report"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// apiStats collects usage from every client the command creates, for --stats.
+	apiStats  islack.Stats
+	startTime time.Time
+)
+
+// printStats writes the API usage summary as JSON to stderr.
+func printStats() {
+	summary := struct {
+		islack.StatsSummary
+		Elapsed string `json:"elapsed"`
+	}{
+		StatsSummary: apiStats.Summary(),
+		Elapsed:      time.Since(startTime).Round(time.Millisecond).String(),
+	}
+
+	// Marshal directly rather than via output.PrintJSON so zero counts are kept.
+	data, err := json.MarshalIndent(map[string]any{"stats": summary}, "", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+var (
+	activitySince       string
+	activityUntil       string
+	activityOutput      string
+	activityExcludeBots bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Channel activity reports",
+}
+
+var statsActivityCmd = &cobra.Command{
+	Use:   "activity <channel>",
+	Short: "Count a channel's messages per day and per user",
+	Long: `Count a channel's top-level messages per day (UTC) and per user over a period,
+by paginating its history.
+
+JSON output has a total and per-day and per-user counts. CSV output has one row per
+day and user (date,user_id,user,messages), ready to pivot in a spreadsheet.
+
+--since and --until accept a duration before now (24h, 30d), a date (2026-01-31),
+an RFC 3339 time, or a Slack timestamp.
+
+Examples:
+  slack-reader stats activity "#eng" --workspace myteam
+  slack-reader stats activity "#eng" --workspace myteam --since 30d --output csv
+  slack-reader stats activity "#eng" --workspace myteam --since 2026-01-01 --until 2026-02-01 --exclude-bots`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if activityOutput != "json" && activityOutput != "csv" {
+			output.PrintError(fmt.Errorf("invalid --output %q (want json or csv)", activityOutput))
+		}
+
+		now := time.Now()
+		since, err := islack.ParseTimeSpec(activitySince, now)
+		if err != nil {
+			output.PrintError(fmt.Errorf("--since: %w", err))
+		}
+		until := now
+		if activityUntil != "" {
+			if until, err = islack.ParseTimeSpec(activityUntil, now); err != nil {
+				output.PrintError(fmt.Errorf("--until: %w", err))
+			}
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, channelName := resolveChannel(ctx, client, args[0])
+		activity, err := report.ComputeActivity(islack.IterChannelHistory(ctx, client, channelID, islack.HistoryOptions{
+			Oldest: islack.FormatTimestamp(since),
+			Latest: islack.FormatTimestamp(until),
+		}), report.ActivityOptions{ExcludeBots: activityExcludeBots})
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+		names := userCountNames(ctx, client, activity.Users)
+
+		if activityOutput == "csv" {
+			if err := writeActivityCSV(os.Stdout, activity, names); err != nil {
+				output.PrintError(err)
+			}
+			return
+		}
+
+		for i := range activity.Users {
+			activity.Users[i].User = names[activity.Users[i].UserID]
+		}
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"name":       channelName,
+			"since":      since.UTC().Format(time.RFC3339),
+			"until":      until.UTC().Format(time.RFC3339),
+			"total":      activity.Total,
+			"days":       activity.Days,
+			"users":      activity.Users,
+		})
+	},
+}
+
+// userCountNames resolves the display names of the users (not bots) in a report.
+func userCountNames(ctx context.Context, client islack.APIClient, counts []report.UserCount) map[string]string {
+	var authors []map[string]any
+	for _, u := range counts {
+		if strings.HasPrefix(u.UserID, "U") || strings.HasPrefix(u.UserID, "W") {
+			authors = append(authors, map[string]any{"user": u.UserID})
+		}
+	}
+	users := islack.NewUserProvider(client)
+	users.Prefetch(ctx, authors)
+
+	names := make(map[string]string, len(authors))
+	for _, a := range authors {
+		id, _ := a["user"].(string)
+		names[id], _ = users.UsernameForID(id)
+	}
+	return names
+}
+
+func writeActivityCSV(w io.Writer, activity *report.Activity, names map[string]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "user_id", "user", "messages"}); err != nil {
+		return err
+	}
+	for _, row := range activity.DayUser {
+		if err := cw.Write([]string{row.Date, row.UserID, names[row.UserID], strconv.Itoa(row.Messages)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var (
+	channelStatsSince       string
+	channelStatsUntil       string
+	channelStatsOutput      string
+	channelStatsTop         int
+	channelStatsExcludeBots bool
+)
+
+var channelStatsCmd = &cobra.Command{
+	Use:   "stats <channel>",
+	Short: "Summarize a channel's messages, threads, and participants",
+	Long: `Summarize a channel's history over a period: top-level messages, threads, replies,
+distinct participants, average thread length (replies per thread), top posters, and
+busiest days (UTC).
+
+Thread figures come from the reply counts on thread parents, so only the channel's
+history is paginated; threads started before --since are not counted.
+
+CSV output has one metric,key,value row per figure, with the user or date as the key
+for top posters and busiest days.
+
+--since and --until accept a duration before now (24h, 90d), a date (2026-01-31),
+an RFC 3339 time, or a Slack timestamp.
+
+Examples:
+  slack-reader channel stats "#support" --workspace myteam --since 90d
+  slack-reader channel stats "#support" --workspace myteam --since 2026-01-01 --until 2026-04-01 --output csv
+  slack-reader channel stats "#support" --workspace myteam --top 5 --exclude-bots`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if channelStatsOutput != "json" && channelStatsOutput != "csv" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or csv)", channelStatsOutput), output.ExitUsage)
+		}
+
+		now := time.Now()
+		since, err := islack.ParseTimeSpec(channelStatsSince, now)
+		if err != nil {
+			output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+		}
+		until := now
+		if channelStatsUntil != "" {
+			if until, err = islack.ParseTimeSpec(channelStatsUntil, now); err != nil {
+				output.Exit(fmt.Errorf("--until: %w", err), output.ExitUsage)
+			}
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, channelName := resolveChannel(ctx, client, args[0])
+		stats, err := report.ComputeChannelStats(islack.IterChannelHistory(ctx, client, channelID, islack.HistoryOptions{
+			Oldest: islack.FormatTimestamp(since),
+			Latest: islack.FormatTimestamp(until),
+		}), report.ChannelStatsOptions{
+			ActivityOptions: report.ActivityOptions{ExcludeBots: channelStatsExcludeBots},
+			Top:             channelStatsTop,
+		})
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+		names := userCountNames(ctx, client, stats.TopPosters)
+		for i := range stats.TopPosters {
+			stats.TopPosters[i].User = names[stats.TopPosters[i].UserID]
+		}
+
+		if channelStatsOutput == "csv" {
+			if err := writeChannelStatsCSV(os.Stdout, stats); err != nil {
+				output.PrintError(err)
+			}
+			return
+		}
+
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"name":       channelName,
+			"since":      since.UTC().Format(time.RFC3339),
+			"until":      until.UTC().Format(time.RFC3339),
+			"stats":      stats,
+		})
+	},
+}
+
+func writeChannelStatsCSV(w io.Writer, stats *report.ChannelStats) error {
+	rows := [][]string{
+		{"metric", "key", "value"},
+		{"messages", "", strconv.Itoa(stats.Messages)},
+		{"threads", "", strconv.Itoa(stats.Threads)},
+		{"replies", "", strconv.Itoa(stats.Replies)},
+		{"participants", "", strconv.Itoa(stats.Participants)},
+		{"avg_thread_length", "", strconv.FormatFloat(stats.AvgThreadLength, 'f', 2, 64)},
+	}
+	for _, u := range stats.TopPosters {
+		name := u.User
+		if name == "" {
+			name = u.UserID
+		}
+		rows = append(rows, []string{"top_poster", name, strconv.Itoa(u.Messages)})
+	}
+	for _, d := range stats.BusiestDays {
+		rows = append(rows, []string{"busiest_day", d.Date, strconv.Itoa(d.Messages)})
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var (
+	reactionsSince  string
+	reactionsUntil  string
+	reactionsOutput string
+	reactionsTop    int
+)
+
+var statsReactionsCmd = &cobra.Command{
+	Use:   "reactions <channel>",
+	Short: "Rank a channel's most used emojis and most reacted-to messages",
+	Long: `Tally the reactions on a channel's top-level messages over a period: which emojis
+were used most (skin tone variants count as the base emoji), and which messages got the
+most reactions, with their authors and permalinks.
+
+CSV output has one metric,key,value row per figure: emoji rows are keyed by emoji name,
+message rows by permalink.
+
+--since and --until accept a duration before now (24h, 30d), a date (2026-01-31),
+an RFC 3339 time, or a Slack timestamp.
+
+Examples:
+  slack-reader stats reactions "#general" --workspace myteam --since 30d
+  slack-reader stats reactions "#kudos" --workspace myteam --since 2026-01-01 --until 2026-04-01 --top 5
+  slack-reader stats reactions "#general" --workspace myteam --output csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if reactionsOutput != "json" && reactionsOutput != "csv" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or csv)", reactionsOutput), output.ExitUsage)
+		}
+
+		now := time.Now()
+		since, err := islack.ParseTimeSpec(reactionsSince, now)
+		if err != nil {
+			output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+		}
+		until := now
+		if reactionsUntil != "" {
+			if until, err = islack.ParseTimeSpec(reactionsUntil, now); err != nil {
+				output.Exit(fmt.Errorf("--until: %w", err), output.ExitUsage)
+			}
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, channelName := resolveChannel(ctx, client, args[0])
+		reactions, err := report.ComputeReactions(islack.IterChannelHistory(ctx, client, channelID, islack.HistoryOptions{
+			Oldest: islack.FormatTimestamp(since),
+			Latest: islack.FormatTimestamp(until),
+		}), report.ReactionsOptions{Top: reactionsTop})
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+
+		authors := make([]report.UserCount, len(reactions.Messages))
+		for i, m := range reactions.Messages {
+			authors[i].UserID = m.UserID
+		}
+		names := userCountNames(ctx, client, authors)
+		for i := range reactions.Messages {
+			m := &reactions.Messages[i]
+			m.User = names[m.UserID]
+			m.Permalink = islack.Permalink(client.Domain(), channelID, m.TS, "")
+		}
+
+		if reactionsOutput == "csv" {
+			if err := writeReactionsCSV(os.Stdout, reactions); err != nil {
+				output.PrintError(err)
+			}
+			return
+		}
+
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"name":       channelName,
+			"since":      since.UTC().Format(time.RFC3339),
+			"until":      until.UTC().Format(time.RFC3339),
+			"reactions":  reactions,
+		})
+	},
+}
+
+func writeReactionsCSV(w io.Writer, reactions *report.Reactions) error {
+	rows := [][]string{
+		{"metric", "key", "value"},
+		{"reactions", "", strconv.Itoa(reactions.Total)},
+	}
+	for _, e := range reactions.Emojis {
+		rows = append(rows, []string{"emoji", e.Name, strconv.Itoa(e.Reactions)})
+	}
+	for _, m := range reactions.Messages {
+		rows = append(rows, []string{"message", m.Permalink, strconv.Itoa(m.Reactions)})
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func init() {
+	channelStatsCmd.Flags().StringVar(&channelStatsSince, "since", "30d", "Start of the period")
+	channelStatsCmd.Flags().StringVar(&channelStatsUntil, "until", "", "End of the period (default now)")
+	channelStatsCmd.Flags().StringVarP(&channelStatsOutput, "output", "o", "json", "Output format: json or csv")
+	channelStatsCmd.Flags().IntVar(&channelStatsTop, "top", 10, "Number of top posters and busiest days to show (0 = all)")
+	channelStatsCmd.Flags().BoolVar(&channelStatsExcludeBots, "exclude-bots", false, "Skip messages posted by bots and integrations")
+	channelCmd.AddCommand(channelStatsCmd)
+
+	statsActivityCmd.Flags().StringVar(&activitySince, "since", "30d", "Start of the period")
+	statsActivityCmd.Flags().StringVar(&activityUntil, "until", "", "End of the period (default now)")
+	statsActivityCmd.Flags().StringVarP(&activityOutput, "output", "o", "json", "Output format: json or csv")
+	statsActivityCmd.Flags().BoolVar(&activityExcludeBots, "exclude-bots", false, "Skip messages posted by bots and integrations")
+	statsCmd.AddCommand(statsActivityCmd)
+
+	statsReactionsCmd.Flags().StringVar(&reactionsSince, "since", "30d", "Start of the period")
+	statsReactionsCmd.Flags().StringVar(&reactionsUntil, "until", "", "End of the period (default now)")
+	statsReactionsCmd.Flags().StringVarP(&reactionsOutput, "output", "o", "json", "Output format: json or csv")
+	statsReactionsCmd.Flags().IntVar(&reactionsTop, "top", 10, "Number of emojis and messages to show (0 = all)")
+	statsCmd.AddCommand(statsReactionsCmd)
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/filter"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/sethrylan/slack-reader/internal/tail"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tailInterval time.Duration
+	tailPostTo   string
+	tailMatch    string
+	tailExec     string
+)
+
+var tailCmd = &cobra.Command{
+	Use:   "tail <channel>",
+	Short: "Follow a channel, printing messages as they are posted",
+	Long: `Follow a channel from now on, polling its history every --interval, and print each new
+message as one line of JSON (JSON Lines), until interrupted or --timeout:
+
+  {"channel": "alerts", "channel_id": "C0123ABC", "permalink": "https://...",
+   "message": {"ts": "...", "user": "U0123ABC", "text": "...", "text_rendered": "..."}}
+
+text_rendered is the message's text as markdown, with mentions resolved. Thread replies
+are not followed, except those also sent to the channel. Responses are never cached, so
+--cache-ttl does not apply.
+
+With --post-to, each line is also POSTed (Content-Type: application/json) to an HTTP
+endpoint, such as an internal webhook, bridging the channel into other systems without a
+Slack app. Posts are retried with backoff when rate limited (429), on server errors
+(5xx), and on network failures, up to --max-retries; if the endpoint still fails, or
+answers with another non-2xx status, tail exits with the error.
+
+With --match, only messages whose rendered text matches the regular expression (RE2
+syntax, e.g. "(?i)sev ?1") are printed, posted, or run. With --exec, a command is run
+with sh -c for each message: {} stands for the rendered text, passed to sh as "$1" so
+that none of it is run as shell syntax (leave {} unquoted), and the line of JSON is on
+its stdin. The command's output goes to stderr, keeping stdout JSON Lines, and tail
+exits if it fails.
+
+In a terminal, omitting the channel opens a picker of known channels.
+
+Examples:
+  slack-reader tail "#alerts" --workspace myteam
+  slack-reader tail "#alerts" --workspace myteam --interval 30s | jq -r .message.text_rendered
+  slack-reader tail "#deploys" --workspace myteam --post-to https://internal.example/hook
+  slack-reader tail "#alerts" --workspace myteam --match SEV1 --exec 'notify-send "SEV1" {}'
+  slack-reader tail "#alerts" --workspace myteam --match '(?i)sev ?1' --exec 'jq -r .permalink >> sev1.log'`,
+	Args: channelArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if tailInterval < time.Second {
+			output.Exit(fmt.Errorf("invalid --interval %s (want at least 1s)", tailInterval), output.ExitUsage)
+		}
+		if tailPostTo != "" {
+			if u, err := url.Parse(tailPostTo); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				output.Exit(fmt.Errorf("invalid --post-to %q (want an http or https URL)", tailPostTo), output.ExitUsage)
+			}
+		}
+		var match *regexp.Regexp
+		if tailMatch != "" {
+			var err error
+			if match, err = regexp.Compile(tailMatch); err != nil {
+				output.Exit(fmt.Errorf("invalid --match: %w", err), output.ExitUsage)
+			}
+		}
+		if runtime.GOOS == "windows" && strings.Contains(tailExec, "{}") {
+			output.Exit(fmt.Errorf("--exec: %w", filter.ErrPlaceholder), output.ExitUsage)
+		}
+		// A cached poll would hide the messages posted since.
+		cacheTTL = 0
+
+		client := newClient()
+		inputs, err := channelInputs(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		channelID, channelName := resolveChannel(ctx, client, inputs[0])
+		users := islack.NewUserProvider(client)
+		opts := tail.Options{Oldest: islack.FormatTimestamp(time.Now()), Interval: tailInterval}
+		err = tail.Follow(ctx, client, channelID, opts, func(msg map[string]any) error {
+			messages := []map[string]any{msg}
+			users.Prefetch(ctx, messages)
+			if err := output.AddRendered(messages, users); err != nil {
+				return err
+			}
+			text, _ := msg["text_rendered"].(string)
+			if match != nil && !match.MatchString(text) {
+				return nil
+			}
+			ts, _ := msg["ts"].(string)
+			threadTS, _ := msg["thread_ts"].(string)
+			line, err := output.MarshalLine(tail.Envelope{
+				ChannelID: channelID,
+				Channel:   channelName,
+				Permalink: islack.Permalink(client.Domain(), channelID, ts, threadTS),
+				Message:   msg,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(line))
+
+			if tailExec != "" {
+				if err := filter.Run(ctx, tailExec, text, line); err != nil {
+					return fmt.Errorf("--exec on message %s: %w", ts, err)
+				}
+			}
+
+			if tailPostTo != "" {
+				return client.PostJSON(ctx, tailPostTo, line)
+			}
+			return nil
+		})
+		if err != nil {
+			output.PrintError(err)
+		}
+	},
+}
+
+func init() {
+	tailCmd.Flags().DurationVar(&tailInterval, "interval", 5*time.Second, "Time between polls for new messages (at least 1s)")
+	tailCmd.Flags().StringVar(&tailPostTo, "post-to", "", "Also POST each message's JSON to this HTTP endpoint, with retries")
+	tailCmd.Flags().StringVar(&tailMatch, "match", "", "Only messages whose rendered text matches this regular expression")
+	tailCmd.Flags().StringVar(&tailExec, "exec", "", "Run this shell command for each message: {} is its rendered text, and its JSON is on stdin")
+	rootCmd.AddCommand(tailCmd)
+}
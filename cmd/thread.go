@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	threadListSince  string
+	threadListUntil  string
+	threadListLimit  int
+	threadListOutput string
+
+	threadsUnreadOnly bool
+	threadsLimit      int
+	threadsOutput     string
+)
+
+var threadCmd = &cobra.Command{
+	Use:   "thread",
+	Short: "Thread operations",
+}
+
+var threadListCmd = &cobra.Command{
+	Use:   "list <channel>",
+	Short: "List a channel's threads with reply counts and last activity",
+	Long: `Scan a channel's history for messages with replies and list those threads, most
+recently active first: the root's author and text snippet, reply count, participants,
+last activity, and permalink. Replies are not fetched; expand a thread with
+message list --ts.
+
+Only threads whose root was posted between --since and --until are found. --since and
+--until accept a duration before now (24h, 7d), a date (2026-01-31), an RFC 3339 time,
+or a Slack timestamp. --output text prints a tab-separated table.
+
+Examples:
+  slack-reader thread list "#eng" --workspace myteam
+  slack-reader thread list "#eng" --workspace myteam --since 30d --limit 20 --output text`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if threadListOutput != "json" && threadListOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", threadListOutput), output.ExitUsage)
+		}
+
+		now := time.Now()
+		since, err := islack.ParseTimeSpec(threadListSince, now)
+		if err != nil {
+			output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+		}
+		opts := islack.HistoryOptions{Oldest: islack.FormatTimestamp(since)}
+		if threadListUntil != "" {
+			until, err := islack.ParseTimeSpec(threadListUntil, now)
+			if err != nil {
+				output.Exit(fmt.Errorf("--until: %w", err), output.ExitUsage)
+			}
+			opts.Latest = islack.FormatTimestamp(until)
+		}
+
+		workspaceFromLinks(args)
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		channelID, channelName := resolveChannel(ctx, client, args[0])
+		threads, err := islack.ListThreads(ctx, client, channelID, opts)
+		if err != nil {
+			islack.InvalidateChannelOnError(client, args[0], err)
+			output.PrintError(err)
+		}
+		if threadListLimit > 0 && len(threads) > threadListLimit {
+			threads = threads[:threadListLimit]
+		}
+
+		summaries := make([]*islack.ThreadSummary, len(threads))
+		for i, t := range threads {
+			summaries[i] = t
+		}
+		resolveThreads(ctx, client, summaries)
+
+		if threadListOutput == "text" {
+			for _, t := range threads {
+				fmt.Printf("%s\t%d\t%s\t%s\t%s\t%s\n", t.LastActivity, t.ReplyCount,
+					strings.Join(t.Participants, ","), t.Author, t.Snippet, t.Permalink)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"channel_id": channelID,
+			"name":       channelName,
+			"threads":    threads,
+		})
+	},
+}
+
+var threadsCmd = &cobra.Command{
+	Use:   "threads",
+	Short: "List threads you follow (Slack's Threads view)",
+	Long: `List the threads in your Threads view (those you started, replied to, or follow)
+across all channels, most recently active first, with their unread replies.
+
+--unread-only keeps threads with unread replies. --output text prints a tab-separated
+table of last activity, unread and total reply counts, channel, author, snippet, and permalink.
+
+This reads subscriptions.thread.getView, the undocumented method the Slack client uses
+for its Threads view, so it may change without notice.
+
+Examples:
+  slack-reader threads --workspace myteam --unread-only
+  slack-reader threads --workspace myteam --limit 50 --output text`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if threadsOutput != "json" && threadsOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", threadsOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		threads, err := islack.ListSubscribedThreads(ctx, client, islack.SubscribedThreadOptions{
+			UnreadOnly: threadsUnreadOnly,
+			Limit:      threadsLimit,
+		})
+		if err != nil {
+			output.PrintError(err)
+		}
+		summaries := make([]*islack.ThreadSummary, len(threads))
+		for i, t := range threads {
+			summaries[i] = &t.ThreadSummary
+		}
+		resolveThreads(ctx, client, summaries)
+
+		if threadsOutput == "text" {
+			for _, t := range threads {
+				fmt.Printf("%s\t%d\t%d\t%s\t%s\t%s\t%s\n", t.LastActivity, t.UnreadCount, t.ReplyCount,
+					t.ChannelID, t.Author, t.Snippet, t.Permalink)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{"threads": threads})
+	},
+}
+
+// resolveThreads fills in thread authors' and participants' display names, and permalinks.
+func resolveThreads(ctx context.Context, client *islack.Client, threads []*islack.ThreadSummary) {
+	var people []map[string]any
+	for _, t := range threads {
+		people = append(people, map[string]any{"user": t.User})
+		for _, id := range t.ReplyUsers {
+			people = append(people, map[string]any{"user": id})
+		}
+	}
+	users := islack.NewUserProvider(client)
+	users.Prefetch(ctx, people)
+
+	for _, t := range threads {
+		if t.User != "" {
+			t.Author, _ = users.UsernameForID(t.User)
+		}
+		for _, id := range t.ReplyUsers {
+			name, _ := users.UsernameForID(id)
+			t.Participants = append(t.Participants, name)
+		}
+		if t.ChannelID != "" {
+			t.Permalink = islack.Permalink(workspace, t.ChannelID, t.TS, "")
+		}
+	}
+}
+
+func init() {
+	threadListCmd.Flags().StringVar(&threadListSince, "since", "7d", "Only threads whose root was posted after this time (e.g., 24h, 7d, 2026-01-31)")
+	threadListCmd.Flags().StringVar(&threadListUntil, "until", "", "Only threads whose root was posted before this time (default: now)")
+	threadListCmd.Flags().IntVar(&threadListLimit, "limit", 0, "Maximum number of threads, most recently active first (0 = all)")
+	threadListCmd.Flags().StringVarP(&threadListOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+
+	threadsCmd.Flags().BoolVar(&threadsUnreadOnly, "unread-only", false, "Only threads with unread replies")
+	threadsCmd.Flags().IntVar(&threadsLimit, "limit", 20, "Maximum number of threads")
+	threadsCmd.Flags().StringVarP(&threadsOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+
+	threadCmd.AddCommand(threadListCmd)
+	rootCmd.AddCommand(threadCmd)
+	rootCmd.AddCommand(threadsCmd)
+}
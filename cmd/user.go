@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	"github.com/sethrylan/slack-reader/internal/report"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	userEmail string
+
+	userSearchLimit  int
+	userSearchOutput string
+
+	userActivitySince    string
+	userActivityChannels []string
+	userActivitySamples  int
+	userActivityOutput   string
+
+	userExportOut    string
+	userExportOutput string
+
+	userListAdmins bool
+	userListOutput string
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "User operations",
+}
+
+var userGetCmd = &cobra.Command{
+	Use:   "get [<user>]",
+	Short: "Show a user's profile",
+	Long: `Print a user object (profile, title, time zone, and whether the user is a bot, admin,
+or guest), found by @handle, user ID, or --email. Users who have left the workspace are
+marked "deactivated": true.
+
+To help judge whether a reply can be expected soon, the output adds the user's custom
+status (status: emoji, text, and when it expires) and do-not-disturb state from dnd.info
+(dnd: the next daily DND window, any snooze, and whether notifications are off now, as
+dnd.active).
+
+--email uses users.lookupByEmail. Tokens that may not call it (such as cookie tokens)
+scan users.list for the address instead, which is slower in large workspaces.
+
+Examples:
+  slack-reader user get @alice --workspace myteam
+  slack-reader user get U0123ABCD --workspace myteam
+  slack-reader user get --email alice@example.com --workspace myteam`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if (len(args) == 0) == (userEmail == "") {
+			output.Exit(errors.New("give either a user or --email"), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		var user map[string]any
+		var err error
+		if userEmail != "" {
+			user, err = islack.LookupUserByEmail(ctx, client, userEmail)
+		} else {
+			var userID string
+			if userID, err = islack.ResolveUserID(ctx, client, args[0]); err == nil {
+				user, err = islack.GetUser(ctx, client, userID)
+			}
+		}
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if deleted, _ := user["deleted"].(bool); deleted {
+			user["deactivated"] = true
+		}
+		// Status and do-not-disturb tell whether a reply can be expected soon.
+		if status := islack.StatusOf(user); status != nil {
+			user["status"] = status
+		}
+		id, _ := user["id"].(string)
+		if dnd, err := islack.GetDND(ctx, client, id, time.Now()); err != nil {
+			slog.Info("could not get do-not-disturb state", "user", id, "error", err)
+		} else {
+			user["dnd"] = dnd
+		}
+		output.PrintJSON(user)
+	},
+}
+
+var userSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find users by part of their name",
+	Long: `Find the users whose username, display name, real name, or title contains a name
+fragment, best match first, with their IDs. Use it when a handle is not known, or when
+someone's display name differs from their username.
+
+Exact matches rank first, then names (or words in them) starting with the query, then
+names containing it, then near misses. Deactivated users (marked "deactivated": true)
+and bots rank after people. matched_on tells which field matched.
+
+The member directory (users.list) is cached for a day; clear it with cache clear.
+--output text prints a tab-separated table of ID, username, display name, real name,
+and title.
+
+Examples:
+  slack-reader user search ali --workspace myteam
+  slack-reader user search "Alice Sm" --workspace myteam --output text`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if userSearchOutput != "json" && userSearchOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", userSearchOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		users, err := islack.ListUsers(ctx, client)
+		if err != nil {
+			output.PrintError(err)
+		}
+		matches := islack.SearchUsers(users, args[0])
+		if userSearchLimit > 0 && len(matches) > userSearchLimit {
+			matches = matches[:userSearchLimit]
+		}
+
+		if userSearchOutput == "text" {
+			for _, m := range matches {
+				fmt.Printf("%s\t%s\t%s\t%s\t%s\n", m.ID, m.Name, m.DisplayName, m.RealName, m.Title)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"query": args[0],
+			"users": matches,
+		})
+	},
+}
+
+var userActivityCmd = &cobra.Command{
+	Use:   "activity <user>",
+	Short: "Summarize where and how much a user posted",
+	Long: `Count a user's messages per conversation since --since (30 days by default), most
+active first, with when they last posted there and their most recent messages, e.g.
+for writing a review or catching up on someone's updates.
+
+Messages are found with Slack search (from:@user), which covers the conversations you
+can search. --channels also scans those channels' history, for conversations search
+misses or when search is unavailable to your token; both sources are merged.
+
+--since accepts a duration before now (24h, 30d), a date (2026-01-31), an RFC 3339 time,
+or a Slack timestamp. --output text prints a tab-separated table of channel, message
+count, and last posted time. In a terminal, omitting the user opens a picker.
+
+Examples:
+  slack-reader user activity @alice --workspace myteam
+  slack-reader user activity @alice --workspace myteam --since 90d --samples 5
+  slack-reader user activity @alice --workspace myteam --channels "#eng,#design" --output text`,
+	Args: userArgs(cobra.ExactArgs(1)),
+	Run: func(_ *cobra.Command, args []string) {
+		if userActivityOutput != "json" && userActivityOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", userActivityOutput), output.ExitUsage)
+		}
+		if userActivitySamples < 0 {
+			output.Exit(errors.New("--samples must not be negative"), output.ExitUsage)
+		}
+		since, err := islack.ParseTimeSpec(userActivitySince, time.Now())
+		if err != nil {
+			output.Exit(fmt.Errorf("--since: %w", err), output.ExitUsage)
+		}
+
+		client := newClient()
+		input, err := userInput(client, args)
+		if err != nil {
+			output.PrintError(err)
+		}
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		userID, err := islack.ResolveUserID(ctx, client, input)
+		if err != nil {
+			output.PrintError(err)
+		}
+		activity, err := report.ComputeUserActivity(userMessages(ctx, client, userID, since), report.UserActivityOptions{
+			UserID:  userID,
+			Since:   since,
+			Samples: userActivitySamples,
+		})
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if userActivityOutput == "text" {
+			for _, c := range activity.Channels {
+				fmt.Printf("%s\t%d\t%s\n", channelHeading(c.ChannelID, c.Channel), c.Messages, c.LastPosted)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"user_id":  userID,
+			"since":    since.UTC().Format(time.RFC3339),
+			"total":    activity.Total,
+			"channels": activity.Channels,
+		})
+	},
+}
+
+// userMessages streams a user's messages since a time: search results, then the history
+// of each of --channels. A failed search is skipped when there are channels to scan.
+func userMessages(ctx context.Context, client *islack.Client, userID string, since time.Time) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		// after: is exclusive, so search from the day before.
+		query := fmt.Sprintf("from:<@%s> after:%s", userID, since.AddDate(0, 0, -1).Format(time.DateOnly))
+		for msg, err := range islack.SearchMessages(ctx, client, query) {
+			if err != nil && len(userActivityChannels) > 0 {
+				slog.Info("search failed, scanning --channels only", "error", err)
+				break
+			}
+			if !yield(msg, err) || err != nil {
+				return
+			}
+		}
+
+		for _, input := range userActivityChannels {
+			channelID, channelName := resolveChannel(ctx, client, input)
+			opts := islack.HistoryOptions{Oldest: islack.FormatTimestamp(since)}
+			for msg, err := range islack.IterChannelHistory(ctx, client, channelID, opts) {
+				if err != nil {
+					islack.InvalidateChannelOnError(client, input, err)
+					yield(nil, err)
+					return
+				}
+				ts, _ := msg["ts"].(string)
+				msg["channel"], msg["channel_name"] = channelID, channelName
+				msg["permalink"] = islack.Permalink(client.Domain(), channelID, ts, "")
+				if !yield(msg, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workspace members, or only admins and owners",
+	Long: `List the members of the workspace from the cached member directory (users.list), with
+their names, title, guest kind, and role ("primary_owner", "owner", or "admin").
+
+--admins-only lists the active admins and owners, primary owner first, e.g. to find
+who can change permissions or restore an archived channel.
+
+--output text prints one member per line: ID, @name, real name, and role, tab-separated.
+
+Examples:
+  slack-reader user list --workspace myteam --admins-only
+  slack-reader user list --workspace myteam --admins-only --output text`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if userListOutput != "json" && userListOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", userListOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		users, err := islack.ListUsers(ctx, client)
+		if err != nil {
+			output.PrintError(err)
+		}
+		var members []islack.Member
+		if userListAdmins {
+			members = islack.Admins(users)
+		} else {
+			members = make([]islack.Member, len(users))
+			for i, u := range users {
+				members[i] = islack.NewMember(u)
+			}
+		}
+
+		if userListOutput == "text" {
+			for _, m := range members {
+				fmt.Printf("%s\t@%s\t%s\t%s\n", m.ID, m.Name, m.RealName, m.Role)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{"users": members})
+	},
+}
+
+var userExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the workspace member directory as CSV or JSON",
+	Long: `Write every member of the workspace (users.list, all pages) with their ID, username,
+display name, real name, email (when visible to your token), title, status, and whether
+they are a bot or deactivated (deleted), e.g. to join against an HR system.
+
+--out writes to a file, in the format its extension names (.csv or .json); otherwise
+the directory is printed in the --output format. The export always reads users.list
+afresh, and refreshes the directory user search uses.
+
+Examples:
+  slack-reader user export --workspace myteam --out users.csv
+  slack-reader user export --workspace myteam --out users.json
+  slack-reader user export --workspace myteam --output csv | grep -v ',true$'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		format := userExportOutput
+		if ext := strings.ToLower(filepath.Ext(userExportOut)); ext == ".csv" || ext == ".json" {
+			if !cmd.Flags().Changed("output") {
+				format = ext[1:]
+			}
+		}
+		if format != "json" && format != "csv" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or csv)", format), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		users, err := islack.RefreshUsers(ctx, client)
+		if err != nil {
+			output.PrintError(err)
+		}
+		entries := make([]islack.DirectoryEntry, len(users))
+		for i, u := range users {
+			entries[i] = islack.NewDirectoryEntry(u)
+		}
+
+		w := os.Stdout
+		if userExportOut != "" {
+			if w, err = os.Create(userExportOut); err != nil {
+				output.PrintError(err)
+			}
+		}
+		if format == "csv" {
+			err = writeDirectoryCSV(w, entries)
+		} else {
+			err = writeDirectoryJSON(w, entries)
+		}
+		if userExportOut != "" {
+			err = errors.Join(err, w.Close())
+		}
+		if err != nil {
+			output.PrintError(err)
+		}
+		if userExportOut != "" {
+			fmt.Fprintf(os.Stderr, "%d users written to %s\n", len(entries), userExportOut)
+		}
+	},
+}
+
+func writeDirectoryCSV(w io.Writer, entries []islack.DirectoryEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(islack.DirectoryColumns); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write(e.Row()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeDirectoryJSON writes entries as a JSON array. Empty fields are kept, unlike
+// PrintJSON, so every entry has the same columns.
+func writeDirectoryJSON(w io.Writer, entries []islack.DirectoryEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func init() {
+	userGetCmd.Flags().StringVar(&userEmail, "email", "", "Find the user by email address instead")
+	userSearchCmd.Flags().IntVar(&userSearchLimit, "limit", 10, "Maximum number of users (0 = all matches)")
+	userSearchCmd.Flags().StringVarP(&userSearchOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+
+	userActivityCmd.Flags().StringVar(&userActivitySince, "since", "30d", "Only messages after this time (e.g., 7d, 2026-01-31)")
+	userActivityCmd.Flags().StringSliceVar(&userActivityChannels, "channels", nil, "Also scan these channels' history, comma-separated")
+	userActivityCmd.Flags().IntVar(&userActivitySamples, "samples", 3, "Most recent messages to include per channel")
+	userActivityCmd.Flags().StringVarP(&userActivityOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+
+	userExportCmd.Flags().StringVar(&userExportOut, "out", "", "Write to this file (.csv or .json) instead of stdout")
+	userExportCmd.Flags().StringVarP(&userExportOutput, "output", "o", "json", "Output format: json or csv (default: from the --out extension)")
+
+	userListCmd.Flags().BoolVar(&userListAdmins, "admins-only", false, "Only list active admins and owners")
+	userListCmd.Flags().StringVarP(&userListOutput, "output", "o", "json", "Output format: json or text (one user per line)")
+
+	userCmd.AddCommand(userGetCmd)
+	userCmd.AddCommand(userListCmd)
+	userCmd.AddCommand(userSearchCmd)
+	userCmd.AddCommand(userActivityCmd)
+	userCmd.AddCommand(userExportCmd)
+	rootCmd.AddCommand(userCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	usergroupDisabled bool
+	usergroupOutput   string
+
+	usergroupMembersOutput string
+)
+
+var usergroupCmd = &cobra.Command{
+	Use:   "usergroup",
+	Short: "User group operations",
+}
+
+var usergroupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the workspace's user groups",
+	Long: `List the workspace's user groups (usergroups.list): ID, handle, name, description, and
+member count. --disabled includes disabled groups, marked "disabled": true.
+
+--output text prints a tab-separated table of ID, @handle, name, and member count.
+
+Examples:
+  slack-reader usergroup list --workspace myteam
+  slack-reader usergroup list --workspace myteam --disabled --output text`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if usergroupOutput != "json" && usergroupOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", usergroupOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		groups, err := islack.ListUsergroups(ctx, client, usergroupDisabled)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if usergroupOutput == "text" {
+			for _, g := range groups {
+				fmt.Printf("%s\t@%s\t%s\t%d\n", g.ID, g.Handle, g.Name, g.UserCount)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{"usergroups": groups})
+	},
+}
+
+var usergroupMembersCmd = &cobra.Command{
+	Use:   "members <usergroup>",
+	Short: "List a user group's members",
+	Long: `List the members of a user group (usergroups.users.list), with their names and whether
+they are guests, as in channel members. The group is given by @handle, name, ID (S...),
+or a pasted <!subteam^...> mention.
+
+--output text prints one member per line: ID, @name, and real name, tab-separated.
+
+Examples:
+  slack-reader usergroup members @backend-team --workspace myteam
+  slack-reader usergroup members @oncall --workspace myteam --output text`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if usergroupMembersOutput != "json" && usergroupMembersOutput != "text" {
+			output.Exit(fmt.Errorf("invalid --output %q (want json or text)", usergroupMembersOutput), output.ExitUsage)
+		}
+
+		client := newClient()
+		ctx, cancel := commandContext()
+		defer cancel()
+
+		groupID, err := islack.ResolveUsergroupID(ctx, client, args[0])
+		if err != nil {
+			output.PrintError(err)
+		}
+		members, err := islack.UsergroupMembers(ctx, client, groupID)
+		if err != nil {
+			output.PrintError(err)
+		}
+
+		if usergroupMembersOutput == "text" {
+			for _, m := range members {
+				fmt.Printf("%s\t@%s\t%s\n", m.ID, m.Name, m.RealName)
+			}
+			return
+		}
+		output.PrintJSON(map[string]any{
+			"usergroup_id": groupID,
+			"members":      members,
+		})
+	},
+}
+
+func init() {
+	usergroupListCmd.Flags().BoolVar(&usergroupDisabled, "disabled", false, "Include disabled user groups")
+	usergroupListCmd.Flags().StringVarP(&usergroupOutput, "output", "o", "json", "Output format: json or text (tab-separated table)")
+	usergroupMembersCmd.Flags().StringVarP(&usergroupMembersOutput, "output", "o", "json", "Output format: json or text (one member per line)")
+
+	usergroupCmd.AddCommand(usergroupListCmd)
+	usergroupCmd.AddCommand(usergroupMembersCmd)
+	rootCmd.AddCommand(usergroupCmd)
+}
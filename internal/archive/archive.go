@@ -0,0 +1,512 @@
+// Package archive maintains a local SQLite archive of Slack channels, messages, threads, and users.
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS channels (
+	id        TEXT PRIMARY KEY,
+	name      TEXT NOT NULL DEFAULT '',
+	data      TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL DEFAULT '',
+	display_name TEXT NOT NULL DEFAULT '',
+	data         TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+	channel_id TEXT NOT NULL,
+	ts         TEXT NOT NULL,
+	thread_ts  TEXT NOT NULL DEFAULT '',
+	user_id    TEXT NOT NULL DEFAULT '',
+	text       TEXT NOT NULL DEFAULT '',
+	data       TEXT NOT NULL,
+	deleted_at INTEGER NOT NULL DEFAULT 0, -- when a sync found the message gone from Slack (0 = not deleted)
+	PRIMARY KEY (channel_id, ts)
+);
+CREATE INDEX IF NOT EXISTS messages_thread ON messages (channel_id, thread_ts);
+CREATE INDEX IF NOT EXISTS messages_user ON messages (user_id);
+
+-- Prior versions of edited messages, captured when a sync re-fetches a message whose text changed.
+CREATE TABLE IF NOT EXISTS message_versions (
+	channel_id  TEXT NOT NULL,
+	ts          TEXT NOT NULL,
+	edited_ts   TEXT NOT NULL DEFAULT '',
+	text        TEXT NOT NULL DEFAULT '',
+	data        TEXT NOT NULL,
+	replaced_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS message_versions_message ON message_versions (channel_id, ts);
+
+CREATE TABLE IF NOT EXISTS threads (
+	channel_id   TEXT NOT NULL,
+	thread_ts    TEXT NOT NULL,
+	reply_count  INTEGER NOT NULL DEFAULT 0,
+	latest_reply TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (channel_id, thread_ts)
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5 (
+	text, content = 'messages', content_rowid = 'rowid'
+);
+CREATE TRIGGER IF NOT EXISTS messages_fts_insert AFTER INSERT ON messages BEGIN
+	INSERT INTO messages_fts (rowid, text) VALUES (new.rowid, new.text);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_delete AFTER DELETE ON messages BEGIN
+	INSERT INTO messages_fts (messages_fts, rowid, text) VALUES ('delete', old.rowid, old.text);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_update AFTER UPDATE ON messages BEGIN
+	INSERT INTO messages_fts (messages_fts, rowid, text) VALUES ('delete', old.rowid, old.text);
+	INSERT INTO messages_fts (rowid, text) VALUES (new.rowid, new.text);
+END;
+
+CREATE TABLE IF NOT EXISTS sync_state (
+	channel_id TEXT PRIMARY KEY,
+	latest_ts  TEXT NOT NULL,
+	synced_at  INTEGER NOT NULL
+);
+`
+
+// DB is a handle to a local archive database.
+type DB struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the default archive location for a workspace,
+// under $XDG_DATA_HOME (or ~/.local/share)/slack-reader.
+func DefaultPath(workspace string) (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locate home dir: %w", err)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "slack-reader", workspace+".db"), nil
+}
+
+// Open opens (creating if needed) the archive at path.
+func Open(ctx context.Context, path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	// SQLite allows a single writer; serialize access rather than fight SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	var hadFTS int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE name = 'messages_fts'`).Scan(&hadFTS); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("inspect archive schema: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create archive schema: %w", err)
+	}
+
+	// Archives created before deletions were tracked lack the tombstone column.
+	var hasDeletedAt int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info('messages') WHERE name = 'deleted_at'`).Scan(&hasDeletedAt); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("inspect archive schema: %w", err)
+	}
+	if hasDeletedAt == 0 {
+		if _, err := db.ExecContext(ctx, `ALTER TABLE messages ADD COLUMN deleted_at INTEGER NOT NULL DEFAULT 0`); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("upgrade archive schema: %w", err)
+		}
+	}
+
+	// Archives created before full-text search existed need their index built once.
+	if hadFTS == 0 {
+		if _, err := db.ExecContext(ctx, `INSERT INTO messages_fts (messages_fts) VALUES ('rebuild')`); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("build search index: %w", err)
+		}
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the archive.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// UpsertChannel stores a conversation object.
+func (d *DB) UpsertChannel(ctx context.Context, channel map[string]any) error {
+	id, _ := channel["id"].(string)
+	if id == "" {
+		return errors.New("channel has no id")
+	}
+	name, _ := channel["name"].(string)
+	data, err := json.Marshal(channel)
+	if err != nil {
+		return fmt.Errorf("marshal channel %s: %w", id, err)
+	}
+
+	_, err = d.db.ExecContext(ctx, `
+		INSERT INTO channels (id, name, data) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, data = excluded.data`,
+		id, name, string(data))
+	if err != nil {
+		return fmt.Errorf("store channel %s: %w", id, err)
+	}
+	return nil
+}
+
+// UpsertUser stores a user object along with its resolved display name.
+func (d *DB) UpsertUser(ctx context.Context, user map[string]any, displayName string) error {
+	id, _ := user["id"].(string)
+	if id == "" {
+		return errors.New("user has no id")
+	}
+	name, _ := user["name"].(string)
+	data, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("marshal user %s: %w", id, err)
+	}
+
+	_, err = d.db.ExecContext(ctx, `
+		INSERT INTO users (id, name, display_name, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name, display_name = excluded.display_name, data = excluded.data`,
+		id, name, displayName, string(data))
+	if err != nil {
+		return fmt.Errorf("store user %s: %w", id, err)
+	}
+	return nil
+}
+
+// HasUser reports whether a user is already archived.
+func (d *DB) HasUser(ctx context.Context, id string) (bool, error) {
+	var n int
+	err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE id = ?`, id).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("look up user %s: %w", id, err)
+	}
+	return n > 0, nil
+}
+
+// UpsertMessages stores messages for a channel in a single transaction.
+// When an archived message's text has changed, its previous version is kept in
+// message_versions so edits between syncs can be compared.
+func (d *DB) UpsertMessages(ctx context.Context, channelID string, messages []map[string]any) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().Unix()
+	for _, msg := range messages {
+		ts, _ := msg["ts"].(string)
+		if ts == "" {
+			continue
+		}
+		threadTS, _ := msg["thread_ts"].(string)
+		userID, _ := msg["user"].(string)
+		text, _ := msg["text"].(string)
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("marshal message %s: %w", ts, err)
+		}
+
+		if err := keepPriorVersion(ctx, tx, channelID, ts, text, now); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO messages (channel_id, ts, thread_ts, user_id, text, data) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (channel_id, ts) DO UPDATE SET
+				thread_ts = excluded.thread_ts, user_id = excluded.user_id,
+				text = excluded.text, data = excluded.data, deleted_at = 0`,
+			channelID, ts, threadTS, userID, text, string(data))
+		if err != nil {
+			return fmt.Errorf("store message %s: %w", ts, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// keepPriorVersion copies the archived version of a message into message_versions
+// if its text differs from text.
+func keepPriorVersion(ctx context.Context, tx *sql.Tx, channelID, ts, text string, now int64) error {
+	var oldText, oldData string
+	err := tx.QueryRowContext(ctx,
+		`SELECT text, data FROM messages WHERE channel_id = ? AND ts = ?`,
+		channelID, ts).Scan(&oldText, &oldData)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("look up message %s: %w", ts, err)
+	}
+	if oldText == text {
+		return nil
+	}
+
+	var old map[string]any
+	_ = json.Unmarshal([]byte(oldData), &old)
+	edited, _ := old["edited"].(map[string]any)
+	editedTS, _ := edited["ts"].(string)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO message_versions (channel_id, ts, edited_ts, text, data, replaced_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		channelID, ts, editedTS, oldText, oldData, now)
+	if err != nil {
+		return fmt.Errorf("store prior version of message %s: %w", ts, err)
+	}
+	return nil
+}
+
+// MessageVersion is one version of an archived message's text.
+type MessageVersion struct {
+	Text string `json:"text"`
+	// EditedTS is the Slack timestamp of the edit that produced this version;
+	// empty for the original text.
+	EditedTS string `json:"edited_ts,omitempty"`
+	// ReplacedAt is when a sync replaced this version (RFC 3339); empty for the current version.
+	ReplacedAt string `json:"replaced_at,omitempty"`
+	Current    bool   `json:"current,omitempty"`
+}
+
+// MessageVersions returns the archived versions of a message, oldest first, ending
+// with the current one. It returns nil if the message is not archived.
+func (d *DB) MessageVersions(ctx context.Context, channelID, ts string) ([]MessageVersion, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT text, edited_ts, replaced_at FROM message_versions
+		WHERE channel_id = ? AND ts = ? ORDER BY replaced_at, rowid`,
+		channelID, ts)
+	if err != nil {
+		return nil, fmt.Errorf("query versions of %s: %w", ts, err)
+	}
+	defer rows.Close()
+
+	var versions []MessageVersion
+	for rows.Next() {
+		var v MessageVersion
+		var replacedAt int64
+		if err := rows.Scan(&v.Text, &v.EditedTS, &replacedAt); err != nil {
+			return nil, fmt.Errorf("scan version: %w", err)
+		}
+		v.ReplacedAt = time.Unix(replacedAt, 0).UTC().Format(time.RFC3339)
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query versions of %s: %w", ts, err)
+	}
+
+	var text, data string
+	err = d.db.QueryRowContext(ctx,
+		`SELECT text, data FROM messages WHERE channel_id = ? AND ts = ?`,
+		channelID, ts).Scan(&text, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("look up message %s: %w", ts, err)
+	}
+	var msg map[string]any
+	_ = json.Unmarshal([]byte(data), &msg)
+	edited, _ := msg["edited"].(map[string]any)
+	editedTS, _ := edited["ts"].(string)
+	return append(versions, MessageVersion{Text: text, EditedTS: editedTS, Current: true}), nil
+}
+
+// Thread returns the reply count and latest_reply recorded for a thread, or 0 and "" if
+// the thread is not archived.
+func (d *DB) Thread(ctx context.Context, channelID, threadTS string) (replyCount int, latestReply string, err error) {
+	err = d.db.QueryRowContext(ctx,
+		`SELECT reply_count, latest_reply FROM threads WHERE channel_id = ? AND thread_ts = ?`,
+		channelID, threadTS).Scan(&replyCount, &latestReply)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("look up thread %s: %w", threadTS, err)
+	}
+	return replyCount, latestReply, nil
+}
+
+// TombstoneMissing marks top-level messages archived after oldest that are not in
+// fetched as deleted at the given time, and returns how many were marked. fetched must
+// be the complete channel history after oldest.
+func (d *DB) TombstoneMissing(ctx context.Context, channelID, oldest string, fetched []map[string]any, at time.Time) (int, error) {
+	return d.tombstone(ctx, `channel_id = ? AND (thread_ts = '' OR thread_ts = ts) AND ts > ?`,
+		[]any{channelID, oldest}, fetched, at)
+}
+
+// TombstoneMissingReplies marks archived replies of a thread that are not in fetched,
+// the thread's complete replies, as deleted at the given time, and returns how many were marked.
+func (d *DB) TombstoneMissingReplies(ctx context.Context, channelID, threadTS string, fetched []map[string]any, at time.Time) (int, error) {
+	return d.tombstone(ctx, `channel_id = ? AND thread_ts = ? AND ts <> thread_ts`,
+		[]any{channelID, threadTS}, fetched, at)
+}
+
+// tombstone sets deleted_at on live messages matching where whose ts is not in fetched.
+func (d *DB) tombstone(ctx context.Context, where string, args []any, fetched []map[string]any, at time.Time) (int, error) {
+	present := make(map[string]bool, len(fetched))
+	for _, msg := range fetched {
+		if ts, _ := msg["ts"].(string); ts != "" {
+			present[ts] = true
+		}
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `SELECT channel_id, ts FROM messages WHERE deleted_at = 0 AND `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("query archived messages: %w", err)
+	}
+	var missing [][2]string
+	for rows.Next() {
+		var channelID, ts string
+		if err := rows.Scan(&channelID, &ts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan message: %w", err)
+		}
+		if !present[ts] {
+			missing = append(missing, [2]string{channelID, ts})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("query archived messages: %w", err)
+	}
+
+	for _, m := range missing {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE messages SET deleted_at = ? WHERE channel_id = ? AND ts = ?`,
+			at.Unix(), m[0], m[1]); err != nil {
+			return 0, fmt.Errorf("mark message %s deleted: %w", m[1], err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(missing), nil
+}
+
+// UpsertThread records a thread's reply count and latest reply.
+func (d *DB) UpsertThread(ctx context.Context, channelID, threadTS string, replyCount int, latestReply string) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO threads (channel_id, thread_ts, reply_count, latest_reply) VALUES (?, ?, ?, ?)
+		ON CONFLICT (channel_id, thread_ts) DO UPDATE SET
+			reply_count = excluded.reply_count, latest_reply = excluded.latest_reply`,
+		channelID, threadTS, replyCount, latestReply)
+	if err != nil {
+		return fmt.Errorf("store thread %s: %w", threadTS, err)
+	}
+	return nil
+}
+
+// LatestTS returns the newest synced message timestamp for a channel, or "" if never synced.
+func (d *DB) LatestTS(ctx context.Context, channelID string) (string, error) {
+	var latest string
+	err := d.db.QueryRowContext(ctx, `SELECT latest_ts FROM sync_state WHERE channel_id = ?`, channelID).Scan(&latest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up sync state: %w", err)
+	}
+	return latest, nil
+}
+
+// SetSyncState records the newest synced timestamp for a channel.
+func (d *DB) SetSyncState(ctx context.Context, channelID, latestTS string, syncedAt time.Time) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO sync_state (channel_id, latest_ts, synced_at) VALUES (?, ?, ?)
+		ON CONFLICT (channel_id) DO UPDATE SET latest_ts = excluded.latest_ts, synced_at = excluded.synced_at`,
+		channelID, latestTS, syncedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("store sync state: %w", err)
+	}
+	return nil
+}
+
+// ChannelStatus summarizes the archived contents of one channel.
+type ChannelStatus struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Messages int    `json:"messages"`
+	Threads  int    `json:"threads"`
+	Deleted  int    `json:"deleted,omitempty"`
+	LatestTS string `json:"latest_ts,omitempty"`
+	SyncedAt string `json:"synced_at,omitempty"`
+}
+
+// Status summarizes the whole archive.
+type Status struct {
+	Path     string          `json:"path"`
+	Users    int             `json:"users"`
+	Channels []ChannelStatus `json:"channels"`
+}
+
+// Status reports per-channel message and thread counts and sync state.
+func (d *DB) Status(ctx context.Context) (*Status, error) {
+	status := &Status{}
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&status.Users); err != nil {
+		return nil, fmt.Errorf("count users: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT c.id, c.name,
+			(SELECT COUNT(*) FROM messages m WHERE m.channel_id = c.id),
+			(SELECT COUNT(*) FROM threads t WHERE t.channel_id = c.id),
+			(SELECT COUNT(*) FROM messages m WHERE m.channel_id = c.id AND m.deleted_at > 0),
+			COALESCE(s.latest_ts, ''), COALESCE(s.synced_at, 0)
+		FROM channels c LEFT JOIN sync_state s ON s.channel_id = c.id
+		ORDER BY c.name`)
+	if err != nil {
+		return nil, fmt.Errorf("query channels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cs ChannelStatus
+		var syncedAt int64
+		if err := rows.Scan(&cs.ID, &cs.Name, &cs.Messages, &cs.Threads, &cs.Deleted, &cs.LatestTS, &syncedAt); err != nil {
+			return nil, fmt.Errorf("scan channel: %w", err)
+		}
+		if syncedAt > 0 {
+			cs.SyncedAt = time.Unix(syncedAt, 0).UTC().Format(time.RFC3339)
+		}
+		status.Channels = append(status.Channels, cs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query channels: %w", err)
+	}
+	return status, nil
+}
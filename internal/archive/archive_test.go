@@ -0,0 +1,44 @@
+package archive_test
+
+import "testing"
+
+func TestMessageVersions(t *testing.T) {
+	db := openTestDB(t)
+	ctx := t.Context()
+
+	upsert := func(msg map[string]any) {
+		t.Helper()
+		if err := db.UpsertMessages(ctx, "C1", []map[string]any{msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	upsert(map[string]any{"ts": "1770000000.000001", "user": "U1", "text": "deploy at 3"})
+	// Re-syncing an unchanged message keeps no version.
+	upsert(map[string]any{"ts": "1770000000.000001", "user": "U1", "text": "deploy at 3", "reply_count": float64(1)})
+	upsert(map[string]any{
+		"ts": "1770000000.000001", "user": "U1", "text": "deploy at 4",
+		"edited": map[string]any{"user": "U1", "ts": "1770000100.000000"},
+	})
+
+	versions, err := db.MessageVersions(ctx, "C1", "1770000000.000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2: %+v", len(versions), versions)
+	}
+	if v := versions[0]; v.Text != "deploy at 3" || v.EditedTS != "" || v.ReplacedAt == "" || v.Current {
+		t.Errorf("prior version = %+v", v)
+	}
+	if v := versions[1]; v.Text != "deploy at 4" || v.EditedTS != "1770000100.000000" || !v.Current {
+		t.Errorf("current version = %+v", v)
+	}
+
+	versions, err = db.MessageVersions(ctx, "C1", "1770000000.000009")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions != nil {
+		t.Errorf("versions of unarchived message = %+v, want nil", versions)
+	}
+}
@@ -0,0 +1,215 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// SearchOptions filters an archive search.
+type SearchOptions struct {
+	Query     string
+	ChannelID string // restrict to one channel (optional)
+	UserID    string // restrict to one author (optional)
+	Limit     int    // maximum matches (0 = 20)
+	Context   int    // messages of surrounding context before and after each match
+	Domain    string // workspace domain, used to build permalinks
+}
+
+// ContextMessage is a message surrounding a search match.
+type ContextMessage struct {
+	TS       string `json:"ts"`
+	User     string `json:"user,omitempty"`
+	Username string `json:"username,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// SearchMatch is a single archived message matching a search.
+type SearchMatch struct {
+	ChannelID string           `json:"channel_id"`
+	Channel   string           `json:"channel,omitempty"`
+	TS        string           `json:"ts"`
+	ThreadTS  string           `json:"thread_ts,omitempty"`
+	User      string           `json:"user,omitempty"`
+	Username  string           `json:"username,omitempty"`
+	Text      string           `json:"text"`
+	Snippet   string           `json:"snippet,omitempty"`
+	Permalink string           `json:"permalink,omitempty"`
+	DeletedAt string           `json:"deleted_at,omitempty"` // when a sync found the message deleted (RFC 3339)
+	Before    []ContextMessage `json:"before,omitempty"`
+	After     []ContextMessage `json:"after,omitempty"`
+}
+
+// Search runs a full-text search over archived messages, best matches first.
+func (d *DB) Search(ctx context.Context, opts SearchOptions) ([]SearchMatch, error) {
+	query := ftsQuery(opts.Query)
+	if query == "" {
+		return nil, errors.New("search query is empty")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	stmt := `
+		SELECT m.channel_id, COALESCE(c.name, ''), m.ts, m.thread_ts, m.user_id,
+			COALESCE(u.display_name, ''), m.text, m.deleted_at,
+			snippet(messages_fts, 0, '**', '**', '…', 16)
+		FROM messages_fts
+		JOIN messages m ON m.rowid = messages_fts.rowid
+		LEFT JOIN channels c ON c.id = m.channel_id
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE messages_fts MATCH ?`
+	args := []any{query}
+	if opts.ChannelID != "" {
+		stmt += ` AND m.channel_id = ?`
+		args = append(args, opts.ChannelID)
+	}
+	if opts.UserID != "" {
+		stmt += ` AND m.user_id = ?`
+		args = append(args, opts.UserID)
+	}
+	stmt += ` ORDER BY rank LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := d.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []SearchMatch
+	for rows.Next() {
+		var m SearchMatch
+		var deletedAt int64
+		if err := rows.Scan(&m.ChannelID, &m.Channel, &m.TS, &m.ThreadTS, &m.User, &m.Username, &m.Text, &deletedAt, &m.Snippet); err != nil {
+			return nil, fmt.Errorf("scan match: %w", err)
+		}
+		if deletedAt > 0 {
+			m.DeletedAt = time.Unix(deletedAt, 0).UTC().Format(time.RFC3339)
+		}
+		if opts.Domain != "" {
+			m.Permalink = islack.Permalink(opts.Domain, m.ChannelID, m.TS, m.ThreadTS)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	if opts.Context > 0 {
+		for i := range matches {
+			if err := d.addContext(ctx, &matches[i], opts.Context); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return matches, nil
+}
+
+// addContext fills in the n messages before and after a match within the same conversation:
+// the thread for replies, or the top-level channel history otherwise.
+func (d *DB) addContext(ctx context.Context, m *SearchMatch, n int) error {
+	scope := `(m.thread_ts = '' OR m.thread_ts = m.ts)`
+	scopeArg := any(nil)
+	if m.ThreadTS != "" && m.ThreadTS != m.TS {
+		scope = `m.thread_ts = ?`
+		scopeArg = m.ThreadTS
+	}
+
+	query := func(cmp, order string) ([]ContextMessage, error) {
+		stmt := `
+			SELECT m.ts, m.user_id, COALESCE(u.display_name, ''), m.text
+			FROM messages m LEFT JOIN users u ON u.id = m.user_id
+			WHERE m.channel_id = ? AND ` + scope + ` AND m.ts ` + cmp + ` ?
+			ORDER BY m.ts ` + order + ` LIMIT ?`
+		args := []any{m.ChannelID}
+		if scopeArg != nil {
+			args = append(args, scopeArg)
+		}
+		args = append(args, m.TS, n)
+
+		rows, err := d.db.QueryContext(ctx, stmt, args...)
+		if err != nil {
+			return nil, fmt.Errorf("search context: %w", err)
+		}
+		defer rows.Close()
+		return scanContext(rows)
+	}
+
+	before, err := query("<", "DESC")
+	if err != nil {
+		return err
+	}
+	// Fetched newest first; present oldest first.
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+
+	after, err := query(">", "ASC")
+	if err != nil {
+		return err
+	}
+
+	m.Before, m.After = before, after
+	return nil
+}
+
+func scanContext(rows *sql.Rows) ([]ContextMessage, error) {
+	var msgs []ContextMessage
+	for rows.Next() {
+		var c ContextMessage
+		if err := rows.Scan(&c.TS, &c.User, &c.Username, &c.Text); err != nil {
+			return nil, fmt.Errorf("scan context: %w", err)
+		}
+		msgs = append(msgs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search context: %w", err)
+	}
+	return msgs, nil
+}
+
+// LookupChannel finds an archived channel ID by name.
+func (d *DB) LookupChannel(ctx context.Context, name string) (string, error) {
+	var id string
+	err := d.db.QueryRowContext(ctx, `SELECT id FROM channels WHERE name = ?`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("channel not in archive: #%s", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up channel: %w", err)
+	}
+	return id, nil
+}
+
+// LookupUser finds an archived user ID by handle or display name.
+func (d *DB) LookupUser(ctx context.Context, handle string) (string, error) {
+	var id string
+	err := d.db.QueryRowContext(ctx,
+		`SELECT id FROM users WHERE id = ? OR name = ? OR display_name = ? LIMIT 1`,
+		handle, handle, handle).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("user not in archive: @%s", handle)
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up user: %w", err)
+	}
+	return id, nil
+}
+
+// ftsQuery turns free text into an FTS5 query matching all terms, quoting each
+// term so punctuation in user input isn't parsed as FTS5 syntax.
+func ftsQuery(text string) string {
+	fields := strings.Fields(text)
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		terms = append(terms, `"`+strings.ReplaceAll(f, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
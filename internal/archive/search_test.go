@@ -0,0 +1,63 @@
+package archive_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/archive"
+)
+
+func TestSearch(t *testing.T) {
+	db := openTestDB(t)
+	ctx := t.Context()
+
+	if err := db.UpsertChannel(ctx, map[string]any{"id": "C1", "name": "ops"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertUser(ctx, map[string]any{"id": "U1", "name": "alice"}, "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	err := db.UpsertMessages(ctx, "C1", []map[string]any{
+		{"ts": "1770000000.000001", "user": "U2", "text": "deploy starting"},
+		{"ts": "1770000000.000002", "user": "U1", "text": "what is the rollback plan?"},
+		{"ts": "1770000000.000003", "user": "U2", "text": "revert the release"},
+		{"ts": "1770000000.000004", "user": "U2", "text": "plan for lunch"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := db.Search(ctx, archive.SearchOptions{
+		Query:   "rollback plan",
+		Context: 1,
+		Domain:  "myteam",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1: %+v", len(matches), matches)
+	}
+
+	m := matches[0]
+	if m.TS != "1770000000.000002" || m.Channel != "ops" || m.Username != "Alice" {
+		t.Errorf("match = %+v", m)
+	}
+	if m.Permalink != "https://myteam.slack.com/archives/C1/p1770000000000002" {
+		t.Errorf("permalink = %q", m.Permalink)
+	}
+	if len(m.Before) != 1 || m.Before[0].Text != "deploy starting" {
+		t.Errorf("before = %+v, want [deploy starting]", m.Before)
+	}
+	if len(m.After) != 1 || m.After[0].Text != "revert the release" {
+		t.Errorf("after = %+v, want [revert the release]", m.After)
+	}
+
+	// Filtering by author excludes other users' matches.
+	matches, err = db.Search(ctx, archive.SearchOptions{Query: "plan", UserID: "U2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Text != "plan for lunch" {
+		t.Errorf("matches = %+v, want [plan for lunch]", matches)
+	}
+}
@@ -0,0 +1,220 @@
+package archive
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// SyncResult summarizes what a Sync call added to the archive.
+type SyncResult struct {
+	ChannelID      string `json:"channel_id"`
+	Name           string `json:"name,omitempty"`
+	NewMessages    int    `json:"new_messages"`
+	ThreadsUpdated int    `json:"threads_updated"`
+	Replies        int    `json:"replies"`
+	NewUsers       int    `json:"new_users"`
+	Deleted        int    `json:"deleted"`
+	LatestTS       string `json:"latest_ts,omitempty"`
+}
+
+// SyncOptions controls how a channel is synced.
+type SyncOptions struct {
+	// Parallel splits the time range being fetched into this many windows fetched
+	// concurrently. Values <= 1 walk history sequentially.
+	Parallel int
+
+	// PageSize is the number of messages requested per API call (0 = Slack's maximum).
+	PageSize int
+
+	// Recheck re-fetches messages archived since this time, in addition to new ones, so
+	// that edits and deletions within it are detected. The zero value fetches only new messages.
+	Recheck time.Time
+}
+
+// Sync incrementally archives a channel: messages newer than the last sync, replies of any
+// thread whose latest reply or reply count changed, and any authors not yet archived.
+//
+// Archived messages missing from the fetched window, or from a refreshed thread, are
+// tombstoned: kept, with the time the deletion was detected. Without opts.Recheck, the
+// window only holds new messages, so deletions are detected in refreshed threads only.
+//
+// Threads are only refreshed when their parent is in the fetched window, so new replies
+// to parents older than the previous sync (or opts.Recheck) are not picked up.
+func Sync(ctx context.Context, client islack.APIClient, db *DB, channelID string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{ChannelID: channelID}
+
+	channel, err := islack.GetChannelInfo(ctx, client, channelID)
+	if err != nil {
+		return nil, err
+	}
+	result.Name, _ = channel["name"].(string)
+	if err := db.UpsertChannel(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	oldest, err := db.LatestTS(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := oldest
+	if !opts.Recheck.IsZero() && oldest != "" {
+		if recheck := islack.FormatTimestamp(opts.Recheck); recheck < oldest {
+			from = recheck
+		}
+	}
+
+	fetchedAt := time.Now()
+	messages, err := fetchHistory(ctx, client, channelID, channel, from, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UpsertMessages(ctx, channelID, messages); err != nil {
+		return nil, err
+	}
+	for _, msg := range messages {
+		if ts, _ := msg["ts"].(string); ts > oldest {
+			result.NewMessages++
+		}
+	}
+	slog.Info("archived messages", "channel", channelID, "count", result.NewMessages)
+
+	deleted, err := db.TombstoneMissing(ctx, channelID, from, messages, fetchedAt)
+	if err != nil {
+		return nil, err
+	}
+	result.Deleted += deleted
+
+	authors := append([]map[string]any(nil), messages...)
+
+	for _, msg := range messages {
+		replyCount, _ := msg["reply_count"].(float64)
+		threadTS, _ := msg["ts"].(string)
+		latestReply, _ := msg["latest_reply"].(string)
+
+		storedCount, storedLatest, err := db.Thread(ctx, channelID, threadTS)
+		if err != nil {
+			return nil, err
+		}
+		if replyCount == 0 && storedCount == 0 {
+			continue
+		}
+		if storedLatest != "" && storedLatest == latestReply && storedCount == int(replyCount) {
+			continue
+		}
+
+		// A thread whose replies were all deleted has nothing left to fetch.
+		var replies []map[string]any
+		if replyCount > 0 {
+			replies, err = islack.CollectMessages(islack.IterThread(ctx, client, channelID, threadTS,
+				islack.HistoryOptions{PageSize: opts.PageSize}))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := db.UpsertMessages(ctx, channelID, replies); err != nil {
+			return nil, err
+		}
+		deleted, err := db.TombstoneMissingReplies(ctx, channelID, threadTS, replies, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		result.Deleted += deleted
+		if err := db.UpsertThread(ctx, channelID, threadTS, int(replyCount), latestReply); err != nil {
+			return nil, err
+		}
+		authors = append(authors, replies...)
+		result.ThreadsUpdated++
+		result.Replies += len(replies)
+	}
+
+	newUsers, err := syncUsers(ctx, client, db, authors)
+	if err != nil {
+		return nil, err
+	}
+	result.NewUsers = newUsers
+
+	// History is sorted oldest first, so the last message is the newest.
+	latest := oldest
+	if len(messages) > 0 {
+		last, _ := messages[len(messages)-1]["ts"].(string)
+		latest = max(latest, last)
+	}
+	result.LatestTS = latest
+	if latest != "" {
+		if err := db.SetSyncState(ctx, channelID, latest, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// fetchHistory fetches messages newer than oldest, in parallel windows when requested.
+// Without a previous sync, the window starts at the channel's creation time.
+func fetchHistory(ctx context.Context, client islack.APIClient, channelID string, channel map[string]any, oldest string, opts SyncOptions) ([]map[string]any, error) {
+	if opts.Parallel <= 1 {
+		return islack.CollectMessages(islack.IterChannelHistory(ctx, client, channelID,
+			islack.HistoryOptions{Oldest: oldest, PageSize: opts.PageSize}))
+	}
+
+	var start time.Time
+	if oldest != "" {
+		t, err := islack.ParseTimestamp(oldest)
+		if err != nil {
+			return nil, err
+		}
+		start = t
+	} else {
+		created, _ := channel["created"].(float64)
+		// No Slack message predates 2013; start there if the creation time is unknown.
+		start = time.Unix(max(int64(created), time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC).Unix()), 0)
+	}
+
+	end := time.Now()
+	if !end.After(start) {
+		return nil, nil
+	}
+	window := islack.HistoryOptions{
+		Oldest:   islack.FormatTimestamp(start),
+		Latest:   islack.FormatTimestamp(end),
+		PageSize: opts.PageSize,
+	}
+	return islack.ListChannelHistoryParallel(ctx, client, channelID, window, opts.Parallel)
+}
+
+// syncUsers archives message authors that are not yet in the archive.
+func syncUsers(ctx context.Context, client islack.APIClient, db *DB, messages []map[string]any) (int, error) {
+	seen := make(map[string]bool)
+	added := 0
+	for _, msg := range messages {
+		id, _ := msg["user"].(string)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		exists, err := db.HasUser(ctx, id)
+		if err != nil {
+			return added, err
+		}
+		if exists {
+			continue
+		}
+
+		user, err := islack.GetUser(ctx, client, id)
+		if err != nil {
+			// A missing author shouldn't abort the sync; it is retried next time.
+			slog.Info("could not fetch user", "user", id, "error", err)
+			continue
+		}
+		if err := db.UpsertUser(ctx, user, islack.DisplayName(user)); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,181 @@
+package archive_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/archive"
+)
+
+// fakeSlack serves canned responses by method and records each call.
+type fakeSlack struct {
+	history []any
+	replies map[string][]any
+	calls   []string
+}
+
+func (f *fakeSlack) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	f.calls = append(f.calls, method)
+	switch method {
+	case "conversations.info":
+		return map[string]any{"ok": true, "channel": map[string]any{"id": params["channel"], "name": "general"}}, nil
+	case "conversations.history":
+		var msgs []any
+		for _, m := range f.history {
+			if ts, _ := m.(map[string]any)["ts"].(string); ts > params["oldest"] {
+				msgs = append(msgs, m)
+			}
+		}
+		return map[string]any{"ok": true, "messages": msgs}, nil
+	case "conversations.replies":
+		return map[string]any{"ok": true, "messages": f.replies[params["ts"]]}, nil
+	case "users.info":
+		return map[string]any{"ok": true, "user": map[string]any{"id": params["user"], "name": "user-" + params["user"]}}, nil
+	}
+	return nil, fmt.Errorf("unexpected method %s", method)
+}
+
+func (f *fakeSlack) count(method string) int {
+	n := 0
+	for _, c := range f.calls {
+		if c == method {
+			n++
+		}
+	}
+	return n
+}
+
+func openTestDB(t *testing.T) *archive.DB {
+	t.Helper()
+	db, err := archive.Open(t.Context(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSync_Incremental(t *testing.T) {
+	db := openTestDB(t)
+	fake := &fakeSlack{
+		history: []any{
+			map[string]any{"ts": "1770000000.000001", "user": "U1", "text": "hello"},
+			map[string]any{"ts": "1770000000.000002", "user": "U2", "text": "thread root", "reply_count": float64(1), "latest_reply": "1770000000.000003"},
+		},
+		replies: map[string][]any{
+			"1770000000.000002": {
+				map[string]any{"ts": "1770000000.000002", "user": "U2", "text": "thread root", "thread_ts": "1770000000.000002"},
+				map[string]any{"ts": "1770000000.000003", "user": "U3", "text": "reply", "thread_ts": "1770000000.000002"},
+			},
+		},
+	}
+
+	first, err := archive.Sync(t.Context(), fake, db, "C123", archive.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NewMessages != 2 || first.ThreadsUpdated != 1 || first.NewUsers != 3 {
+		t.Errorf("first sync = %+v, want 2 messages, 1 thread, 3 users", first)
+	}
+
+	// A second sync with one new message only fetches what's new.
+	fake.history = append(fake.history, map[string]any{"ts": "1770000000.000004", "user": "U1", "text": "later"})
+	fake.calls = nil
+
+	second, err := archive.Sync(t.Context(), fake, db, "C123", archive.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NewMessages != 1 || second.ThreadsUpdated != 0 || second.NewUsers != 0 {
+		t.Errorf("second sync = %+v, want 1 message, 0 threads, 0 users", second)
+	}
+	if n := fake.count("users.info"); n != 0 {
+		t.Errorf("second sync made %d users.info calls, want 0", n)
+	}
+
+	status, err := db.Status(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Channels) != 1 {
+		t.Fatalf("got %d channels, want 1", len(status.Channels))
+	}
+	ch := status.Channels[0]
+	if ch.Messages != 4 || ch.Threads != 1 || ch.LatestTS != "1770000000.000004" {
+		t.Errorf("status = %+v, want 4 messages, 1 thread, latest 1770000000.000004", ch)
+	}
+	if status.Users != 3 {
+		t.Errorf("users = %d, want 3", status.Users)
+	}
+}
+
+func TestSync_Tombstones(t *testing.T) {
+	db := openTestDB(t)
+	fake := &fakeSlack{
+		history: []any{
+			map[string]any{"ts": "1770000000.000001", "user": "U1", "text": "hello"},
+			map[string]any{"ts": "1770000000.000002", "user": "U2", "text": "thread root", "reply_count": float64(2), "latest_reply": "1770000000.000004"},
+			map[string]any{"ts": "1770000000.000005", "user": "U1", "text": "oops"},
+		},
+		replies: map[string][]any{
+			"1770000000.000002": {
+				map[string]any{"ts": "1770000000.000002", "user": "U2", "text": "thread root", "thread_ts": "1770000000.000002"},
+				map[string]any{"ts": "1770000000.000003", "user": "U3", "text": "first", "thread_ts": "1770000000.000002"},
+				map[string]any{"ts": "1770000000.000004", "user": "U3", "text": "second", "thread_ts": "1770000000.000002"},
+			},
+		},
+	}
+	if _, err := archive.Sync(t.Context(), fake, db, "C123", archive.SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// "oops" and the first reply are deleted in Slack.
+	fake.history = []any{
+		fake.history[0],
+		map[string]any{"ts": "1770000000.000002", "user": "U2", "text": "thread root", "reply_count": float64(1), "latest_reply": "1770000000.000004"},
+	}
+	fake.replies["1770000000.000002"] = []any{
+		fake.replies["1770000000.000002"][0],
+		fake.replies["1770000000.000002"][2],
+	}
+
+	// Without a recheck window, nothing already archived is re-read.
+	result, err := archive.Sync(t.Context(), fake, db, "C123", archive.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Deleted != 0 || result.NewMessages != 0 {
+		t.Errorf("sync = %+v, want nothing new or deleted", result)
+	}
+
+	recheck := time.Unix(1769990000, 0)
+	result, err = archive.Sync(t.Context(), fake, db, "C123", archive.SyncOptions{Recheck: recheck})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Deleted != 2 || result.NewMessages != 0 || result.ThreadsUpdated != 1 {
+		t.Errorf("recheck sync = %+v, want 2 deleted, 0 new, 1 thread", result)
+	}
+	if result.LatestTS != "1770000000.000005" {
+		t.Errorf("latest = %q, want the previous sync's latest", result.LatestTS)
+	}
+
+	status, err := db.Status(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := status.Channels[0]; ch.Messages != 5 || ch.Deleted != 2 {
+		t.Errorf("status = %+v, want 5 messages kept, 2 deleted", ch)
+	}
+
+	matches, err := db.Search(t.Context(), archive.SearchOptions{Query: "oops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].DeletedAt == "" {
+		t.Errorf("matches = %+v, want oops marked deleted", matches)
+	}
+}
@@ -0,0 +1,288 @@
+// Package cache provides small on-disk caches for resolved Slack lookups.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// Store is a JSON file-backed key/value cache with per-entry expiry.
+// A nil *Store is valid and caches nothing.
+type Store struct {
+	path    string
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// Dir returns the root cache directory (e.g., ~/.cache/slack-reader).
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache dir: %w", err)
+	}
+	return filepath.Join(base, "slack-reader"), nil
+}
+
+// WorkspaceDir returns the cache directory for a workspace.
+func WorkspaceDir(workspace string) (string, error) {
+	if err := checkWorkspace(workspace); err != nil {
+		return "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, workspace), nil
+}
+
+// checkWorkspace rejects workspace names that are not a single path element, so that
+// a crafted name (e.g., "../..") cannot reach outside the cache directory.
+func checkWorkspace(workspace string) error {
+	if workspace == "" || workspace == "." || workspace == ".." ||
+		strings.ContainsAny(workspace, `/\`) || filepath.Base(workspace) != workspace {
+		return fmt.Errorf("invalid workspace name %q", workspace)
+	}
+	return nil
+}
+
+// WorkspacePath returns the path of a named cache file for a workspace.
+func WorkspacePath(workspace, name string) (string, error) {
+	dir, err := WorkspaceDir(workspace)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// Open loads the cache at path, dropping expired entries. A missing file yields an empty cache.
+func Open(path string, ttl time.Duration) (*Store, error) {
+	s := &Store{path: path, ttl: ttl, entries: make(map[string]entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		// A corrupt cache is not worth failing a command over; start fresh.
+		s.entries = make(map[string]entry)
+		return s, nil
+	}
+
+	now := time.Now()
+	for k, e := range s.entries {
+		if now.After(e.Expires) {
+			delete(s.entries, k)
+		}
+	}
+	return s, nil
+}
+
+// Get returns the cached value for key, if present and not expired.
+func (s *Store) Get(key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || time.Now().After(e.Expires) {
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Entries returns a copy of all unexpired entries.
+func (s *Store) Entries() map[string]string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	out := make(map[string]string, len(s.entries))
+	for k, e := range s.entries {
+		if !now.After(e.Expires) {
+			out[k] = e.Value
+		}
+	}
+	return out
+}
+
+// Set stores value under key and persists the cache.
+func (s *Store) Set(key, value string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry{Value: value, Expires: time.Now().Add(s.ttl)}
+	return s.save()
+}
+
+// SetMany stores several values at once, persisting the cache a single time.
+func (s *Store) SetMany(values map[string]string) error {
+	if s == nil || len(values) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(s.ttl)
+	for k, v := range values {
+		s.entries[k] = entry{Value: v, Expires: expires}
+	}
+	return s.save()
+}
+
+// Delete removes key and persists the cache.
+func (s *Store) Delete(key string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; !ok {
+		return nil
+	}
+	delete(s.entries, key)
+	return s.save()
+}
+
+// save writes the cache atomically. Callers must hold s.mu.
+func (s *Store) save() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("marshal cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".cache-*")
+	if err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
+
+// FileStore caches opaque values as individual files, expiring them by modification time.
+// It suits large values (e.g., API responses) that would be costly to rewrite as one file.
+// Expired files are deleted when read, and all of them on the first write.
+// A nil *FileStore is valid and caches nothing.
+type FileStore struct {
+	dir   string
+	ttl   time.Duration
+	prune sync.Once
+}
+
+// NewFileStore returns a FileStore rooted at dir. The directory is created on first write.
+func NewFileStore(dir string, ttl time.Duration) *FileStore {
+	return &FileStore{dir: dir, ttl: ttl}
+}
+
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get returns the cached value for key, if present and not expired.
+func (s *FileStore) Get(key string) ([]byte, bool) {
+	if s == nil {
+		return nil, false
+	}
+	path := s.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if s.expired(info) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set stores value under key.
+func (s *FileStore) Set(key string, value []byte) error {
+	if s == nil {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	s.prune.Do(s.removeExpired)
+	if err := os.WriteFile(s.path(key), value, 0o600); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) expired(info fs.FileInfo) bool {
+	return time.Since(info.ModTime()) > s.ttl
+}
+
+// removeExpired deletes the store's expired files, ignoring errors.
+func (s *FileStore) removeExpired() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && info.Mode().IsRegular() && s.expired(info) {
+			_ = os.Remove(filepath.Join(s.dir, e.Name()))
+		}
+	}
+}
+
+// Clear removes all cached data for workspace, or for every workspace if workspace is empty.
+func Clear(workspace string) error {
+	dir, err := Dir()
+	if workspace != "" {
+		dir, err = WorkspaceDir(workspace)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clear cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/cache"
+)
+
+func TestStore_PersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "myteam", "channels.json")
+
+	s, err := cache.Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("general", "C0123ABCD"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := cache.Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := reopened.Get("general"); !ok || got != "C0123ABCD" {
+		t.Errorf("Get(general) = %q, %v; want C0123ABCD, true", got, ok)
+	}
+}
+
+func TestStore_Expires(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "channels.json")
+
+	s, err := cache.Open(path, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("general", "C0123ABCD"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := s.Get("general"); ok {
+		t.Error("expected entry to have expired")
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "channels.json")
+
+	s, err := cache.Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("general", "C0123ABCD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("general"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := cache.Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reopened.Get("general"); ok {
+		t.Error("expected entry to be deleted")
+	}
+}
+
+func TestStore_NilIsNoop(t *testing.T) {
+	var s *cache.Store
+	if err := s.Set("general", "C0123ABCD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("general"); ok {
+		t.Error("nil store should never hit")
+	}
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	s := cache.NewFileStore(filepath.Join(t.TempDir(), "responses"), time.Hour)
+
+	if _, ok := s.Get("conversations.history?channel=C1"); ok {
+		t.Fatal("expected miss on empty store")
+	}
+	if err := s.Set("conversations.history?channel=C1", []byte(`{"ok":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := s.Get("conversations.history?channel=C1")
+	if !ok || string(got) != `{"ok":true}` {
+		t.Errorf("Get = %q, %v; want {\"ok\":true}, true", got, ok)
+	}
+	if _, ok := s.Get("conversations.history?channel=C2"); ok {
+		t.Error("expected miss for a different key")
+	}
+}
+
+func TestFileStore_PrunesExpired(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "responses")
+	s := cache.NewFileStore(dir, time.Hour)
+	for _, key := range []string{"a", "b"} {
+		if err := s.Set(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Age every entry past the TTL.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	for _, e := range entries {
+		if err := os.Chtimes(filepath.Join(dir, e.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reading an expired entry deletes it.
+	if _, ok := s.Get("a"); ok {
+		t.Error("expected miss for expired entry")
+	}
+	if n := countFiles(t, dir); n != 1 {
+		t.Errorf("after Get: %d files, want 1", n)
+	}
+
+	// The first write of a new store deletes the rest.
+	if err := cache.NewFileStore(dir, time.Hour).Set("c", []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if n := countFiles(t, dir); n != 1 {
+		t.Errorf("after Set: %d files, want 1", n)
+	}
+}
+
+func TestClear_RemovesResponses(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := cache.WorkspaceDir("myteam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.NewFileStore(filepath.Join(dir, "responses"), time.Hour).Set("k", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Clear("myteam"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "responses")); !os.IsNotExist(err) {
+		t.Errorf("responses dir still present (stat err = %v)", err)
+	}
+}
+
+func countFiles(t *testing.T, dir string) int {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+func TestWorkspaceDir_RejectsPaths(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, w := range []string{"", ".", "..", "../..", "a/b", `a\b`, "/etc"} {
+		if _, err := cache.WorkspaceDir(w); err == nil {
+			t.Errorf("WorkspaceDir(%q): expected an error", w)
+		}
+		if _, err := cache.WorkspacePath(w, "channels"); err == nil {
+			t.Errorf("WorkspacePath(%q): expected an error", w)
+		}
+		if w != "" {
+			if err := cache.Clear(w); err == nil {
+				t.Errorf("Clear(%q): expected an error", w)
+			}
+		}
+	}
+	if _, err := cache.WorkspaceDir("myteam"); err != nil {
+		t.Errorf("WorkspaceDir(myteam): %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package config reads the slack-reader configuration file, which sets default flag values.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds default flag values. Global values apply to every command with the flag;
+// command values apply to a command (e.g., "message list") or a command group (e.g.,
+// "message") and its subcommands, the most specific winning:
+//
+//	workspace: myteam
+//	cache-ttl: 10m
+//	commands:
+//	  message list:
+//	    output: markdown
+//	    limit: 200
+type Config struct {
+	Global   map[string]string
+	Commands map[string]map[string]string
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME (or ~/.config)/slack-reader/config.yaml.
+func DefaultPath() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locate home dir: %w", err)
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "slack-reader", "config.yaml"), nil
+}
+
+// Load reads a configuration file. A missing file is an empty configuration.
+func Load(path string) (*Config, error) {
+	cfg := &Config{Global: map[string]string{}, Commands: map[string]map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	for key, value := range raw {
+		if key != "commands" {
+			if cfg.Global[key], err = flagValue(value); err != nil {
+				return nil, fmt.Errorf("config %s: %s: %w", path, key, err)
+			}
+			continue
+		}
+		commands, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config %s: commands: want a map of command names to flags", path)
+		}
+		for command, flags := range commands {
+			values, ok := flags.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("config %s: commands: %s: want a map of flags to values", path, command)
+			}
+			command = strings.Join(strings.Fields(command), " ")
+			cfg.Commands[command] = make(map[string]string, len(values))
+			for name, v := range values {
+				if cfg.Commands[command][name], err = flagValue(v); err != nil {
+					return nil, fmt.Errorf("config %s: commands: %s: %s: %w", path, command, name, err)
+				}
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// Values returns the default flag values for a command, given by its path without the
+// program name (e.g., "message list").
+func (c *Config) Values(command string) map[string]string {
+	values := make(map[string]string, len(c.Global))
+	for name, v := range c.Global {
+		values[name] = v
+	}
+	words := strings.Fields(command)
+	for i := range words {
+		for name, v := range c.Commands[strings.Join(words[:i+1], " ")] {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+// flagValue formats a YAML value as a flag value; lists become comma-separated.
+func flagValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			s, err := flagValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]any:
+		return "", errors.New("want a value, not a map")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/config"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+workspace: myteam
+cache-ttl: 10m
+commands:
+  message:
+    rendered: true
+    output: json
+  message  list:
+    output: markdown
+    limit: 200
+    exclude-subtype: [channel_join, channel_leave]
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command string
+		want    map[string]string
+	}{
+		{"channel list", map[string]string{"workspace": "myteam", "cache-ttl": "10m"}},
+		{"message get", map[string]string{"workspace": "myteam", "cache-ttl": "10m", "rendered": "true", "output": "json"}},
+		{"message list", map[string]string{
+			"workspace": "myteam", "cache-ttl": "10m", "rendered": "true", "output": "markdown",
+			"limit": "200", "exclude-subtype": "channel_join,channel_leave",
+		}},
+	}
+	for _, tt := range tests {
+		if got := cfg.Values(tt.command); !maps.Equal(got, tt.want) {
+			t.Errorf("Values(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil || len(cfg.Values("message list")) != 0 {
+		t.Errorf("Load(missing) = %+v, %v; want an empty config", cfg, err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("commands:\n  message list: markdown\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(path); err == nil {
+		t.Error("Load accepted a command without a map of flags")
+	}
+}
@@ -0,0 +1,206 @@
+// Package digest renders a period of channel activity as HTML email (EML or mbox).
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	slackmd "github.com/rneatherway/slack/pkg/markdown"
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Digest is one channel's messages over a period.
+type Digest struct {
+	Channel  string // display name, e.g. "#eng"
+	Since    time.Time
+	Until    time.Time
+	Messages []map[string]any // oldest first
+	Summary  string           // optional text shown above the messages
+}
+
+// Subject returns the email subject line for d.
+func (d *Digest) Subject() string {
+	return fmt.Sprintf("%s digest: %s to %s (%d messages)", d.Channel,
+		d.Since.UTC().Format("2006-01-02 15:04"), d.Until.UTC().Format("2006-01-02 15:04 MST"), len(d.Messages))
+}
+
+// Transcript renders just the messages as markdown, in the same style as message list --output markdown.
+func (d *Digest) Transcript(users output.UserResolver) (string, error) {
+	return output.FormatMarkdown(d.Messages, users)
+}
+
+// Markdown renders the digest as markdown: a title, the summary if any, and the transcript.
+func (d *Digest) Markdown(users output.UserResolver) (string, error) {
+	body, err := d.Transcript(users)
+	if err != nil {
+		return "", err
+	}
+	if d.Summary != "" {
+		body = fmt.Sprintf("## Summary\n\n%s\n\n## Messages\n\n%s", d.Summary, body)
+	}
+	return fmt.Sprintf("# %s\n\n%s", d.Subject(), body), nil
+}
+
+// HTML renders the digest as a standalone HTML document.
+func (d *Digest) HTML(users output.UserResolver) (string, error) {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>%s</title></head><body>\n",
+		html.EscapeString(d.Subject()))
+	fmt.Fprintf(b, "<h1>%s</h1>\n", html.EscapeString(d.Subject()))
+	if d.Summary != "" {
+		fmt.Fprintf(b, "<h2>Summary</h2>\n<p>%s</p>\n<h2>Messages</h2>\n",
+			strings.ReplaceAll(html.EscapeString(d.Summary), "\n", "<br>\n"))
+	}
+
+	if len(d.Messages) == 0 {
+		b.WriteString("<p>No messages.</p>\n")
+	}
+	for _, msg := range d.Messages {
+		author, err := users.UsernameForMessage(msg)
+		if err != nil {
+			return "", err
+		}
+		ts, _ := msg["ts"].(string)
+		tm, err := slackmd.ParseUnixTimestamp(ts)
+		if err != nil {
+			return "", fmt.Errorf("parse timestamp %q: %w", ts, err)
+		}
+		text, _ := msg["text"].(string)
+		if text != "" {
+			if text, err = slackmd.Convert(users, text); err != nil {
+				return "", err
+			}
+		}
+		huddle, isHuddle, err := output.HuddleSummary(msg, users)
+		if err != nil {
+			return "", err
+		}
+		if isHuddle {
+			text = strings.TrimSpace(huddle + "\n" + text)
+		}
+
+		when := tm.UTC().Format("2006-01-02 15:04 MST")
+		if editedAt, ok := islack.EditedAt(msg); ok {
+			when += " (edited " + editedAt.UTC().Format("2006-01-02 15:04 MST") + ")"
+		}
+		fmt.Fprintf(b, "<p><b>%s</b> <small>%s</small></p>\n", html.EscapeString(author), when)
+		fmt.Fprintf(b, "<blockquote>%s</blockquote>\n",
+			strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n"))
+	}
+	b.WriteString("</body></html>\n")
+	return b.String(), nil
+}
+
+// Envelope holds the addressing for a digest email.
+type Envelope struct {
+	From string
+	To   string
+	Date time.Time
+}
+
+// WriteEML writes the digest as a MIME email with plain-text (markdown) and HTML alternatives.
+func (d *Digest) WriteEML(w io.Writer, env Envelope, users output.UserResolver) error {
+	text, err := d.Markdown(users)
+	if err != nil {
+		return err
+	}
+	body, err := d.HTML(users)
+	if err != nil {
+		return err
+	}
+
+	parts := &bytes.Buffer{}
+	mw := multipart.NewWriter(parts)
+	for _, alt := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", body},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alt.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return fmt.Errorf("write digest: %w", err)
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := io.WriteString(qw, alt.content); err != nil {
+			return fmt.Errorf("write digest: %w", err)
+		}
+		if err := qw.Close(); err != nil {
+			return fmt.Errorf("write digest: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+
+	headers := []string{
+		"From: " + env.From,
+		"To: " + env.To,
+		"Subject: " + encodeHeader(d.Subject()),
+		"Date: " + env.Date.Format(time.RFC1123Z),
+		"Message-ID: " + messageID(env.From),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	if _, err := io.WriteString(w, strings.Join(headers, "\r\n")+"\r\n\r\n"); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+	if _, err := w.Write(parts.Bytes()); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+	return nil
+}
+
+// WriteMbox writes the digest as a single-message mbox (mboxrd), suitable for appending to a mailbox.
+func (d *Digest) WriteMbox(w io.Writer, env Envelope, users output.UserResolver) error {
+	eml := &bytes.Buffer{}
+	if err := d.WriteEML(eml, env, users); err != nil {
+		return err
+	}
+
+	sender := env.From
+	if addr, err := mail.ParseAddress(env.From); err == nil {
+		sender = addr.Address
+	}
+	fmt.Fprintf(w, "From %s %s\n", sender, env.Date.UTC().Format(time.ANSIC))
+	for line := range strings.SplitSeq(strings.ReplaceAll(eml.String(), "\r\n", "\n"), "\n") {
+		// mboxrd: quote lines that would otherwise read as a message separator.
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			line = ">" + line
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("write digest: %w", err)
+		}
+	}
+	return nil
+}
+
+// encodeHeader encodes non-ASCII header text per RFC 2047.
+func encodeHeader(s string) string {
+	return mime.QEncoding.Encode("utf-8", s)
+}
+
+func messageID(from string) string {
+	domain := "slack-reader.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, host, ok := strings.Cut(addr.Address, "@"); ok {
+			domain = host
+		}
+	}
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain)
+}
@@ -0,0 +1,82 @@
+package digest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/digest"
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func testDigest() *digest.Digest {
+	return &digest.Digest{
+		Channel: "#eng",
+		Since:   time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+		Until:   time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+		Messages: []map[string]any{
+			{"ts": "1773100000.000100", "user": "U1", "user_profile": map[string]any{"display_name": "alice"}, "text": "deploy <b>done</b>"},
+			{"ts": "1773100060.000100", "user": "U1", "user_profile": map[string]any{"display_name": "alice"}, "text": "heads up:\nFrom now on, use the new pipeline"},
+		},
+	}
+}
+
+func testUsers(d *digest.Digest) *slack.UserProvider {
+	users := slack.NewUserProvider(nil)
+	users.Seed(d.Messages)
+	return users
+}
+
+func TestDigest_HTMLEscapes(t *testing.T) {
+	d := testDigest()
+	body, err := d.HTML(testUsers(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "deploy &lt;b&gt;done&lt;/b&gt;") {
+		t.Errorf("message text not escaped:\n%s", body)
+	}
+	if !strings.Contains(body, "<b>alice</b>") {
+		t.Errorf("author missing:\n%s", body)
+	}
+}
+
+func TestDigest_WriteMbox(t *testing.T) {
+	d := testDigest()
+	env := digest.Envelope{From: "digest <digest@example.com>", To: "team@example.com", Date: d.Until}
+
+	var buf bytes.Buffer
+	if err := d.WriteMbox(&buf, env, testUsers(d)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "From digest@example.com Tue Mar 10 12:00:00 2026\n") {
+		t.Errorf("missing mbox separator, got:\n%.80s", out)
+	}
+	for _, want := range []string{"Subject: #eng digest:", "To: team@example.com", "Content-Type: text/html; charset=utf-8"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	// Only the separator may start a line with "From ".
+	if n := strings.Count(out, "\nFrom "); n != 0 {
+		t.Errorf("found %d unescaped From lines", n)
+	}
+}
+
+func TestDigest_SummaryFirst(t *testing.T) {
+	d := testDigest()
+	d.Summary = "Deploys moved to the new pipeline."
+
+	md, err := d.Markdown(testUsers(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := strings.Index(md, "## Summary\n\nDeploys moved to the new pipeline.")
+	messages := strings.Index(md, "## Messages")
+	if summary < 0 || messages < summary {
+		t.Errorf("want summary section before messages, got:\n%s", md)
+	}
+}
@@ -0,0 +1,278 @@
+// Package export writes channel history in the layout of Slack's workspace export, so
+// that tools which read official exports can read ours.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Destination receives the files of an export, named by slash-separated paths relative
+// to the export's root (e.g., "general/2026-01-31.json").
+type Destination interface {
+	WriteFile(ctx context.Context, name string, data []byte) error
+	Close() error // waits for writes still in progress
+}
+
+// Dir is a Destination that writes under a local directory.
+type Dir string
+
+// WriteFile implements Destination.
+func (d Dir) WriteFile(_ context.Context, name string, data []byte) error {
+	path := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create export directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close implements Destination.
+func (Dir) Close() error {
+	return nil
+}
+
+// Options controls an export.
+type Options struct {
+	Oldest string // only messages after this timestamp ("" = from the start)
+	Latest string // only messages before this timestamp ("" = up to now)
+
+	// Deterministic makes re-running an export write identical files unless messages were
+	// posted, edited, or deleted, for exports kept under version control: the reader's own
+	// state and the times Slack last touched objects (last_read, unread counts, updated,
+	// presence, ...) are left out, members are sorted, and Oldest is moved back to the
+	// start of its day (UTC), so that the first day's file is whole.
+	Deterministic bool
+}
+
+// volatileFields are the fields left out of a deterministic export, by kind of object:
+// the exporting user's read state and preferences, and last-modified times that change
+// without the content changing.
+var volatileFields = map[string][]string{
+	"channel": {"last_read", "unread_count", "unread_count_display", "updated", "priority", "is_open", "is_member"},
+	"user":    {"updated", "presence"},
+	"message": {"last_read", "subscribed", "is_starred"},
+}
+
+// Result summarizes an export.
+type Result struct {
+	Channels []ChannelResult `json:"channels"`
+	Users    int             `json:"users"`
+	Files    int             `json:"files"`
+}
+
+// ChannelResult summarizes one exported conversation.
+type ChannelResult struct {
+	ChannelID string `json:"channel_id"`
+	Dir       string `json:"dir"`
+	Messages  int    `json:"messages"`
+	Days      int    `json:"days"`
+}
+
+// indexFiles names the file that lists each kind of conversation, as Slack's export does.
+var indexFiles = []string{"channels.json", "groups.json", "mpims.json", "dms.json"}
+
+// Export writes channels and users to dest in Slack's export layout:
+//
+//	channels.json, groups.json, mpims.json, dms.json  the public channels, private channels,
+//	                                                   group DMs, and DMs, with their members
+//	users.json                                         users, the workspace's member directory
+//	<channel>/<YYYY-MM-DD>.json                        each day's messages (UTC), replies included
+//
+// A channel's directory is its name, or its ID for DMs, which have none. Messages are
+// sorted by timestamp, conversations and users by ID, and JSON object keys by name, and
+// file names depend only on what is exported, not on when; see also Options.Deterministic.
+func Export(ctx context.Context, client islack.APIClient, dest Destination, channelIDs []string, users []map[string]any, opts Options) (*Result, error) {
+	if opts.Deterministic && opts.Oldest != "" {
+		if oldest, err := islack.ParseTimestamp(opts.Oldest); err == nil {
+			y, m, d := oldest.UTC().Date()
+			opts.Oldest = islack.FormatTimestamp(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+		}
+	}
+
+	result := &Result{}
+	index := make(map[string][]map[string]any, len(indexFiles))
+	for _, id := range channelIDs {
+		channel, err := islack.GetChannelInfo(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		members, err := islack.ChannelMemberIDs(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Deterministic {
+			sort.Strings(members)
+			strip(channel, "channel")
+		}
+		channel["members"] = members
+
+		file, dir := kind(channel)
+		index[file] = append(index[file], channel)
+
+		messages, err := history(ctx, client, id, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Deterministic {
+			for _, msg := range messages {
+				strip(msg, "message")
+			}
+		}
+		days := byDay(messages)
+		for _, day := range sortedKeys(days) {
+			if err := writeJSON(ctx, dest, dir+"/"+day+".json", days[day]); err != nil {
+				return nil, err
+			}
+			result.Files++
+		}
+		slog.Info("exported channel", "channel", id, "dir", dir, "messages", len(messages))
+		result.Channels = append(result.Channels, ChannelResult{ChannelID: id, Dir: dir, Messages: len(messages), Days: len(days)})
+	}
+
+	for _, file := range indexFiles {
+		if channels := index[file]; len(channels) > 0 {
+			sortByID(channels)
+			if err := writeJSON(ctx, dest, file, channels); err != nil {
+				return nil, err
+			}
+			result.Files++
+		}
+	}
+
+	users = append([]map[string]any(nil), users...)
+	if opts.Deterministic {
+		for i, u := range users {
+			users[i] = maps.Clone(u)
+			strip(users[i], "user")
+		}
+	}
+	sortByID(users)
+	if err := writeJSON(ctx, dest, "users.json", users); err != nil {
+		return nil, err
+	}
+	result.Files++
+	result.Users = len(users)
+	return result, nil
+}
+
+// strip removes the volatile fields of an object of the given kind, in place.
+func strip(object map[string]any, kind string) {
+	for _, field := range volatileFields[kind] {
+		delete(object, field)
+	}
+}
+
+// kind returns the index file that lists a conversation, and its directory.
+func kind(channel map[string]any) (file, dir string) {
+	id, _ := channel["id"].(string)
+	name, _ := channel["name"].(string)
+	switch {
+	case channel["is_im"] == true:
+		return "dms.json", id
+	case channel["is_mpim"] == true:
+		return "mpims.json", name
+	case channel["is_private"] == true:
+		return "groups.json", name
+	default:
+		return "channels.json", name
+	}
+}
+
+// history returns a channel's messages in the window, with the replies of each thread
+// started in it, oldest first.
+func history(ctx context.Context, client islack.APIClient, channelID string, opts Options) ([]map[string]any, error) {
+	window := islack.HistoryOptions{Oldest: opts.Oldest, Latest: opts.Latest}
+	messages, err := islack.CollectMessages(islack.IterChannelHistory(ctx, client, channelID, window))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(messages))
+	for _, msg := range messages {
+		ts, _ := msg["ts"].(string)
+		seen[ts] = true
+	}
+	var replies []map[string]any
+	for _, msg := range messages {
+		if count, _ := msg["reply_count"].(float64); count == 0 {
+			continue
+		}
+		threadTS, _ := msg["ts"].(string)
+		for reply, err := range islack.IterThread(ctx, client, channelID, threadTS, islack.HistoryOptions{}) {
+			if err != nil {
+				return nil, err
+			}
+			// The thread starts with its parent, and broadcast replies are in history too.
+			if ts, _ := reply["ts"].(string); !seen[ts] {
+				seen[ts] = true
+				replies = append(replies, reply)
+			}
+		}
+	}
+	messages = append(messages, replies...)
+	sort.SliceStable(messages, func(i, j int) bool {
+		tsI, _ := messages[i]["ts"].(string)
+		tsJ, _ := messages[j]["ts"].(string)
+		return tsI < tsJ
+	})
+	return messages, nil
+}
+
+// byDay groups messages by the UTC date of their timestamps.
+func byDay(messages []map[string]any) map[string][]map[string]any {
+	days := make(map[string][]map[string]any)
+	for _, msg := range messages {
+		ts, _ := msg["ts"].(string)
+		t, err := islack.ParseTimestamp(ts)
+		if err != nil {
+			continue
+		}
+		day := t.UTC().Format("2006-01-02")
+		days[day] = append(days[day], msg)
+	}
+	return days
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortByID sorts Slack objects by their "id" field.
+func sortByID(objects []map[string]any) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		idI, _ := objects[i]["id"].(string)
+		idJ, _ := objects[j]["id"].(string)
+		return idI < idJ
+	})
+}
+
+// writeJSON writes v to dest as indented JSON, with object keys sorted and without the
+// HTML escaping of <, > and & that would obscure Slack's markup.
+func writeJSON(ctx context.Context, dest Destination, name string, v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("marshal %s: %w", name, err)
+	}
+	return dest.WriteFile(ctx, name, buf.Bytes())
+}
@@ -0,0 +1,184 @@
+package export_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/export"
+)
+
+// exportAPI serves a public channel with a thread over two days, and a DM.
+type exportAPI struct{}
+
+func (exportAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method + " " + params["channel"] {
+	case "conversations.info C1":
+		return map[string]any{"ok": true, "channel": map[string]any{"id": "C1", "name": "general", "is_channel": true}}, nil
+	case "conversations.info D1":
+		return map[string]any{"ok": true, "channel": map[string]any{"id": "D1", "is_im": true, "user": "U2"}}, nil
+	case "conversations.members C1", "conversations.members D1":
+		return map[string]any{"ok": true, "members": []any{"U1", "U2"}}, nil
+	case "conversations.history C1":
+		return map[string]any{"ok": true, "messages": []any{
+			map[string]any{"ts": "1770076800.000100", "text": "next day <@U2>"}, // 2026-02-03
+			map[string]any{"ts": "1770000000.000100", "text": "question", "reply_count": float64(1), "thread_ts": "1770000000.000100"},
+		}}, nil
+	case "conversations.replies C1":
+		return map[string]any{"ok": true, "messages": []any{
+			map[string]any{"ts": "1770000000.000100", "text": "question", "reply_count": float64(1), "thread_ts": "1770000000.000100"},
+			map[string]any{"ts": "1770000100.000100", "text": "answer", "thread_ts": "1770000000.000100"},
+		}}, nil
+	case "conversations.history D1":
+		return map[string]any{"ok": true, "messages": []any{
+			map[string]any{"ts": "1770000200.000100", "text": "hi"},
+		}}, nil
+	}
+	return nil, errors.New("unexpected call " + method)
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	users := []map[string]any{{"id": "U2", "name": "bob"}, {"id": "U1", "name": "alice"}}
+	result, err := export.Export(t.Context(), exportAPI{}, export.Dir(dir), []string{"C1", "D1"}, users, export.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Files != 6 || result.Users != 2 || len(result.Channels) != 2 || result.Channels[0].Messages != 3 {
+		t.Errorf("got result %+v", result)
+	}
+
+	var channels []map[string]any
+	readJSON(t, filepath.Join(dir, "channels.json"), &channels)
+	if len(channels) != 1 || channels[0]["name"] != "general" || len(channels[0]["members"].([]any)) != 2 {
+		t.Errorf("got channels.json %v", channels)
+	}
+	var dms []map[string]any
+	readJSON(t, filepath.Join(dir, "dms.json"), &dms)
+	if len(dms) != 1 || dms[0]["id"] != "D1" {
+		t.Errorf("got dms.json %v", dms)
+	}
+	var userList []map[string]any
+	readJSON(t, filepath.Join(dir, "users.json"), &userList)
+	if len(userList) != 2 || userList[0]["id"] != "U1" {
+		t.Errorf("got users.json %v, want sorted by ID", userList)
+	}
+
+	var day []map[string]any
+	readJSON(t, filepath.Join(dir, "general", "2026-02-02.json"), &day)
+	var texts []string
+	for _, msg := range day {
+		texts = append(texts, msg["text"].(string))
+	}
+	if !slices.Equal(texts, []string{"question", "answer"}) {
+		t.Errorf("got 2026-02-02 messages %v, want the thread in order", texts)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "general", "2026-02-03.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[\n    {\n        \"text\": \"next day <@U2>\",\n        \"ts\": \"1770076800.000100\"\n    }\n]\n"
+	if string(data) != want {
+		t.Errorf("got 2026-02-03.json %s, want sorted keys, 4-space indent, and markup unescaped", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "D1", "2026-02-02.json")); err != nil {
+		t.Errorf("DM messages not under its ID: %v", err)
+	}
+}
+
+// An s3:// destination streams each file to `aws s3 cp - <url>`.
+func TestNewDestination_S3(t *testing.T) {
+	bin, uploads := t.TempDir(), t.TempDir()
+	// Only shell builtins: PATH holds just the fake CLI.
+	script := "#!/bin/sh\n[ \"$1 $2 $3\" = 's3 cp -' ] || exit 2\nprintf '%s' \"$4\" > " + uploads + "/url\n" +
+		"while IFS= read -r line; do printf '%s\\n' \"$line\"; done > " + uploads + "/body\n"
+	if err := os.WriteFile(filepath.Join(bin, "aws"), []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	dest, err := export.NewDestination(t.Context(), "s3://bucket/exports/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dest.WriteFile(t.Context(), "general/2026-02-02.json", []byte("[]\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dest.Close(); err != nil {
+		t.Fatal(err)
+	}
+	url, _ := os.ReadFile(filepath.Join(uploads, "url"))
+	body, _ := os.ReadFile(filepath.Join(uploads, "body"))
+	if string(url) != "s3://bucket/exports/general/2026-02-02.json" || string(body) != "[]\n" {
+		t.Errorf("uploaded %q to %q, want the file streamed to its object URL", body, url)
+	}
+
+	if _, err := export.NewDestination(t.Context(), "gs://bucket"); err == nil {
+		t.Error("want an error when the gcloud CLI is missing")
+	}
+	if dest, err := export.NewDestination(t.Context(), "./out"); err != nil || dest != export.Dir("./out") {
+		t.Errorf("NewDestination(./out) = %v, %v; want a local directory", dest, err)
+	}
+}
+
+// volatileAPI serves a channel whose read state and member order change between calls.
+type volatileAPI struct{ calls *int }
+
+func (a volatileAPI) API(_ context.Context, method string, _ map[string]string) (map[string]any, error) {
+	*a.calls++
+	switch method {
+	case "conversations.info":
+		return map[string]any{"ok": true, "channel": map[string]any{"id": "C1", "name": "general", "last_read": fmt.Sprint(*a.calls), "unread_count": float64(*a.calls)}}, nil
+	case "conversations.members":
+		if *a.calls%2 == 0 {
+			return map[string]any{"ok": true, "members": []any{"U2", "U1"}}, nil
+		}
+		return map[string]any{"ok": true, "members": []any{"U1", "U2"}}, nil
+	case "conversations.history":
+		return map[string]any{"ok": true, "messages": []any{
+			map[string]any{"ts": "1770000000.000100", "text": "hi", "last_read": fmt.Sprint(*a.calls)},
+		}}, nil
+	}
+	return nil, errors.New("unexpected call " + method)
+}
+
+func TestExport_Deterministic(t *testing.T) {
+	var calls int
+	var exports []string
+	for range 2 {
+		dir := t.TempDir()
+		users := []map[string]any{{"id": "U1", "name": "alice", "updated": float64(calls)}}
+		_, err := export.Export(t.Context(), volatileAPI{&calls}, export.Dir(dir), []string{"C1"}, users, export.Options{Deterministic: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files string
+		for _, name := range []string{"channels.json", "users.json", "general/2026-02-02.json"} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			files += string(data)
+		}
+		exports = append(exports, files)
+	}
+	if exports[0] != exports[1] {
+		t.Errorf("re-export differs:\n%s\n---\n%s", exports[0], exports[1])
+	}
+}
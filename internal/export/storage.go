@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// uploadConcurrency bounds the uploads to object storage in progress at once.
+const uploadConcurrency = 8
+
+// storageCLIs are the commands that stream stdin to an object, by URL scheme. Both
+// upload large objects in parts.
+var storageCLIs = map[string][]string{
+	"s3://": {"aws", "s3", "cp", "-"},
+	"gs://": {"gcloud", "storage", "cp", "-"},
+}
+
+// NewDestination returns the Destination for --out: object storage for an s3:// or gs://
+// URL, or else a local directory. Uploads go through the aws or gcloud CLI, which must be
+// installed and authenticated, so no file is staged on local disk.
+func NewDestination(ctx context.Context, out string) (Destination, error) {
+	for scheme, cli := range storageCLIs {
+		if !strings.HasPrefix(out, scheme) {
+			continue
+		}
+		if strings.TrimPrefix(out, scheme) == "" {
+			return nil, fmt.Errorf("invalid destination %q: no bucket", out)
+		}
+		if _, err := exec.LookPath(cli[0]); err != nil {
+			return nil, fmt.Errorf("%s destinations need the %s CLI: %w", scheme, cli[0], err)
+		}
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(uploadConcurrency)
+		return &objectStore{prefix: strings.TrimSuffix(out, "/") + "/", cli: cli, ctx: gctx, g: g}, nil
+	}
+	return Dir(out), nil
+}
+
+// objectStore is a Destination that uploads each file as an object under prefix,
+// several at a time.
+type objectStore struct {
+	prefix string   // e.g., "s3://bucket/exports/"
+	cli    []string // the upload command, to which the object URL is appended
+	ctx    context.Context
+	g      *errgroup.Group
+}
+
+// WriteFile implements Destination. It returns once the upload has started, or with the
+// error of an earlier upload; Close reports the rest.
+func (s *objectStore) WriteFile(_ context.Context, name string, data []byte) error {
+	if err := s.ctx.Err(); err != nil {
+		return s.g.Wait()
+	}
+	url := s.prefix + name
+	s.g.Go(func() error {
+		args := append(append([]string(nil), s.cli[1:]...), url)
+		cmd := exec.CommandContext(s.ctx, s.cli[0], args...) //nolint:gosec // the destination is the user's
+		cmd.Stdin = bytes.NewReader(data)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("upload %s: %w: %s", url, err, msg)
+			}
+			return fmt.Errorf("upload %s: %w", url, err)
+		}
+		return nil
+	})
+	return nil
+}
+
+// Close implements Destination.
+func (s *objectStore) Close() error {
+	return s.g.Wait()
+}
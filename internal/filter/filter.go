@@ -0,0 +1,139 @@
+// Package filter pipes messages and transcripts through external commands, and runs
+// commands on them.
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Exec runs Command once per message, with the message's JSON on stdin, and replaces
+// the message's text with the command's output (trailing newlines trimmed). Attachments
+// with text are piped through it the same way, as their own JSON. Content the command
+// does not rewrite is dropped, so none of the original text survives filtering: blocks,
+// which repeat the text; attachment fallbacks, fields, and shared-message blocks; and
+// file previews.
+type Exec struct {
+	Command string // run with sh -c (cmd /C on Windows)
+}
+
+// Apply filters each message in place, stopping at the first failure.
+func (f Exec) Apply(ctx context.Context, messages []map[string]any) error {
+	for _, msg := range messages {
+		if err := f.apply(ctx, msg); err != nil {
+			ts, _ := msg["ts"].(string)
+			return fmt.Errorf("exec filter on message %s: %w", ts, err)
+		}
+	}
+	return nil
+}
+
+func (f Exec) apply(ctx context.Context, msg map[string]any) error {
+	input, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+	out, err := Pipe(ctx, f.Command, input)
+	if err != nil {
+		return err
+	}
+	msg["text"] = out
+	delete(msg, "blocks")
+
+	attachments, _ := msg["attachments"].([]any)
+	for _, a := range attachments {
+		att, _ := a.(map[string]any)
+		if att == nil {
+			continue
+		}
+		for _, field := range []string{"fallback", "pretext", "fields", "blocks", "message_blocks"} {
+			delete(att, field)
+		}
+		if text, _ := att["text"].(string); text == "" {
+			continue
+		}
+		input, err := json.Marshal(att)
+		if err != nil {
+			return fmt.Errorf("marshal attachment: %w", err)
+		}
+		if att["text"], err = Pipe(ctx, f.Command, input); err != nil {
+			return err
+		}
+	}
+
+	files, _ := msg["files"].([]any)
+	for _, fl := range files {
+		if file, _ := fl.(map[string]any); file != nil {
+			for _, field := range []string{"preview", "preview_highlight", "preview_plain_text", "plain_text"} {
+				delete(file, field)
+			}
+		}
+	}
+	return nil
+}
+
+// Pipe runs command with sh -c (cmd /C on Windows), feeding it input, and returns
+// its output with trailing newlines trimmed. A failure includes the command's stderr.
+func Pipe(ctx context.Context, command string, input []byte) (string, error) {
+	cmd := shell(ctx, command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// ErrPlaceholder is returned by Run for a command with {} where there is no sh to pass
+// the argument to safely (Windows).
+var ErrPlaceholder = errors.New("{} in a command needs sh (not available on Windows); read stdin instead")
+
+// Run runs command as Pipe does but for its effect, with arg in place of each {} and input
+// on stdin. The command's output goes to stderr, and a failure includes it. arg reaches
+// sh as a positional parameter ("$1") rather than spliced into the command, so no text
+// in it is run as shell syntax.
+func Run(ctx context.Context, command, arg string, input []byte) error {
+	if strings.Contains(command, "{}") {
+		if runtime.GOOS == "windows" {
+			return ErrPlaceholder
+		}
+		command = strings.ReplaceAll(command, "{}", `"$1"`)
+	}
+	cmd := shell(ctx, command, arg)
+	cmd.Stdin = bytes.NewReader(input)
+	var out bytes.Buffer
+	cmd.Stdout = io.MultiWriter(os.Stderr, &out)
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// shell returns the command that runs command with sh -c (cmd /C on Windows), with args
+// as sh's positional parameters $1, $2, ...; cmd takes none.
+func shell(ctx context.Context, command string, args ...string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) //nolint:gosec // running the user's command is the point
+	}
+	args = append([]string{"-c", command, "sh"}, args...)
+	return exec.CommandContext(ctx, "sh", args...) //nolint:gosec // running the user's command is the point
+}
@@ -0,0 +1,105 @@
+package filter_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/filter"
+)
+
+func TestExec_ReplacesText(t *testing.T) {
+	messages := []map[string]any{
+		{"ts": "1770000001.000000", "text": "call me at 555-1234"},
+		{"ts": "1770000002.000000", "text": "no numbers here"},
+	}
+
+	// The command sees the message JSON; replace every digit.
+	f := filter.Exec{Command: `sed -e 's/.*"text":"\([^"]*\)".*/\1/' -e 's/[0-9]/#/g'`}
+	if err := f.Apply(t.Context(), messages); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"call me at ###-####", "no numbers here"}
+	for i, msg := range messages {
+		if msg["text"] != want[i] {
+			t.Errorf("message %d: got text %q, want %q", i, msg["text"], want[i])
+		}
+	}
+}
+
+func TestExec_ReportsFailure(t *testing.T) {
+	messages := []map[string]any{{"ts": "1770000001.000000", "text": "hi"}}
+
+	err := filter.Exec{Command: "echo broken >&2; exit 3"}.Apply(t.Context(), messages)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if want := "exec filter on message 1770000001.000000: exit status 3: broken"; err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
+
+func TestExec_FiltersAllText(t *testing.T) {
+	secret := "555-1234"
+	messages := []map[string]any{{
+		"ts":   "1770000001.000000",
+		"text": "call me at " + secret,
+		"blocks": []any{map[string]any{"type": "rich_text", "elements": []any{
+			map[string]any{"type": "text", "text": "call me at " + secret},
+		}}},
+		"attachments": []any{
+			map[string]any{"text": "or at " + secret, "fallback": "or at " + secret, "pretext": secret},
+			map[string]any{"is_share": true, "message_blocks": []any{map[string]any{"message": map[string]any{
+				"blocks": []any{map[string]any{"type": "rich_text", "elements": []any{
+					map[string]any{"type": "text", "text": secret},
+				}}},
+			}}}},
+		},
+		"files": []any{map[string]any{"id": "F1", "preview": secret, "plain_text": secret}},
+	}}
+
+	if err := (filter.Exec{Command: "echo '[redacted]'"}).Apply(t.Context(), messages); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Errorf("filtered JSON still contains %q: %s", secret, data)
+	}
+	att := messages[0]["attachments"].([]any)[0].(map[string]any)
+	if messages[0]["text"] != "[redacted]" || att["text"] != "[redacted]" {
+		t.Errorf("got text %q and attachment text %q, want both [redacted]", messages[0]["text"], att["text"])
+	}
+}
+
+func TestRun_PassesArgument(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	// The argument is shell syntax that must not run.
+	arg := `SEV1 $(touch ` + filepath.Join(dir, "pwned") + `); 'quoted'`
+	command := `printf '%s|' {} > ` + out + `; cat >> ` + out
+	if err := filter.Run(t.Context(), command, arg, []byte(`{"ok":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := arg + `|{"ok":true}`; string(got) != want {
+		t.Errorf("command saw %q, want %q", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+		t.Error("the argument was run as shell syntax")
+	}
+
+	err = filter.Run(t.Context(), "echo broken; exit 3", "", nil)
+	if err == nil || err.Error() != "exit status 3: broken" {
+		t.Errorf("got error %v, want the exit status and output", err)
+	}
+}
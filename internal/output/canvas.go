@@ -0,0 +1,277 @@
+package output
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// blankLines matches runs of blank lines, which are collapsed to one.
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	// trailingSpace matches spaces at the end of a line.
+	trailingSpace = regexp.MustCompile(` +\n`)
+)
+
+// CanvasMarkdown converts a canvas's HTML (as downloaded from its private URL) to
+// GitHub-flavored markdown: headings, paragraphs, lists and checklists, links, emphasis,
+// code, quotes, and tables. Other markup is dropped, keeping its text.
+func CanvasMarkdown(html string) (string, error) {
+	d := xml.NewDecoder(strings.NewReader(html))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	r := &canvasRenderer{out: []*strings.Builder{{}}}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse canvas: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			r.start(strings.ToLower(t.Name.Local), t.Attr)
+		case xml.EndElement:
+			r.end(strings.ToLower(t.Name.Local))
+		case xml.CharData:
+			r.text(string(t))
+		}
+	}
+
+	md := trailingSpace.ReplaceAllString(r.out[0].String(), "\n")
+	md = blankLines.ReplaceAllString(md, "\n\n")
+	return strings.TrimSpace(md) + "\n", nil
+}
+
+// canvasRenderer writes markdown as HTML tokens arrive. Elements whose markdown wraps
+// their content (links, quotes, table cells) render into a builder of their own,
+// pushed onto out, that is popped and written out when the element ends.
+type canvasRenderer struct {
+	out   []*strings.Builder
+	lists []canvasList
+	links []string
+	row   []string // cells of the table row being rendered
+	rows  int      // rows rendered in the current table
+	pre   int
+	skip  int // depth inside elements whose content is dropped
+}
+
+type canvasList struct {
+	ordered   bool
+	checklist bool
+	n         int
+}
+
+func (r *canvasRenderer) b() *strings.Builder {
+	return r.out[len(r.out)-1]
+}
+
+func (r *canvasRenderer) push() {
+	r.out = append(r.out, &strings.Builder{})
+}
+
+func (r *canvasRenderer) pop() string {
+	s := r.b().String()
+	r.out = r.out[:len(r.out)-1]
+	return s
+}
+
+// block starts a new paragraph-level element.
+func (r *canvasRenderer) block() {
+	r.b().WriteString("\n\n")
+}
+
+func (r *canvasRenderer) start(name string, attrs []xml.Attr) {
+	if r.skip > 0 {
+		if name == "head" || name == "script" || name == "style" || name == "title" {
+			r.skip++
+		}
+		return
+	}
+	switch name {
+	case "head", "script", "style", "title":
+		r.skip++
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.block()
+		r.b().WriteString(strings.Repeat("#", int(name[1]-'0')) + " ")
+	case "p", "div":
+		if len(r.lists) == 0 {
+			r.block()
+		}
+	case "br":
+		r.b().WriteString("\n")
+	case "hr":
+		r.block()
+		r.b().WriteString("---")
+		r.block()
+	case "ul", "ol":
+		if len(r.lists) == 0 {
+			r.block()
+		}
+		r.lists = append(r.lists, canvasList{
+			ordered:   name == "ol",
+			checklist: strings.Contains(attr(attrs, "class"), "checklist"),
+		})
+	case "li":
+		r.b().WriteString("\n")
+		if len(r.lists) == 0 {
+			r.b().WriteString("- ")
+			return
+		}
+		l := &r.lists[len(r.lists)-1]
+		l.n++
+		r.b().WriteString(strings.Repeat("  ", len(r.lists)-1))
+		switch {
+		case l.checklist && strings.Contains(attr(attrs, "class"), "checked"):
+			r.b().WriteString("- [x] ")
+		case l.checklist:
+			r.b().WriteString("- [ ] ")
+		case l.ordered:
+			fmt.Fprintf(r.b(), "%d. ", l.n)
+		default:
+			r.b().WriteString("- ")
+		}
+	case "b", "strong":
+		r.b().WriteString("**")
+	case "i", "em":
+		r.b().WriteString("_")
+	case "s", "del", "strike":
+		r.b().WriteString("~~")
+	case "code":
+		if r.pre == 0 {
+			r.b().WriteString("`")
+		}
+	case "pre":
+		r.block()
+		r.b().WriteString("```\n")
+		r.pre++
+	case "a":
+		r.links = append(r.links, attr(attrs, "href"))
+		r.push()
+	case "img":
+		fmt.Fprintf(r.b(), "![%s](%s)", attr(attrs, "alt"), attr(attrs, "src"))
+	case "blockquote":
+		r.push()
+	case "table":
+		r.block()
+		r.rows = 0
+	case "tr":
+		r.row = nil
+	case "td", "th":
+		r.push()
+	}
+}
+
+func (r *canvasRenderer) end(name string) {
+	if r.skip > 0 {
+		if name == "head" || name == "script" || name == "style" || name == "title" {
+			r.skip--
+		}
+		return
+	}
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "p", "div":
+		if len(r.lists) == 0 {
+			r.block()
+		}
+	case "ul", "ol":
+		if len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+		if len(r.lists) == 0 {
+			r.block()
+		}
+	case "b", "strong":
+		r.b().WriteString("**")
+	case "i", "em":
+		r.b().WriteString("_")
+	case "s", "del", "strike":
+		r.b().WriteString("~~")
+	case "code":
+		if r.pre == 0 {
+			r.b().WriteString("`")
+		}
+	case "pre":
+		if r.pre > 0 {
+			r.pre--
+		}
+		r.b().WriteString("\n```")
+		r.block()
+	case "a":
+		if len(r.links) == 0 || len(r.out) < 2 {
+			return
+		}
+		href := r.links[len(r.links)-1]
+		r.links = r.links[:len(r.links)-1]
+		text := strings.TrimSpace(r.pop())
+		switch {
+		case href == "" || href == text:
+			r.b().WriteString(text)
+		case text == "":
+			r.b().WriteString(href)
+		default:
+			fmt.Fprintf(r.b(), "[%s](%s)", text, href)
+		}
+	case "blockquote":
+		if len(r.out) < 2 {
+			return
+		}
+		quoted := blankLines.ReplaceAllString(strings.TrimSpace(r.pop()), "\n\n")
+		r.block()
+		r.b().WriteString("> " + strings.ReplaceAll(quoted, "\n", "\n> "))
+		r.block()
+	case "td", "th":
+		if len(r.out) < 2 {
+			return
+		}
+		cell := strings.Join(strings.Fields(r.pop()), " ")
+		r.row = append(r.row, strings.ReplaceAll(cell, "|", `\|`))
+	case "tr":
+		r.b().WriteString("\n| " + strings.Join(r.row, " | ") + " |")
+		if r.rows == 0 {
+			r.b().WriteString("\n|" + strings.Repeat(" --- |", len(r.row)))
+		}
+		r.rows++
+	case "table":
+		r.block()
+	}
+}
+
+func (r *canvasRenderer) text(s string) {
+	if r.skip > 0 {
+		return
+	}
+	if r.pre > 0 {
+		r.b().WriteString(s)
+		return
+	}
+	// Outside <pre>, runs of whitespace are one space, and none starts a line.
+	collapsed := strings.Join(strings.Fields(s), " ")
+	cur := r.b().String()
+	if s != "" && strings.TrimLeft(s[:1], " \t\r\n") == "" &&
+		cur != "" && !strings.HasSuffix(cur, " ") && !strings.HasSuffix(cur, "\n") {
+		r.b().WriteString(" ")
+	}
+	if collapsed == "" {
+		return
+	}
+	r.b().WriteString(collapsed)
+	if strings.TrimRight(s[len(s)-1:], " \t\r\n") == "" {
+		r.b().WriteString(" ")
+	}
+}
+
+func attr(attrs []xml.Attr, name string) string {
+	for _, a := range attrs {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
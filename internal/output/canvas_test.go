@@ -0,0 +1,35 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+)
+
+func TestCanvasMarkdown(t *testing.T) {
+	html := `<!DOCTYPE html><html><head><meta charset="utf-8"><title>Runbook</title></head><body>
+<h1>On-call runbook</h1>
+<p>Page the <b>primary</b> first, then see <a href="https://example.com/esc">escalation</a>.<br>Thanks &amp; good luck.</p>
+<ul class="checklist"><li class="checked">Ack the page</li><li>Open an incident</li></ul>
+<ol><li>Check <code>status</code></li><li>Restart<ul><li>web</li></ul></li></ol>
+<blockquote><p>Stay calm</p></blockquote>
+<pre><code>kubectl get pods
+kubectl logs web</code></pre>
+<table><tr><th>Service</th><th>Owner</th></tr><tr><td>web</td><td>@alice | @bob</td></tr></table>
+</body></html>`
+
+	got, err := output.CanvasMarkdown(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# On-call runbook\n\n" +
+		"Page the **primary** first, then see [escalation](https://example.com/esc).\nThanks & good luck.\n\n" +
+		"- [x] Ack the page\n- [ ] Open an incident\n\n" +
+		"1. Check `status`\n2. Restart\n  - web\n\n" +
+		"> Stay calm\n\n" +
+		"```\nkubectl get pods\nkubectl logs web\n```\n\n" +
+		"| Service | Owner |\n| --- | --- |\n| web | @alice \\| @bob |\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"errors"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Exit codes, so scripts can tell failure classes apart.
+const (
+	ExitError       = 1 // any failure not listed below
+	ExitUsage       = 2 // invalid flags or arguments
+	ExitAuth        = 3 // missing or unusable credentials
+	ExitNotFound    = 4 // no such channel, user, message, etc.
+	ExitRateLimited = 5 // still rate limited after all retries
+	ExitNetwork     = 6 // Slack could not be reached, or --timeout expired
+)
+
+// exitKinds names each exit code in JSON errors.
+var exitKinds = map[int]string{
+	ExitError:       "error",
+	ExitUsage:       "usage",
+	ExitAuth:        "auth",
+	ExitNotFound:    "not_found",
+	ExitRateLimited: "rate_limited",
+	ExitNetwork:     "network",
+}
+
+// ExitCode returns the process exit code for err.
+func ExitCode(err error) int {
+	switch {
+	case errors.Is(err, islack.ErrAuthFailed):
+		return ExitAuth
+	case errors.Is(err, islack.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, islack.ErrRateLimited):
+		return ExitRateLimited
+	case errors.Is(err, islack.ErrNetwork):
+		return ExitNetwork
+	}
+	return ExitError
+}
@@ -0,0 +1,35 @@
+package output_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"generic", errors.New("boom"), output.ExitError},
+		{"auth", fmt.Errorf("%w: no token", islack.ErrAuthFailed), output.ExitAuth},
+		{"wrapped auth", fmt.Errorf("whoami: %w", fmt.Errorf("%w: no token", islack.ErrAuthFailed)), output.ExitAuth},
+		{"revoked token", &islack.APIError{Method: "auth.test", Code: "token_revoked"}, output.ExitAuth},
+		{"unknown channel", fmt.Errorf("history: %w", &islack.APIError{Method: "conversations.history", Code: "channel_not_found"}), output.ExitNotFound},
+		{"unknown user", fmt.Errorf("%w with email a@b.c", islack.ErrUserNotFound), output.ExitNotFound},
+		{"rate limited", &islack.APIError{Method: "conversations.list", Code: "ratelimited"}, output.ExitRateLimited},
+		{"network", fmt.Errorf("slack API x: %w", islack.ErrNetwork), output.ExitNetwork},
+		{"other API error", &islack.APIError{Method: "conversations.history", Code: "not_in_channel"}, output.ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := output.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"fmt"
+	"time"
+
+	slackmd "github.com/rneatherway/slack/pkg/markdown"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// HuddleSummary describes a huddle or call message in words, e.g.
+// "Huddle started by alice, 4 participants, 32 min". ok is false for other messages.
+func HuddleSummary(msg map[string]any, users slackmd.UserProvider) (summary string, ok bool, err error) {
+	h, ok := islack.ParseHuddle(msg)
+	if !ok {
+		return "", false, nil
+	}
+
+	summary = h.Kind
+	if h.CreatedBy != "" {
+		name, err := users.UsernameForID(h.CreatedBy)
+		if err != nil {
+			return "", true, err
+		}
+		summary += " started by " + name
+	}
+	switch n := len(h.Participants); n {
+	case 0:
+	case 1:
+		summary += ", 1 participant"
+	default:
+		summary += fmt.Sprintf(", %d participants", n)
+	}
+	switch d := h.Duration(); {
+	case h.End.IsZero() && !h.Start.IsZero():
+		summary += ", ongoing"
+	case d > 0:
+		summary += ", " + formatMinutes(d)
+	}
+	return summary, true, nil
+}
+
+// formatMinutes formats a duration as "32 min" or "1 h 5 min".
+func formatMinutes(d time.Duration) string {
+	mins := int(d.Round(time.Minute).Minutes())
+	switch {
+	case mins < 1:
+		return "<1 min"
+	case mins < 60:
+		return fmt.Sprintf("%d min", mins)
+	case mins%60 == 0:
+		return fmt.Sprintf("%d h", mins/60)
+	default:
+		return fmt.Sprintf("%d h %d min", mins/60, mins%60)
+	}
+}
@@ -4,32 +4,75 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 )
 
 // PrintJSON marshals v to compact JSON (pruning nil/empty/zero fields) with 2-space indent and prints to stdout.
 func PrintJSON(v any) {
-	pruned := prune(v)
-	data, err := json.MarshalIndent(pruned, "", "  ")
+	if err := WriteJSON(os.Stdout, v); err != nil {
+		PrintError(err)
+	}
+}
+
+// WriteJSON writes v to w as PrintJSON formats it.
+func WriteJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(prune(v), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// MarshalLine returns v as PrintJSON formats it, but compact and on one line, for streams
+// of JSON values (JSON Lines).
+func MarshalLine(v any) ([]byte, error) {
+	data, err := json.Marshal(prune(v))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, `{"error":"%s"}`+"\n", err.Error())
-		os.Exit(1)
+		return nil, fmt.Errorf("marshal json: %w", err)
 	}
-	fmt.Println(string(data))
+	return data, nil
 }
 
-// PrintError prints a JSON error to stderr and exits with code 1.
+// PrintError prints a JSON error to stderr and exits with the code ExitCode returns for err.
 func PrintError(err error) {
-	fmt.Fprintf(os.Stderr, `{"error":"%s"}`+"\n", err.Error())
-	os.Exit(1)
+	Exit(err, ExitCode(err))
+}
+
+// exitHooks run, in order, before Exit ends the process.
+var exitHooks []func()
+
+// OnExit registers f to run after Exit prints its error and before the process exits,
+// so that a failing command can still report (e.g., --stats).
+func OnExit(f func()) {
+	exitHooks = append(exitHooks, f)
+}
+
+// Exit prints a JSON error to stderr and exits with code. The error's kind names the
+// code (e.g., "not_found"), for scripts that parse the error rather than the status.
+func Exit(err error, code int) {
+	data, _ := json.Marshal(map[string]string{"error": err.Error(), "kind": exitKinds[code]})
+	fmt.Fprintln(os.Stderr, string(data))
+	for _, f := range exitHooks {
+		f()
+	}
+	os.Exit(code)
 }
 
 // prune recursively removes nil, empty, and zero-value fields from maps and slices.
+// json.RawMessage values are kept as they are.
 func prune(v any) any {
 	if v == nil {
 		return nil
 	}
+	if raw, ok := v.(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return nil
+		}
+		return raw
+	}
 
 	rv := reflect.ValueOf(v)
 	switch rv.Kind() {
@@ -1,11 +1,16 @@
 package output
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
+	"time"
 
 	slackmd "github.com/rneatherway/slack/pkg/markdown"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
 )
 
 // UserResolver resolves user IDs and message authors to display names.
@@ -14,9 +19,23 @@ type UserResolver interface {
 	UsernameForMessage(msg map[string]any) (string, error)
 }
 
+// MarkdownOptions adjusts how FormatMarkdownWithOptions renders messages.
+type MarkdownOptions struct {
+	// Highlight, if set, bolds its matches in message text (e.g., for message list --grep).
+	Highlight *regexp.Regexp
+	// AuthorLocations, if set, maps user IDs to time zones. Headers of messages by these
+	// users add the time in the author's zone, e.g. "09:12 (author local)".
+	AuthorLocations map[string]*time.Location
+}
+
 // FormatMarkdown converts Slack messages to GitHub-flavored markdown,
 // following the rneatherway/gh-slack blockquote style.
 func FormatMarkdown(messages []map[string]any, users UserResolver) (string, error) {
+	return FormatMarkdownWithOptions(messages, users, MarkdownOptions{})
+}
+
+// FormatMarkdownWithOptions is FormatMarkdown with rendering options.
+func FormatMarkdownWithOptions(messages []map[string]any, users UserResolver, opts MarkdownOptions) (string, error) {
 	b := &strings.Builder{}
 
 	type msgMeta struct {
@@ -73,19 +92,28 @@ func FormatMarkdown(messages []map[string]any, users UserResolver) (string, erro
 			if err != nil {
 				return "", err
 			}
-			fmt.Fprintf(b, "> **%s** at %s\n",
+			fmt.Fprintf(b, "> **%s** at %s%s\n",
 				username,
-				tm.UTC().Format("2006-01-02 15:04 MST"))
+				tm.UTC().Format("2006-01-02 15:04 MST"),
+				authorLocalTime(msg, *tm, opts.AuthorLocations))
 		}
 		fmt.Fprintf(b, ">\n")
 
+		huddle, isHuddle, err := HuddleSummary(msg, users)
+		if err != nil {
+			return "", err
+		}
+		if isHuddle {
+			fmt.Fprintf(b, "> _%s_\n", huddle)
+		}
+
 		text, _ := msg["text"].(string)
 		if text != "" {
 			converted, err := slackmd.Convert(users, text)
 			if err != nil {
 				return "", err
 			}
-			for line := range strings.SplitSeq(converted, "\n") {
+			for line := range strings.SplitSeq(highlight(converted, opts.Highlight), "\n") {
 				fmt.Fprintf(b, "> %s\n", line)
 			}
 		}
@@ -97,19 +125,41 @@ func FormatMarkdown(messages []map[string]any, users UserResolver) (string, erro
 				if att == nil {
 					continue
 				}
+				if islack.IsSharedMessage(att) {
+					if err := writeSharedMessage(b, att, users, opts); err != nil {
+						return "", err
+					}
+					continue
+				}
 				attText, _ := att["text"].(string)
 				if attText != "" {
 					converted, err := slackmd.Convert(users, attText)
 					if err != nil {
 						return "", err
 					}
-					for line := range strings.SplitSeq(converted, "\n") {
+					for line := range strings.SplitSeq(highlight(converted, opts.Highlight), "\n") {
 						fmt.Fprintf(b, "> %s\n", line)
 					}
 				}
 			}
 		}
 
+		if editedAt, ok := islack.EditedAt(msg); ok {
+			fmt.Fprintf(b, "> _(edited %s)_\n", editedAt.UTC().Format("2006-01-02 15:04 MST"))
+		}
+
+		// Reactions are listed only once their users are resolved (message list --with-reactions).
+		reactions, _ := msg["reactions"].([]any)
+		for _, r := range reactions {
+			reaction, _ := r.(map[string]any)
+			names, ok := reaction["user_names"].([]string)
+			if !ok {
+				continue
+			}
+			name, _ := reaction["name"].(string)
+			fmt.Fprintf(b, "> :%s: %s\n", name, strings.Join(names, ", "))
+		}
+
 		if !includeSpeakerHeader {
 			b.WriteString("\n")
 		}
@@ -120,11 +170,135 @@ func FormatMarkdown(messages []map[string]any, users UserResolver) (string, erro
 	return b.String(), nil
 }
 
+// authorLocalTime returns ", 09:12 (author local)" for a message whose author's time zone
+// is in locations, with the date when it differs from the UTC date, or else "".
+func authorLocalTime(msg map[string]any, tm time.Time, locations map[string]*time.Location) string {
+	userID, _ := msg["user"].(string)
+	loc := locations[userID]
+	if loc == nil {
+		return ""
+	}
+	local := tm.In(loc)
+	layout := "15:04"
+	if local.Format(time.DateOnly) != tm.UTC().Format(time.DateOnly) {
+		layout = "2006-01-02 15:04"
+	}
+	return ", " + local.Format(layout) + " (author local)"
+}
+
+// writeSharedMessage writes a message shared into another (an attachment with is_share or
+// is_msg_unfurl) as a quote nested in the sharing message's, headed by its author, channel,
+// and time.
+func writeSharedMessage(b *strings.Builder, att map[string]any, users UserResolver, opts MarkdownOptions) error {
+	author, _ := att["author_name"].(string)
+	if author == "" {
+		if id, _ := att["author_id"].(string); id != "" {
+			var err error
+			if author, err = users.UsernameForID(id); err != nil {
+				return err
+			}
+		}
+	}
+	header := "**" + cmp.Or(author, "unknown") + "**"
+	if name, _ := att["channel_name"].(string); name != "" {
+		header += " in #" + name
+	} else if id, _ := att["channel_id"].(string); id != "" {
+		header += " in " + id
+	}
+	if ts, _ := att["ts"].(string); ts != "" {
+		if tm, err := slackmd.ParseUnixTimestamp(ts); err == nil {
+			header += " at " + tm.UTC().Format("2006-01-02 15:04 MST")
+		}
+	}
+	fmt.Fprintf(b, "> > %s\n", header)
+
+	if text := islack.SharedMessageText(att); text != "" {
+		converted, err := slackmd.Convert(users, text)
+		if err != nil {
+			return err
+		}
+		for line := range strings.SplitSeq(highlight(converted, opts.Highlight), "\n") {
+			fmt.Fprintf(b, "> > %s\n", line)
+		}
+	}
+	return nil
+}
+
+// highlight bolds the non-empty matches of re in text. A nil re leaves text unchanged.
+func highlight(text string, re *regexp.Regexp) string {
+	if re == nil {
+		return text
+	}
+	return re.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.TrimSpace(m) == "" {
+			return m
+		}
+		return "**" + m + "**"
+	})
+}
+
 // PrintMarkdown formats messages as markdown and prints to stdout.
 func PrintMarkdown(messages []map[string]any, users UserResolver) {
-	md, err := FormatMarkdown(messages, users)
+	PrintMarkdownWithOptions(messages, users, MarkdownOptions{})
+}
+
+// PrintMarkdownWithOptions is PrintMarkdown with rendering options.
+func PrintMarkdownWithOptions(messages []map[string]any, users UserResolver, opts MarkdownOptions) {
+	md, err := FormatMarkdownWithOptions(messages, users, opts)
 	if err != nil {
 		PrintError(err)
 	}
 	fmt.Print(md)
 }
+
+// AddRendered adds two fields to each message for JSON output: blocks_raw, the message's
+// blocks exactly as Slack sent them (PrintJSON keeps json.RawMessage values verbatim, where
+// it would prune empty and false fields from blocks), and text_rendered, from RenderText.
+func AddRendered(messages []map[string]any, users UserResolver) error {
+	for _, msg := range messages {
+		if blocks, ok := msg["blocks"]; ok {
+			raw, err := json.Marshal(blocks)
+			if err != nil {
+				return fmt.Errorf("marshal blocks: %w", err)
+			}
+			msg["blocks_raw"] = json.RawMessage(raw)
+		}
+		text, err := RenderText(msg, users)
+		if err != nil {
+			return err
+		}
+		msg["text_rendered"] = text
+	}
+	return nil
+}
+
+// RenderText converts a message's text, and its attachments' text (including that of
+// messages shared into it), from Slack mrkdwn to markdown with mentions resolved, as
+// markdown output shows it. Parts are separated by blank lines.
+func RenderText(msg map[string]any, users UserResolver) (string, error) {
+	text, _ := msg["text"].(string)
+	texts := []string{text}
+	attachments, _ := msg["attachments"].([]any)
+	for _, a := range attachments {
+		att, _ := a.(map[string]any)
+		if islack.IsSharedMessage(att) {
+			texts = append(texts, islack.SharedMessageText(att))
+			continue
+		}
+		attText, _ := att["text"].(string)
+		texts = append(texts, attText)
+	}
+
+	var parts []string
+	for _, t := range texts {
+		if t == "" {
+			continue
+		}
+		converted, err := slackmd.Convert(users, t)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, converted)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
@@ -1,10 +1,14 @@
 package output_test
 
 import (
+	"encoding/json"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
 )
 
 type testUserResolver struct {
@@ -142,3 +146,196 @@ func TestFormatMarkdown_Links(t *testing.T) {
 		t.Errorf("expected markdown link, got:\n%s", result)
 	}
 }
+
+func TestFormatMarkdown_Huddle(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice"}}
+	messages := []map[string]any{{
+		"user": "U1", "subtype": "huddle_thread", "ts": "1770165000.000100",
+		"room": map[string]any{
+			"created_by":          "U1",
+			"date_start":          float64(1770165000),
+			"date_end":            float64(1770166920),
+			"participant_history": []any{"U1", "U2", "U3", "U4"},
+		},
+	}}
+
+	result, err := output.FormatMarkdown(messages, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "> _Huddle started by alice, 4 participants, 32 min_\n") {
+		t.Errorf("huddle not summarized:\n%s", result)
+	}
+}
+
+func TestFormatMarkdown_Edited(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice"}}
+	messages := []map[string]any{{
+		"user": "U1", "text": "deploy at 4", "ts": "1710687600.000100",
+		"edited": map[string]any{"user": "U1", "ts": "1710687720.000000"},
+	}}
+
+	result, err := output.FormatMarkdown(messages, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "> _(edited 2024-03-17 15:02 UTC)_\n") {
+		t.Errorf("edit not marked:\n%s", result)
+	}
+}
+
+func TestFormatMarkdown_ReactionUsers(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice"}}
+	messages := []map[string]any{{
+		"user": "U1", "text": "ship it?", "ts": "1710687600.000100",
+		"reactions": []any{
+			map[string]any{"name": "white_check_mark", "count": float64(2), "users": []any{"U2", "U3"},
+				"user_names": []string{"bob", "carol"}},
+		},
+	}}
+
+	result, err := output.FormatMarkdown(messages, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "> :white_check_mark: bob, carol\n") {
+		t.Errorf("reaction users not listed:\n%s", result)
+	}
+}
+
+func TestFormatMarkdownWithOptions_Highlight(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice"}}
+	messages := []map[string]any{
+		{"user": "U1", "text": "starting the rollback now", "ts": "1710687600.000100"},
+	}
+
+	result, err := output.FormatMarkdownWithOptions(messages, users,
+		output.MarkdownOptions{Highlight: regexp.MustCompile(`rollback|revert`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "> starting the **rollback** now\n") {
+		t.Errorf("match not highlighted:\n%s", result)
+	}
+}
+
+func TestFormatMarkdownWithOptions_AuthorLocations(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice", "U2": "bob", "U3": "carol"}}
+	messages := []map[string]any{
+		{"user": "U1", "text": "paging oncall", "ts": "1710687600.000100"},
+		{"user": "U2", "text": "on it", "ts": "1710687660.000100"},
+		{"user": "U3", "text": "thanks", "ts": "1710687720.000100"},
+	}
+
+	result, err := output.FormatMarkdownWithOptions(messages, users, output.MarkdownOptions{
+		AuthorLocations: map[string]*time.Location{
+			"U1": time.FixedZone("EDT", -4*60*60),
+			"U2": time.FixedZone("JST", 9*60*60),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> **alice** at 2024-03-17 15:00 UTC, 11:00 (author local)\n",
+		"> **bob** at 2024-03-17 15:01 UTC, 2024-03-18 00:01 (author local)\n",
+		"> **carol** at 2024-03-17 15:02 UTC\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q in:\n%s", want, result)
+		}
+	}
+}
+
+func TestAddRendered(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U123": "alice"}}
+	messages := []map[string]any{{
+		"ts":   "1679058753.0",
+		"text": "hi <@U123>, *ship it*",
+		"blocks": []any{map[string]any{
+			"type":     "rich_text",
+			"elements": []any{map[string]any{"type": "text", "text": "", "style": map[string]any{"bold": false}}},
+		}},
+		"attachments": []any{
+			map[string]any{"text": "preview"},
+			map[string]any{"is_share": true, "message_blocks": []any{map[string]any{"message": map[string]any{
+				"blocks": []any{map[string]any{"type": "rich_text", "elements": []any{
+					map[string]any{"type": "rich_text_section", "elements": []any{
+						map[string]any{"type": "text", "text": "forwarded for "},
+						map[string]any{"type": "user", "user_id": "U123"},
+					}},
+				}}},
+			}}}},
+		},
+	}}
+
+	if err := output.AddRendered(messages, users); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := messages[0]["text_rendered"], "hi `@alice`, *ship it*\n\npreview\n\nforwarded for `@alice`"; got != want {
+		t.Errorf("text_rendered = %q, want %q", got, want)
+	}
+
+	// blocks_raw survives JSON output unpruned, unlike blocks.
+	var b strings.Builder
+	if err := output.WriteJSON(&b, messages[0]); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := got["blocks_raw"].([]any)
+	block, _ := raw[0].(map[string]any)
+	elements, _ := block["elements"].([]any)
+	element, _ := elements[0].(map[string]any)
+	if _, ok := element["text"]; !ok || element["style"] == nil {
+		t.Errorf("blocks_raw = %v, want empty and false fields kept", got["blocks_raw"])
+	}
+}
+
+func TestFormatMarkdown_SharedMessage(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U123": "alice", "U456": "bob"}}
+	messages := []map[string]any{{
+		"user": "U456",
+		"text": "FYI",
+		"ts":   "1679058753.0",
+		"attachments": []any{map[string]any{
+			"is_share":     true,
+			"author_id":    "U123",
+			"channel_name": "general",
+			"ts":           "1679050000.000100",
+			"text":         "we ship on *Friday*",
+		}},
+	}}
+
+	result, err := output.FormatMarkdown(messages, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "> FYI\n> > **alice** in #general at 2023-03-17 10:46 UTC\n> > we ship on *Friday*\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected shared message quoted under the message, got:\n%s", result)
+	}
+}
+
+func TestFormatSavedMarkdown(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U123": "alice"}}
+	items := []*islack.SavedItem{
+		{Type: "message", State: islack.SavedInProgress, DueAt: "2026-03-03T10:00:00Z", Permalink: "https://myteam.slack.com/archives/C1/p1",
+			Message: map[string]any{"user": "U123", "text": "review\nthe RFC"}},
+		{Type: "message", State: islack.SavedCompleted, Message: map[string]any{"user": "U123", "text": "done"}},
+		{Type: "message", State: islack.SavedArchived},
+	}
+
+	got, err := output.FormatSavedMarkdown(items, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- [ ] **alice**: review the RFC (due 2026-03-03 10:00 UTC) [link](https://myteam.slack.com/archives/C1/p1)\n" +
+		"- [x] **alice**: done\n" +
+		"- [ ] _(message deleted)_ (archived)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
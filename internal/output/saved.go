@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	slackmd "github.com/rneatherway/slack/pkg/markdown"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// FormatSavedMarkdown renders Later items as a markdown task list: completed items are
+// checked, and each shows its author, text, due date, and permalink, as in
+// "- [ ] **alice**: review the RFC (due 2026-03-03 10:00 UTC) [link](https://...)".
+func FormatSavedMarkdown(items []*islack.SavedItem, users UserResolver) (string, error) {
+	b := &strings.Builder{}
+	for _, item := range items {
+		box := "[ ]"
+		if item.State == islack.SavedCompleted {
+			box = "[x]"
+		}
+		fmt.Fprintf(b, "- %s ", box)
+
+		switch {
+		case item.Message != nil:
+			author, err := users.UsernameForMessage(item.Message)
+			if err != nil {
+				return "", err
+			}
+			text, _ := item.Message["text"].(string)
+			if text, err = slackmd.Convert(users, text); err != nil {
+				return "", err
+			}
+			fmt.Fprintf(b, "**%s**: %s", author, strings.Join(strings.Fields(text), " "))
+		case item.Type == "message":
+			b.WriteString("_(message deleted)_")
+		default:
+			fmt.Fprintf(b, "_(%s)_", item.Type)
+		}
+
+		if due := formatRFC3339(item.DueAt); due != "" {
+			fmt.Fprintf(b, " (due %s)", due)
+		}
+		if item.State == islack.SavedArchived {
+			b.WriteString(" (archived)")
+		}
+		if item.Permalink != "" {
+			fmt.Fprintf(b, " [link](%s)", item.Permalink)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// formatRFC3339 reformats an RFC 3339 time as markdown output shows times, or returns "".
+func formatRFC3339(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
@@ -0,0 +1,280 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	slackmd "github.com/rneatherway/slack/pkg/markdown"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Transcript is the chat transcript JSON shape written by DiscordChatExporter,
+// so tools that analyze or visualize those exports can read Slack channels too.
+// Slack concepts map onto it as: workspace = guild, ts = message ID, files = attachments.
+type Transcript struct {
+	Guild        TranscriptGuild     `json:"guild"`
+	Channel      TranscriptChannel   `json:"channel"`
+	ExportedAt   string              `json:"exportedAt"`
+	Messages     []TranscriptMessage `json:"messages"`
+	MessageCount int                 `json:"messageCount"`
+}
+
+// TranscriptGuild identifies the workspace a transcript came from, by its domain.
+type TranscriptGuild struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TranscriptChannel identifies a transcript's channel.
+type TranscriptChannel struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Topic string `json:"topic"`
+}
+
+// TranscriptMessage is one message in a Transcript.
+type TranscriptMessage struct {
+	ID              string                 `json:"id"`
+	Type            string                 `json:"type"` // "Default", "Reply" for thread replies, or "Call" for huddles and calls
+	Timestamp       string                 `json:"timestamp"`
+	TimestampEdited *string                `json:"timestampEdited"`
+	IsPinned        bool                   `json:"isPinned"`
+	Content         string                 `json:"content"`
+	Author          TranscriptAuthor       `json:"author"`
+	Attachments     []TranscriptAttachment `json:"attachments"`
+	Embeds          []any                  `json:"embeds"`
+	Stickers        []any                  `json:"stickers"`
+	Reactions       []TranscriptReaction   `json:"reactions"`
+	Mentions        []TranscriptAuthor     `json:"mentions"`
+}
+
+// TranscriptAuthor is a message author or mentioned user.
+type TranscriptAuthor struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Nickname  string           `json:"nickname"`
+	IsBot     bool             `json:"isBot"`
+	Roles     []TranscriptRole `json:"roles"` // a guest role for Slack guests
+	AvatarURL string           `json:"avatarUrl"`
+}
+
+// TranscriptRole is a role an author has. Slack guests have one, whose ID is
+// islack.GuestMultiChannel or islack.GuestSingleChannel.
+type TranscriptRole struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TranscriptAttachment is a file shared in a message.
+type TranscriptAttachment struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	FileName      string `json:"fileName"`
+	FileSizeBytes int64  `json:"fileSizeBytes"`
+}
+
+// TranscriptReaction is one emoji's reactions to a message.
+type TranscriptReaction struct {
+	Emoji TranscriptEmoji `json:"emoji"`
+	Count int             `json:"count"`
+}
+
+// TranscriptEmoji is a reaction emoji, by its Slack short name.
+type TranscriptEmoji struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	IsAnimated bool   `json:"isAnimated"`
+	ImageURL   string `json:"imageUrl"`
+}
+
+// AvatarResolver is implemented by UserResolvers that know users' avatar image URLs
+// (islack.UserProvider, with CaptureAvatars). Transcripts use it for authors whose
+// messages do not embed a profile.
+type AvatarResolver interface {
+	AvatarForID(id string) string
+}
+
+// GuestResolver is implemented by UserResolvers that know which users are guests
+// (islack.UserProvider). Transcripts give guests a role naming their kind of guest.
+type GuestResolver interface {
+	GuestKindForID(id string) string
+}
+
+// guestRoles names the roles of Slack guests, by guest kind.
+var guestRoles = map[string]string{
+	islack.GuestMultiChannel:  "Guest",
+	islack.GuestSingleChannel: "Single-channel guest",
+}
+
+// rolesFor returns a user's roles: a guest role if users is a GuestResolver that knows
+// the user is a guest. It is never nil, since transcript readers expect the field.
+func rolesFor(users UserResolver, id string) []TranscriptRole {
+	guests, ok := users.(GuestResolver)
+	if !ok {
+		return []TranscriptRole{}
+	}
+	kind := guests.GuestKindForID(id)
+	if kind == "" {
+		return []TranscriptRole{}
+	}
+	return []TranscriptRole{{ID: kind, Name: guestRoles[kind]}}
+}
+
+// avatarFor returns a user's avatar URL, if users is an AvatarResolver that knows it.
+func avatarFor(users UserResolver, id string) string {
+	if avatars, ok := users.(AvatarResolver); ok {
+		return avatars.AvatarForID(id)
+	}
+	return ""
+}
+
+// mentionPattern matches user mentions (<@U123> or <@U123|name>) in message text.
+var mentionPattern = regexp.MustCompile(`<@([UW][A-Z0-9]+)(?:\|[^>]*)?>`)
+
+// FormatTranscript converts Slack messages (oldest first) to a Transcript.
+// Message text is converted to markdown, with mentions resolved through users.
+// Encode the result with encoding/json rather than PrintJSON: consumers expect
+// every field to be present, even when empty.
+func FormatTranscript(guild TranscriptGuild, channel TranscriptChannel, messages []map[string]any, users UserResolver, now time.Time) (*Transcript, error) {
+	if channel.Type == "" {
+		channel.Type = "GuildTextChat"
+	}
+	t := &Transcript{
+		Guild:        guild,
+		Channel:      channel,
+		ExportedAt:   now.UTC().Format(time.RFC3339),
+		Messages:     make([]TranscriptMessage, 0, len(messages)),
+		MessageCount: len(messages),
+	}
+	for _, msg := range messages {
+		m, err := transcriptMessage(msg, users)
+		if err != nil {
+			return nil, err
+		}
+		t.Messages = append(t.Messages, m)
+	}
+	return t, nil
+}
+
+func transcriptMessage(msg map[string]any, users UserResolver) (TranscriptMessage, error) {
+	ts, _ := msg["ts"].(string)
+	tm, err := slackmd.ParseUnixTimestamp(ts)
+	if err != nil {
+		return TranscriptMessage{}, fmt.Errorf("parse timestamp %q: %w", ts, err)
+	}
+
+	m := TranscriptMessage{
+		ID:          ts,
+		Type:        "Default",
+		Timestamp:   tm.UTC().Format(time.RFC3339Nano),
+		Attachments: []TranscriptAttachment{},
+		Embeds:      []any{},
+		Stickers:    []any{},
+		Reactions:   []TranscriptReaction{},
+		Mentions:    []TranscriptAuthor{},
+	}
+	if threadTS, _ := msg["thread_ts"].(string); threadTS != "" && threadTS != ts {
+		m.Type = "Reply"
+	}
+	if edited, _ := msg["edited"].(map[string]any); edited != nil {
+		if editedTS, _ := edited["ts"].(string); editedTS != "" {
+			if et, err := slackmd.ParseUnixTimestamp(editedTS); err == nil {
+				s := et.UTC().Format(time.RFC3339Nano)
+				m.TimestampEdited = &s
+			}
+		}
+	}
+	pinnedTo, _ := msg["pinned_to"].([]any)
+	m.IsPinned = len(pinnedTo) > 0
+
+	huddle, isHuddle, err := HuddleSummary(msg, users)
+	if err != nil {
+		return TranscriptMessage{}, err
+	}
+
+	if text, _ := msg["text"].(string); text != "" {
+		if m.Content, err = slackmd.Convert(users, text); err != nil {
+			return TranscriptMessage{}, err
+		}
+		seen := make(map[string]bool)
+		for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			id := match[1]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			name, err := users.UsernameForID(id)
+			if err != nil {
+				return TranscriptMessage{}, err
+			}
+			m.Mentions = append(m.Mentions, TranscriptAuthor{
+				ID: id, Name: name, Nickname: name, Roles: rolesFor(users, id), AvatarURL: avatarFor(users, id),
+			})
+		}
+	}
+	if isHuddle {
+		m.Type = "Call"
+		m.Content = strings.TrimSpace(huddle + "\n" + m.Content)
+	}
+
+	name, err := users.UsernameForMessage(msg)
+	if err != nil {
+		return TranscriptMessage{}, err
+	}
+	m.Author = TranscriptAuthor{Name: name, Nickname: name}
+	m.Author.ID, _ = msg["user"].(string)
+	botID, _ := msg["bot_id"].(string)
+	if botID == "" {
+		botID, _ = msg["app_id"].(string)
+	}
+	if botID != "" {
+		m.Author.IsBot = true
+		if m.Author.ID == "" {
+			m.Author.ID = botID
+		}
+	}
+	m.Author.Roles = rolesFor(users, m.Author.ID)
+	m.Author.AvatarURL = avatarFor(users, m.Author.ID)
+	if profile, _ := msg["user_profile"].(map[string]any); profile != nil {
+		if url, _ := profile["image_72"].(string); url != "" {
+			m.Author.AvatarURL = url
+		}
+		if handle, _ := profile["name"].(string); handle != "" {
+			m.Author.Name = handle
+		}
+	}
+
+	files, _ := msg["files"].([]any)
+	for _, f := range files {
+		file, _ := f.(map[string]any)
+		if file == nil {
+			continue
+		}
+		a := TranscriptAttachment{}
+		a.ID, _ = file["id"].(string)
+		a.URL, _ = file["url_private"].(string)
+		a.FileName, _ = file["name"].(string)
+		size, _ := file["size"].(float64)
+		a.FileSizeBytes = int64(size)
+		m.Attachments = append(m.Attachments, a)
+	}
+
+	reactions, _ := msg["reactions"].([]any)
+	for _, r := range reactions {
+		reaction, _ := r.(map[string]any)
+		if reaction == nil {
+			continue
+		}
+		emoji, _ := reaction["name"].(string)
+		count, _ := reaction["count"].(float64)
+		m.Reactions = append(m.Reactions, TranscriptReaction{
+			Emoji: TranscriptEmoji{Name: emoji, Code: emoji},
+			Count: int(count),
+		})
+	}
+	return m, nil
+}
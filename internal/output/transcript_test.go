@@ -0,0 +1,129 @@
+package output_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+)
+
+func TestFormatTranscript(t *testing.T) {
+	users := &testUserResolver{users: map[string]string{"U1": "alice", "U2": "bob"}}
+	messages := []map[string]any{
+		{
+			"user": "U1", "text": "hi <@U2>", "ts": "1679058753.000100",
+			"edited":    map[string]any{"user": "U1", "ts": "1679058800.000000"},
+			"reactions": []any{map[string]any{"name": "tada", "count": float64(2)}},
+			"files":     []any{map[string]any{"id": "F1", "name": "log.txt", "url_private": "https://files/log.txt", "size": float64(42)}},
+		},
+		{"bot_id": "B1", "text": "deployed", "ts": "1679058900.000200", "thread_ts": "1679058753.000100"},
+	}
+
+	tr, err := output.FormatTranscript(
+		output.TranscriptGuild{ID: "myteam", Name: "myteam"},
+		output.TranscriptChannel{ID: "C1", Name: "general"},
+		messages, users, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.MessageCount != 2 || tr.Channel.Type != "GuildTextChat" {
+		t.Fatalf("got count %d, type %q", tr.MessageCount, tr.Channel.Type)
+	}
+	first := tr.Messages[0]
+	if first.Author.Name != "alice" || first.Content != "hi `@bob`" {
+		t.Errorf("got author %q, content %q", first.Author.Name, first.Content)
+	}
+	if first.TimestampEdited == nil || len(first.Reactions) != 1 || first.Reactions[0].Count != 2 {
+		t.Errorf("got edited %v, reactions %+v", first.TimestampEdited, first.Reactions)
+	}
+	if len(first.Attachments) != 1 || first.Attachments[0].FileSizeBytes != 42 {
+		t.Errorf("got attachments %+v", first.Attachments)
+	}
+	if len(first.Mentions) != 1 || first.Mentions[0].ID != "U2" {
+		t.Errorf("got mentions %+v", first.Mentions)
+	}
+
+	second := tr.Messages[1]
+	if second.Type != "Reply" || !second.Author.IsBot || second.Author.ID != "B1" {
+		t.Errorf("got type %q, author %+v", second.Type, second.Author)
+	}
+
+	// Empty collections are encoded as [], not omitted or null.
+	data, err := json.Marshal(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"attachments":[]`) || !strings.Contains(string(data), `"timestampEdited":null`) {
+		t.Errorf("got %s", data)
+	}
+}
+
+// avatarUserResolver is a testUserResolver that knows avatars.
+type avatarUserResolver struct {
+	testUserResolver
+	avatars map[string]string
+}
+
+func (r *avatarUserResolver) AvatarForID(id string) string {
+	return r.avatars[id]
+}
+
+func TestFormatTranscript_Avatars(t *testing.T) {
+	users := &avatarUserResolver{
+		testUserResolver: testUserResolver{users: map[string]string{"U1": "alice", "U2": "bob"}},
+		avatars:          map[string]string{"U1": "https://a/u1.png", "U2": "https://a/u2.png", "B1": "https://a/b1.png"},
+	}
+	messages := []map[string]any{
+		{"user": "U1", "text": "hi <@U2>", "ts": "1679058753.000100"},
+		{"user": "U2", "text": "hey", "ts": "1679058800.000100", "user_profile": map[string]any{"image_72": "https://embedded/u2.png"}},
+		{"bot_id": "B1", "text": "deployed", "ts": "1679058900.000200"},
+	}
+
+	tr, err := output.FormatTranscript(output.TranscriptGuild{}, output.TranscriptChannel{}, messages, users, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{tr.Messages[0].Author.AvatarURL, tr.Messages[0].Mentions[0].AvatarURL, tr.Messages[1].Author.AvatarURL, tr.Messages[2].Author.AvatarURL}
+	want := []string{"https://a/u1.png", "https://a/u2.png", "https://embedded/u2.png", "https://a/b1.png"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("avatars = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// guestUserResolver is a testUserResolver that knows which users are guests.
+type guestUserResolver struct {
+	testUserResolver
+	guests map[string]string
+}
+
+func (r *guestUserResolver) GuestKindForID(id string) string {
+	return r.guests[id]
+}
+
+func TestFormatTranscript_GuestRoles(t *testing.T) {
+	users := &guestUserResolver{
+		testUserResolver: testUserResolver{users: map[string]string{"U1": "alice", "U2": "vendor"}},
+		guests:           map[string]string{"U2": "single_channel"},
+	}
+	messages := []map[string]any{
+		{"user": "U2", "text": "hi <@U1>", "ts": "1679058753.000100"},
+	}
+
+	tr, err := output.FormatTranscript(output.TranscriptGuild{}, output.TranscriptChannel{}, messages, users, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	author, mention := tr.Messages[0].Author, tr.Messages[0].Mentions[0]
+	if len(author.Roles) != 1 || author.Roles[0] != (output.TranscriptRole{ID: "single_channel", Name: "Single-channel guest"}) {
+		t.Errorf("author roles = %+v, want a single-channel guest role", author.Roles)
+	}
+	if mention.Roles == nil || len(mention.Roles) != 0 {
+		t.Errorf("mention roles = %#v, want empty", mention.Roles)
+	}
+}
@@ -0,0 +1,123 @@
+// Package picker implements a minimal line-based fuzzy picker for interactive terminals.
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxShown bounds how many candidates are listed at once.
+const maxShown = 10
+
+// ErrCanceled is returned when input ends before a choice is made.
+var ErrCanceled = errors.New("no selection made")
+
+// Item is a choice: Label is shown and matched against, Value is returned.
+type Item struct {
+	Label string
+	Value string
+}
+
+// Pick prompts on out for a filter, lists the best matches, and reads either a number
+// choosing one of them or a new filter from in. An empty line picks the top match.
+func Pick(in io.Reader, out io.Writer, prompt string, items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, errors.New("nothing to pick from")
+	}
+	scanner := bufio.NewScanner(in)
+
+	fmt.Fprintf(out, "%s (type to filter): ", prompt)
+	matches := Rank("", items)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= min(len(matches), maxShown) {
+			return matches[n-1], nil
+		}
+		if line == "" && len(matches) > 0 {
+			return matches[0], nil
+		}
+
+		matches = Rank(line, items)
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(out, "No matches for %q. Filter: ", line)
+			continue
+		case 1:
+			return matches[0], nil
+		}
+		for i, m := range matches[:min(len(matches), maxShown)] {
+			fmt.Fprintf(out, "  %d) %s\n", i+1, m.Label)
+		}
+		if len(matches) > maxShown {
+			fmt.Fprintf(out, "  … %d more\n", len(matches)-maxShown)
+		}
+		fmt.Fprintf(out, "Choose 1-%d (Enter for 1), or refine the filter: ", min(len(matches), maxShown))
+	}
+	if err := scanner.Err(); err != nil {
+		return Item{}, fmt.Errorf("read selection: %w", err)
+	}
+	return Item{}, ErrCanceled
+}
+
+// Rank returns the items whose labels fuzzily match query, best first: exact matches,
+// then prefix, substring, and finally in-order subsequence matches. Matching is case-insensitive.
+func Rank(query string, items []Item) []Item {
+	query = strings.ToLower(query)
+	type scored struct {
+		item Item
+		tier int
+	}
+	var matches []scored
+	for _, it := range items {
+		if tier, ok := matchTier(query, strings.ToLower(it.Label)); ok {
+			matches = append(matches, scored{it, tier})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if len(a.item.Label) != len(b.item.Label) {
+			return len(a.item.Label) < len(b.item.Label)
+		}
+		return a.item.Label < b.item.Label
+	})
+
+	ranked := make([]Item, len(matches))
+	for i, m := range matches {
+		ranked[i] = m.item
+	}
+	return ranked
+}
+
+func matchTier(query, label string) (int, bool) {
+	switch {
+	case label == query:
+		return 0, true
+	case strings.HasPrefix(label, query):
+		return 1, true
+	case strings.Contains(label, query):
+		return 2, true
+	case isSubsequence(query, label):
+		return 3, true
+	}
+	return 0, false
+}
+
+// isSubsequence reports whether the runes of query appear in order within s.
+func isSubsequence(query, s string) bool {
+	q := []rune(query)
+	i := 0
+	for _, r := range s {
+		if i < len(q) && r == q[i] {
+			i++
+		}
+	}
+	return i == len(q)
+}
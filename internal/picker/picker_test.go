@@ -0,0 +1,64 @@
+package picker_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/picker"
+)
+
+var channels = []picker.Item{
+	{Label: "#team-backend-platform-eng", Value: "C1"},
+	{Label: "#general", Value: "C2"},
+	{Label: "#eng-general", Value: "C3"},
+	{Label: "#general-dev", Value: "C4"},
+}
+
+func TestRank(t *testing.T) {
+	got := picker.Rank("#gen", channels)
+	want := []string{"#general", "#general-dev", "#eng-general"}
+	if len(got) != len(want) {
+		t.Fatalf("Rank(#gen) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Label != want[i] {
+			t.Errorf("Rank(#gen)[%d] = %s, want %s", i, got[i].Label, want[i])
+		}
+	}
+
+	// Subsequence matches rank after substring matches.
+	got = picker.Rank("bpe", channels)
+	if len(got) != 1 || got[0].Value != "C1" {
+		t.Errorf("Rank(bpe) = %v, want only #team-backend-platform-eng", got)
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unique match", "platform\n", "C1"},
+		{"choose by number", "general\n2\n", "C3"},
+		{"enter takes top match", "general\n\n", "C2"},
+		{"refine after no match", "xyz\ndev\n", "C4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := picker.Pick(strings.NewReader(tt.input), &bytes.Buffer{}, "Channel", channels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Value != tt.want {
+				t.Errorf("picked %s, want %s", got.Value, tt.want)
+			}
+		})
+	}
+
+	if _, err := picker.Pick(strings.NewReader("general\n"), &bytes.Buffer{}, "Channel", channels); !errors.Is(err, picker.ErrCanceled) {
+		t.Errorf("got error %v at end of input, want ErrCanceled", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Package report computes summary reports over channel history.
+package report
+
+import (
+	"iter"
+	"sort"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// ActivityOptions controls what an activity report counts.
+type ActivityOptions struct {
+	ExcludeBots bool           // skip messages posted by bots and integrations
+	Location    *time.Location // time zone that days are counted in (nil = UTC)
+}
+
+// DayCount is the number of messages posted on one day.
+type DayCount struct {
+	Date     string `json:"date"` // YYYY-MM-DD
+	Messages int    `json:"messages"`
+}
+
+// UserCount is the number of messages one user posted.
+type UserCount struct {
+	UserID   string `json:"user_id"`
+	User     string `json:"user,omitempty"`
+	Messages int    `json:"messages"`
+}
+
+// DayUserCount is the number of messages one user posted on one day.
+type DayUserCount struct {
+	Date     string
+	UserID   string
+	Messages int
+}
+
+// Activity summarizes message volume by day and by user.
+type Activity struct {
+	Total   int            `json:"total"`
+	Days    []DayCount     `json:"days"`  // oldest first
+	Users   []UserCount    `json:"users"` // most active first
+	DayUser []DayUserCount `json:"-"`     // by date, then most active
+}
+
+// ComputeActivity counts messages from seq, which may be in any order.
+func ComputeActivity(seq iter.Seq2[map[string]any, error], opts ActivityOptions) (*Activity, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	byDay := make(map[string]int)
+	byUser := make(map[string]int)
+	byDayUser := make(map[[2]string]int)
+	a := &Activity{}
+	for msg, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		if opts.ExcludeBots && IsBot(msg) {
+			continue
+		}
+		ts, _ := msg["ts"].(string)
+		t, err := islack.ParseTimestamp(ts)
+		if err != nil {
+			continue
+		}
+		day := t.In(loc).Format(time.DateOnly)
+		user := author(msg)
+
+		a.Total++
+		byDay[day]++
+		byUser[user]++
+		byDayUser[[2]string{day, user}]++
+	}
+
+	for day, n := range byDay {
+		a.Days = append(a.Days, DayCount{Date: day, Messages: n})
+	}
+	sort.Slice(a.Days, func(i, j int) bool { return a.Days[i].Date < a.Days[j].Date })
+
+	for user, n := range byUser {
+		a.Users = append(a.Users, UserCount{UserID: user, Messages: n})
+	}
+	sort.Slice(a.Users, func(i, j int) bool {
+		if a.Users[i].Messages != a.Users[j].Messages {
+			return a.Users[i].Messages > a.Users[j].Messages
+		}
+		return a.Users[i].UserID < a.Users[j].UserID
+	})
+
+	for k, n := range byDayUser {
+		a.DayUser = append(a.DayUser, DayUserCount{Date: k[0], UserID: k[1], Messages: n})
+	}
+	sort.Slice(a.DayUser, func(i, j int) bool {
+		x, y := a.DayUser[i], a.DayUser[j]
+		if x.Date != y.Date {
+			return x.Date < y.Date
+		}
+		if x.Messages != y.Messages {
+			return x.Messages > y.Messages
+		}
+		return x.UserID < y.UserID
+	})
+	return a, nil
+}
+
+// IsBot reports whether a message was posted by a bot or integration.
+func IsBot(msg map[string]any) bool {
+	if botID, _ := msg["bot_id"].(string); botID != "" {
+		return true
+	}
+	subtype, _ := msg["subtype"].(string)
+	return subtype == "bot_message"
+}
+
+// author returns the ID a message is attributed to: the user, else the bot.
+func author(msg map[string]any) string {
+	if user, _ := msg["user"].(string); user != "" {
+		return user
+	}
+	if botID, _ := msg["bot_id"].(string); botID != "" {
+		return botID
+	}
+	return "unknown"
+}
@@ -0,0 +1,45 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/report"
+)
+
+func messages(msgs ...map[string]any) func(func(map[string]any, error) bool) {
+	return func(yield func(map[string]any, error) bool) {
+		for _, m := range msgs {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestComputeActivity(t *testing.T) {
+	seq := messages(
+		map[string]any{"ts": "1772359200.000100", "user": "U1"}, // 2026-03-01 10:00 UTC
+		map[string]any{"ts": "1772359260.000100", "user": "U2"},
+		map[string]any{"ts": "1772445600.000100", "user": "U1"}, // 2026-03-02 10:00 UTC
+		map[string]any{"ts": "1772445660.000100", "bot_id": "B1", "subtype": "bot_message"},
+	)
+
+	a, err := report.ComputeActivity(seq, report.ActivityOptions{ExcludeBots: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Total != 3 {
+		t.Errorf("got total %d, want 3", a.Total)
+	}
+	wantDays := []report.DayCount{{Date: "2026-03-01", Messages: 2}, {Date: "2026-03-02", Messages: 1}}
+	if len(a.Days) != 2 || a.Days[0] != wantDays[0] || a.Days[1] != wantDays[1] {
+		t.Errorf("got days %+v, want %+v", a.Days, wantDays)
+	}
+	if len(a.Users) != 2 || a.Users[0].UserID != "U1" || a.Users[0].Messages != 2 {
+		t.Errorf("got users %+v, want U1 first with 2", a.Users)
+	}
+	if len(a.DayUser) != 3 {
+		t.Errorf("got %d day/user rows, want 3", len(a.DayUser))
+	}
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"iter"
+	"slices"
+)
+
+// ChannelStatsOptions controls what a channel stats report counts.
+type ChannelStatsOptions struct {
+	ActivityOptions
+	Top int // number of top posters and busiest days to keep (0 = all)
+}
+
+// ChannelStats summarizes a channel's history over a period.
+type ChannelStats struct {
+	Messages        int         `json:"messages"`     // top-level messages
+	Threads         int         `json:"threads"`      // messages with replies
+	Replies         int         `json:"replies"`      // thread replies, per reply_count
+	Participants    int         `json:"participants"` // distinct authors of messages and replies
+	AvgThreadLength float64     `json:"avg_thread_length"`
+	TopPosters      []UserCount `json:"top_posters"`  // most active first
+	BusiestDays     []DayCount  `json:"busiest_days"` // most active first
+}
+
+// ComputeChannelStats summarizes the top-level messages in seq, which may be in any order.
+// Thread sizes and participants come from the reply_count and reply_users fields of
+// thread parents, so replies need not be fetched.
+func ComputeChannelStats(seq iter.Seq2[map[string]any, error], opts ChannelStatsOptions) (*ChannelStats, error) {
+	s := &ChannelStats{}
+	participants := make(map[string]bool)
+	counted := func(yield func(map[string]any, error) bool) {
+		for msg, err := range seq {
+			if err == nil && !(opts.ExcludeBots && IsBot(msg)) {
+				participants[author(msg)] = true
+				if replies, _ := msg["reply_count"].(float64); replies > 0 {
+					s.Threads++
+					s.Replies += int(replies)
+				}
+				replyUsers, _ := msg["reply_users"].([]any)
+				for _, u := range replyUsers {
+					if id, _ := u.(string); id != "" {
+						participants[id] = true
+					}
+				}
+			}
+			if !yield(msg, err) {
+				return
+			}
+		}
+	}
+
+	a, err := ComputeActivity(counted, opts.ActivityOptions)
+	if err != nil {
+		return nil, err
+	}
+	s.Messages = a.Total
+	s.Participants = len(participants)
+	if s.Threads > 0 {
+		s.AvgThreadLength = float64(s.Replies) / float64(s.Threads)
+	}
+
+	s.TopPosters = a.Users
+	s.BusiestDays = append([]DayCount(nil), a.Days...)
+	sortByMessages(s.BusiestDays)
+	if opts.Top > 0 {
+		s.TopPosters = s.TopPosters[:min(opts.Top, len(s.TopPosters))]
+		s.BusiestDays = s.BusiestDays[:min(opts.Top, len(s.BusiestDays))]
+	}
+	return s, nil
+}
+
+// sortByMessages orders days busiest first, breaking ties by date.
+func sortByMessages(days []DayCount) {
+	slices.SortStableFunc(days, func(a, b DayCount) int {
+		return b.Messages - a.Messages
+	})
+}
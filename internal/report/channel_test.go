@@ -0,0 +1,38 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/report"
+)
+
+func TestComputeChannelStats(t *testing.T) {
+	seq := messages(
+		map[string]any{"ts": "1772359200.000100", "user": "U1", "reply_count": float64(3), "reply_users": []any{"U2", "U3"}}, // 2026-03-01
+		map[string]any{"ts": "1772359260.000100", "user": "U2"},
+		map[string]any{"ts": "1772445600.000100", "user": "U1", "reply_count": float64(1), "reply_users": []any{"U1"}}, // 2026-03-02
+		map[string]any{"ts": "1772532000.000100", "user": "U1"},                                                        // 2026-03-03
+		map[string]any{"ts": "1772532060.000100", "user": "U4"},
+		map[string]any{"ts": "1772532120.000100", "user": "U2"},
+	)
+
+	s, err := report.ComputeChannelStats(seq, report.ChannelStatsOptions{Top: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Messages != 6 || s.Threads != 2 || s.Replies != 4 || s.Participants != 4 {
+		t.Errorf("got messages=%d threads=%d replies=%d participants=%d, want 6 2 4 4",
+			s.Messages, s.Threads, s.Replies, s.Participants)
+	}
+	if s.AvgThreadLength != 2 {
+		t.Errorf("got average thread length %v, want 2", s.AvgThreadLength)
+	}
+	if len(s.TopPosters) != 2 || s.TopPosters[0].UserID != "U1" || s.TopPosters[1].UserID != "U2" {
+		t.Errorf("got top posters %+v, want U1, U2", s.TopPosters)
+	}
+	wantDays := []report.DayCount{{Date: "2026-03-03", Messages: 3}, {Date: "2026-03-01", Messages: 2}}
+	if len(s.BusiestDays) != 2 || s.BusiestDays[0] != wantDays[0] || s.BusiestDays[1] != wantDays[1] {
+		t.Errorf("got busiest days %+v, want %+v", s.BusiestDays, wantDays)
+	}
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// ReactionsOptions controls what a reactions report keeps.
+type ReactionsOptions struct {
+	Top int // number of emojis and messages to keep (0 = all)
+}
+
+// EmojiCount is how often one emoji was used to react.
+type EmojiCount struct {
+	Name      string `json:"name"`      // skin tone variants count as the base emoji
+	Reactions int    `json:"reactions"` // users who reacted with it, summed over messages
+	Messages  int    `json:"messages"`  // messages it was used on
+}
+
+// ReactedMessage is a message and the reactions it received.
+type ReactedMessage struct {
+	TS        string `json:"ts"`
+	UserID    string `json:"user_id,omitempty"`
+	User      string `json:"user,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Reactions int    `json:"reactions"`
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// Reactions summarizes the reactions on a channel's messages.
+type Reactions struct {
+	Total    int              `json:"total"`    // reactions on all messages
+	Emojis   []EmojiCount     `json:"emojis"`   // most used first
+	Messages []ReactedMessage `json:"messages"` // most reacted to first
+}
+
+// ComputeReactions tallies the reactions on the messages in seq, which may be in any order.
+func ComputeReactions(seq iter.Seq2[map[string]any, error], opts ReactionsOptions) (*Reactions, error) {
+	emojis := make(map[string]*EmojiCount)
+	r := &Reactions{}
+	for msg, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		reactions, _ := msg["reactions"].([]any)
+		total := 0
+		used := make(map[string]bool)
+		for _, v := range reactions {
+			reaction, _ := v.(map[string]any)
+			name, _ := reaction["name"].(string)
+			count, _ := reaction["count"].(float64)
+			if name == "" || count <= 0 {
+				continue
+			}
+			name, _, _ = strings.Cut(name, "::") // e.g., +1::skin-tone-2
+			e := emojis[name]
+			if e == nil {
+				e = &EmojiCount{Name: name}
+				emojis[name] = e
+			}
+			e.Reactions += int(count)
+			if !used[name] {
+				used[name] = true
+				e.Messages++
+			}
+			total += int(count)
+		}
+		if total == 0 {
+			continue
+		}
+		r.Total += total
+		ts, _ := msg["ts"].(string)
+		text, _ := msg["text"].(string)
+		r.Messages = append(r.Messages, ReactedMessage{TS: ts, UserID: author(msg), Text: text, Reactions: total})
+	}
+
+	for _, e := range emojis {
+		r.Emojis = append(r.Emojis, *e)
+	}
+	slices.SortFunc(r.Emojis, func(a, b EmojiCount) int {
+		if a.Reactions != b.Reactions {
+			return b.Reactions - a.Reactions
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(r.Messages, func(a, b ReactedMessage) int {
+		if a.Reactions != b.Reactions {
+			return b.Reactions - a.Reactions
+		}
+		return strings.Compare(a.TS, b.TS)
+	})
+	if opts.Top > 0 {
+		r.Emojis = r.Emojis[:min(opts.Top, len(r.Emojis))]
+		r.Messages = r.Messages[:min(opts.Top, len(r.Messages))]
+	}
+	return r, nil
+}
@@ -0,0 +1,41 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/report"
+)
+
+func reaction(name string, count int) map[string]any {
+	return map[string]any{"name": name, "count": float64(count)}
+}
+
+func TestComputeReactions(t *testing.T) {
+	seq := messages(
+		map[string]any{"ts": "1772359200.000100", "user": "U1", "text": "shipped", "reactions": []any{
+			reaction("tada", 4), reaction("+1", 1), reaction("+1::skin-tone-3", 2),
+		}},
+		map[string]any{"ts": "1772359260.000100", "user": "U2", "text": "no reactions"},
+		map[string]any{"ts": "1772445600.000100", "user": "U3", "text": "thanks all", "reactions": []any{
+			reaction("+1", 3), reaction("heart", 2),
+		}},
+		map[string]any{"ts": "1772532000.000100", "user": "U1", "reactions": []any{reaction("eyes", 1)}},
+	)
+
+	r, err := report.ComputeReactions(seq, report.ReactionsOptions{Top: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Total != 13 {
+		t.Errorf("got total %d, want 13", r.Total)
+	}
+	wantEmojis := []report.EmojiCount{{Name: "+1", Reactions: 6, Messages: 2}, {Name: "tada", Reactions: 4, Messages: 1}}
+	if len(r.Emojis) != 2 || r.Emojis[0] != wantEmojis[0] || r.Emojis[1] != wantEmojis[1] {
+		t.Errorf("got emojis %+v, want %+v", r.Emojis, wantEmojis)
+	}
+	if len(r.Messages) != 2 || r.Messages[0].TS != "1772359200.000100" || r.Messages[0].Reactions != 7 ||
+		r.Messages[1].UserID != "U3" || r.Messages[1].Reactions != 5 {
+		t.Errorf("got messages %+v, want U1's (7) then U3's (5)", r.Messages)
+	}
+}
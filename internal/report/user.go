@@ -0,0 +1,101 @@
+package report
+
+import (
+	"iter"
+	"sort"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// UserActivityOptions controls what a user activity report counts.
+type UserActivityOptions struct {
+	UserID  string    // only this user's messages count
+	Since   time.Time // only messages posted since then count (zero = all)
+	Samples int       // most recent messages kept per channel
+}
+
+// MessageSample is one of a user's recent messages.
+type MessageSample struct {
+	TS        string `json:"ts"`
+	Time      string `json:"time"` // RFC 3339, UTC
+	Text      string `json:"text,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// ChannelActivity is how much a user posted in one conversation.
+type ChannelActivity struct {
+	ChannelID  string          `json:"channel_id"`
+	Channel    string          `json:"channel,omitempty"`
+	Messages   int             `json:"messages"`
+	LastPosted string          `json:"last_posted"` // RFC 3339, UTC
+	Samples    []MessageSample `json:"samples"`     // newest first
+}
+
+// UserActivity summarizes where and how much a user posted.
+type UserActivity struct {
+	Total    int               `json:"total"`
+	Channels []ChannelActivity `json:"channels"` // most active first
+}
+
+// ComputeUserActivity counts a user's messages from seq, which may be in any order and
+// may repeat messages (e.g., search results merged with history scans). Each message's
+// conversation is its "channel" (ID) and "channel_name" fields.
+func ComputeUserActivity(seq iter.Seq2[map[string]any, error], opts UserActivityOptions) (*UserActivity, error) {
+	type posted struct {
+		msg map[string]any
+		at  time.Time
+	}
+
+	seen := make(map[[2]string]bool)
+	byChannel := make(map[string][]posted)
+	names := make(map[string]string)
+	a := &UserActivity{}
+	for msg, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		if user, _ := msg["user"].(string); user != opts.UserID {
+			continue
+		}
+		channel, _ := msg["channel"].(string)
+		ts, _ := msg["ts"].(string)
+		t, err := islack.ParseTimestamp(ts)
+		if err != nil || t.Before(opts.Since) || seen[[2]string{channel, ts}] {
+			continue
+		}
+		seen[[2]string{channel, ts}] = true
+		if name, _ := msg["channel_name"].(string); name != "" {
+			names[channel] = name
+		}
+		a.Total++
+		byChannel[channel] = append(byChannel[channel], posted{msg, t})
+	}
+
+	for channel, msgs := range byChannel {
+		sort.Slice(msgs, func(i, j int) bool { return msgs[i].at.After(msgs[j].at) })
+		c := ChannelActivity{
+			ChannelID:  channel,
+			Channel:    names[channel],
+			Messages:   len(msgs),
+			LastPosted: msgs[0].at.UTC().Format(time.RFC3339),
+			Samples:    []MessageSample{},
+		}
+		for _, p := range msgs[:min(len(msgs), opts.Samples)] {
+			s := MessageSample{Time: p.at.UTC().Format(time.RFC3339)}
+			s.TS, _ = p.msg["ts"].(string)
+			s.Text, _ = p.msg["text"].(string)
+			s.Permalink, _ = p.msg["permalink"].(string)
+			c.Samples = append(c.Samples, s)
+		}
+		a.Channels = append(a.Channels, c)
+	}
+	sort.Slice(a.Channels, func(i, j int) bool {
+		x, y := a.Channels[i], a.Channels[j]
+		if x.Messages != y.Messages {
+			return x.Messages > y.Messages
+		}
+		return x.LastPosted > y.LastPosted
+	})
+	return a, nil
+}
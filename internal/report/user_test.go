@@ -0,0 +1,42 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/report"
+)
+
+func TestComputeUserActivity(t *testing.T) {
+	seq := messages(
+		map[string]any{"ts": "1772359200.000100", "user": "U1", "channel": "C1", "channel_name": "eng", "text": "first"}, // 2026-03-01 10:00 UTC
+		map[string]any{"ts": "1772359260.000100", "user": "U2", "channel": "C1", "text": "not alice"},
+		map[string]any{"ts": "1772445600.000100", "user": "U1", "channel": "C1", "text": "second", "permalink": "https://x/p2"}, // 2026-03-02
+		map[string]any{"ts": "1772445600.000100", "user": "U1", "channel": "C1", "text": "second"},                              // the same, from history
+		map[string]any{"ts": "1772532000.000100", "user": "U1", "channel": "D1", "text": "dm"},                                  // 2026-03-03
+		map[string]any{"ts": "1772272800.000100", "user": "U1", "channel": "C2", "text": "too old"},                             // 2026-02-28
+	)
+
+	a, err := report.ComputeUserActivity(seq, report.UserActivityOptions{
+		UserID:  "U1",
+		Since:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Samples: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Total != 3 || len(a.Channels) != 2 {
+		t.Fatalf("got total %d, channels %+v; want 3 messages in 2 channels", a.Total, a.Channels)
+	}
+
+	eng := a.Channels[0]
+	if eng.ChannelID != "C1" || eng.Channel != "eng" || eng.Messages != 2 || eng.LastPosted != "2026-03-02T10:00:00Z" {
+		t.Errorf("first channel = %+v, want C1 (eng) with 2 messages, last 2026-03-02T10:00:00Z", eng)
+	}
+	if len(eng.Samples) != 1 || eng.Samples[0].Text != "second" || eng.Samples[0].Permalink != "https://x/p2" {
+		t.Errorf("samples = %+v, want only the newest message", eng.Samples)
+	}
+	if dm := a.Channels[1]; dm.ChannelID != "D1" || dm.Messages != 1 {
+		t.Errorf("second channel = %+v, want D1 with 1 message", dm)
+	}
+}
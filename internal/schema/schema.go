@@ -0,0 +1,34 @@
+// Package schema holds the JSON Schemas of slack-reader's JSON output.
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// Names returns the names of the available schemas, sorted.
+func Names() []string {
+	entries, _ := fs.ReadDir(schemas, "schemas")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Get returns the JSON Schema document for name (e.g., "message").
+func Get(name string) ([]byte, error) {
+	data, err := schemas.ReadFile(path.Join("schemas", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q (want one of: %s)", name, strings.Join(Names(), ", "))
+	}
+	return data, nil
+}
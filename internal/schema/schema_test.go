@@ -0,0 +1,35 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/schema"
+)
+
+func TestSchemas(t *testing.T) {
+	names := schema.Names()
+	if !slices.Equal(names, []string{"channel", "message", "user"}) {
+		t.Fatalf("got names %v", names)
+	}
+	for _, name := range names {
+		data, err := schema.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if doc["$schema"] != "https://json-schema.org/draft/2020-12/schema" {
+			t.Errorf("%s: got $schema %v", name, doc["$schema"])
+		}
+	}
+}
+
+func TestGet_Unknown(t *testing.T) {
+	if _, err := schema.Get("reaction"); err == nil {
+		t.Fatal("expected an error for an unknown schema")
+	}
+}
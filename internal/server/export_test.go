@@ -0,0 +1,12 @@
+package server
+
+import (
+	"context"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// NewWithResolver exposes newServer so tests can substitute channel resolution.
+func NewWithResolver(client islack.APIClient, resolve func(context.Context, string) (string, error)) *Server {
+	return newServer(client, resolve, nil)
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// metrics records served requests and renders them, along with the client's
+// Slack API statistics, in the Prometheus text exposition format.
+type metrics struct {
+	stats *islack.Stats
+
+	mu       sync.Mutex
+	requests map[requestKey]int
+	latency  map[string]*histogram // by route
+}
+
+type requestKey struct {
+	route string
+	code  int
+}
+
+type histogram struct {
+	counts []int // per bucket, not cumulative; the last entry is +Inf
+	sum    float64
+	count  int
+}
+
+func newMetrics(stats *islack.Stats) *metrics {
+	return &metrics{
+		stats:    stats,
+		requests: make(map[requestKey]int),
+		latency:  make(map[string]*histogram),
+	}
+}
+
+// instrument wraps next, recording each request's route, status, and latency.
+func (m *metrics) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// The mux records the matched pattern on the request; unmatched paths share one label.
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		m.observe(route, rec.status, time.Since(start))
+	})
+}
+
+func (m *metrics) observe(route string, code int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{route, code}]++
+	h := m.latency[route]
+	if h == nil {
+		h = &histogram{counts: make([]int, len(latencyBuckets)+1)}
+		m.latency[route] = h
+	}
+	secs := elapsed.Seconds()
+	i, _ := slices.BinarySearch(latencyBuckets, secs)
+	h.counts[i]++
+	h.sum += secs
+	h.count++
+}
+
+func (m *metrics) handle(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// write renders all metrics, with series sorted so output is stable between scrapes.
+func (m *metrics) write(w io.Writer) {
+	stats := m.stats.Summary()
+
+	header(w, "slack_reader_api_calls_total", "counter", "Slack API calls made (cache hits excluded), by method.")
+	for _, method := range sortedKeys(stats.Calls) {
+		fmt.Fprintf(w, "slack_reader_api_calls_total{method=%q} %d\n", method, stats.Calls[method])
+	}
+	header(w, "slack_reader_api_call_seconds_total", "counter", "Time spent in Slack API calls, including retries, by method.")
+	for _, method := range sortedKeys(stats.CallSeconds) {
+		fmt.Fprintf(w, "slack_reader_api_call_seconds_total{method=%q} %s\n", method, formatFloat(stats.CallSeconds[method]))
+	}
+	header(w, "slack_reader_api_response_bytes_total", "counter", "Bytes received from the Slack API.")
+	fmt.Fprintf(w, "slack_reader_api_response_bytes_total %d\n", stats.Bytes)
+	header(w, "slack_reader_api_retries_total", "counter", "Slack API requests retried.")
+	fmt.Fprintf(w, "slack_reader_api_retries_total %d\n", stats.Retries)
+	header(w, "slack_reader_api_rate_limited_total", "counter", "Slack API requests rate limited (and retried).")
+	fmt.Fprintf(w, "slack_reader_api_rate_limited_total %d\n", stats.RateLimited)
+	header(w, "slack_reader_cache_hits_total", "counter", "API responses served from the response cache.")
+	fmt.Fprintf(w, "slack_reader_cache_hits_total %d\n", stats.CacheHits)
+	header(w, "slack_reader_cache_misses_total", "counter", "API responses not found in the response cache.")
+	fmt.Fprintf(w, "slack_reader_cache_misses_total %d\n", stats.CacheMisses)
+	header(w, "slack_reader_cache_hit_ratio", "gauge", "Fraction of response cache lookups that hit.")
+	fmt.Fprintf(w, "slack_reader_cache_hit_ratio %s\n", formatFloat(stats.CacheHitRate))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		if c := strings.Compare(a.route, b.route); c != 0 {
+			return c
+		}
+		return a.code - b.code
+	})
+	header(w, "slack_reader_http_requests_total", "counter", "HTTP requests served, by route and status code.")
+	for _, k := range keys {
+		fmt.Fprintf(w, "slack_reader_http_requests_total{route=%q,code=\"%d\"} %d\n", k.route, k.code, m.requests[k])
+	}
+
+	header(w, "slack_reader_http_request_duration_seconds", "histogram", "HTTP request latency, by route.")
+	for _, route := range sortedKeys(m.latency) {
+		h := m.latency[route]
+		cumulative := 0
+		for i, le := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "slack_reader_http_request_duration_seconds_bucket{route=%q,le=%q} %d\n", route, formatFloat(le), cumulative)
+		}
+		fmt.Fprintf(w, "slack_reader_http_request_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", route, h.count)
+		fmt.Fprintf(w, "slack_reader_http_request_duration_seconds_sum{route=%q} %s\n", route, formatFloat(h.sum))
+		fmt.Fprintf(w, "slack_reader_http_request_duration_seconds_count{route=%q} %d\n", route, h.count)
+	}
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
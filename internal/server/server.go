@@ -0,0 +1,236 @@
+// Package server exposes read-only Slack data over a small JSON REST API.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Server serves the REST API, proxying each request through one authenticated client.
+type Server struct {
+	client  islack.APIClient
+	resolve func(ctx context.Context, input string) (string, error)
+	handler http.Handler
+}
+
+// New returns a Server backed by client. Channel names in paths are resolved
+// (and cached) the same way as on the command line. /metrics reports the served
+// requests together with stats, which should be the Stats client records into.
+func New(client *islack.Client, stats *islack.Stats) *Server {
+	return newServer(client, func(ctx context.Context, input string) (string, error) {
+		return islack.ResolveChannelID(ctx, client, input)
+	}, stats)
+}
+
+func newServer(client islack.APIClient, resolve func(context.Context, string) (string, error), stats *islack.Stats) *Server {
+	s := &Server{client: client, resolve: resolve}
+	m := newMetrics(stats)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/channels", s.handleChannels)
+	mux.HandleFunc("GET /v1/channels/{channel}/history", s.handleHistory)
+	mux.HandleFunc("GET /v1/channels/{channel}/messages/{ts}", s.handleMessage)
+	mux.HandleFunc("GET /v1/threads/{channel}/{ts}", s.handleThread)
+	mux.HandleFunc("GET /metrics", m.handle)
+	s.handler = m.instrument(mux)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// handleChannels lists the current user's conversations, or every workspace
+// conversation with ?all=true. ?user= lists another user's conversations,
+// ?types= limits the conversation types, and ?archived=true includes archived ones.
+func (s *Server) handleChannels(w http.ResponseWriter, r *http.Request) {
+	limit, err := limitParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts := islack.ConversationOptions{IncludeArchived: r.URL.Query().Get("archived") == "true"}
+	if types := r.URL.Query().Get("types"); types != "" {
+		if opts.Types, err = islack.ParseConversationTypes(strings.Split(types, ",")); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	var seq iter.Seq2[map[string]any, error]
+	if r.URL.Query().Get("all") == "true" {
+		seq = islack.IterAllConversations(r.Context(), s.client, opts)
+	} else {
+		seq = islack.IterUserConversations(r.Context(), s.client, r.URL.Query().Get("user"), opts)
+	}
+
+	var channels []map[string]any
+	for c, err := range seq {
+		if err != nil {
+			writeSlackError(w, err)
+			return
+		}
+		channels = append(channels, c)
+		if len(channels) >= limit {
+			break
+		}
+	}
+	writeJSON(w, map[string]any{"channels": channels})
+}
+
+// handleHistory lists channel messages, filtered by ?oldest=, ?latest=, ?inclusive=true and ?limit=.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	channelID, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	opts, err := historyOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	messages, err := islack.CollectMessages(islack.IterChannelHistory(r.Context(), s.client, channelID, opts))
+	if err != nil {
+		writeSlackError(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"messages": messages})
+}
+
+// handleMessage fetches a single message, including thread replies.
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	channelID, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := islack.GetMessage(r.Context(), s.client, channelID, r.PathValue("ts"), r.URL.Query().Get("thread_ts"))
+	if err != nil {
+		writeSlackError(w, err)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// handleThread lists a thread's messages, root first.
+func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
+	channelID, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	opts, err := historyOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	messages, err := islack.CollectMessages(islack.IterThread(r.Context(), s.client, channelID, r.PathValue("ts"), opts))
+	if err != nil {
+		writeSlackError(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"messages": messages})
+}
+
+// channel resolves the {channel} path value, writing an error response on failure.
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) (string, bool) {
+	channelID, err := s.resolve(r.Context(), r.PathValue("channel"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return "", false
+	}
+	return channelID, true
+}
+
+// Channel, history and thread requests return defaultLimit items unless ?limit= asks
+// for more, up to maxLimit, so that no request pages through a whole workspace or
+// channel history.
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// limitParam returns ?limit=, defaulting to defaultLimit and capped at maxLimit.
+func limitParam(r *http.Request) (int, error) {
+	limit, err := intParam(r, "limit")
+	if err != nil {
+		return 0, err
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		return 0, fmt.Errorf("invalid limit: %d exceeds the maximum of %d", limit, maxLimit)
+	}
+	return limit, nil
+}
+
+func historyOptions(r *http.Request) (islack.HistoryOptions, error) {
+	limit, err := limitParam(r)
+	if err != nil {
+		return islack.HistoryOptions{}, err
+	}
+	q := r.URL.Query()
+	return islack.HistoryOptions{
+		Oldest:    q.Get("oldest"),
+		Latest:    q.Get("latest"),
+		Inclusive: q.Get("inclusive") == "true",
+		Limit:     limit,
+	}, nil
+}
+
+func intParam(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, v)
+	}
+	return n, nil
+}
+
+// writeSlackError maps a Slack failure to an HTTP status: unknown channels, messages,
+// and other objects are 404s, rate limiting is a 429, and anything else is a bad gateway.
+func writeSlackError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, islack.ErrRateLimited):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, islack.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, context.Canceled):
+		// The client went away; nothing useful can be written.
+		return
+	}
+	writeError(w, status, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	writeBody(w, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	writeBody(w, v)
+}
+
+func writeBody(w http.ResponseWriter, v any) {
+	if err := output.WriteJSON(w, v); err != nil {
+		slog.Info("could not write response", "error", err)
+	}
+}
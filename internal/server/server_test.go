@@ -0,0 +1,198 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/server"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// fakeSlack serves channel history for C0123ABCD and rejects every other channel.
+type fakeSlack struct{}
+
+func (fakeSlack) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	if params["channel"] != "C0123ABCD" {
+		return nil, &islack.APIError{Method: method, Code: "channel_not_found"}
+	}
+	return map[string]any{
+		"ok": true,
+		"messages": []any{
+			map[string]any{"ts": "1770000002.000000", "text": "second"},
+			map[string]any{"ts": "1770000001.000000", "text": "first"},
+		},
+	}, nil
+}
+
+func resolve(_ context.Context, input string) (string, error) {
+	if input == "general" {
+		return "C0123ABCD", nil
+	}
+	return input, nil
+}
+
+func TestServer_History(t *testing.T) {
+	srv := httptest.NewServer(server.NewWithResolver(fakeSlack{}, resolve))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/v1/channels/general/history?limit=10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Messages []struct {
+			TS   string `json:"ts"`
+			Text string `json:"text"`
+		} `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Messages) != 2 || body.Messages[0].Text != "first" {
+		t.Errorf("got messages %+v, want oldest first", body.Messages)
+	}
+}
+
+// endlessSlack serves a channel history that never ends, recording each call's params.
+type endlessSlack struct {
+	calls []map[string]string
+}
+
+func (e *endlessSlack) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	e.calls = append(e.calls, params)
+	if len(e.calls) > 10 {
+		return nil, errors.New("too many pages")
+	}
+	n, _ := strconv.Atoi(params["limit"])
+	messages := make([]any, n)
+	for i := range messages {
+		messages[i] = map[string]any{"ts": fmt.Sprintf("17700%05d.000000", len(e.calls)*1000-i)}
+	}
+	return map[string]any{
+		"ok":                true,
+		"messages":          messages,
+		"response_metadata": map[string]any{"next_cursor": fmt.Sprintf("page%d", len(e.calls)+1)},
+	}, nil
+}
+
+// Without ?limit=, a request returns a bounded number of messages.
+func TestServer_HistoryDefaultLimit(t *testing.T) {
+	api := &endlessSlack{}
+	srv := httptest.NewServer(server.NewWithResolver(api, resolve))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/v1/channels/general/history", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Messages []any `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Messages) != 100 {
+		t.Errorf("got %d messages, want 100", len(body.Messages))
+	}
+	if len(api.calls) != 1 {
+		t.Errorf("made %d API calls, want 1", len(api.calls))
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv := httptest.NewServer(server.NewWithResolver(fakeSlack{}, resolve))
+	defer srv.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/v1/channels/C9999ZZZZ/history", http.StatusNotFound},
+		{http.MethodGet, "/v1/channels/general/history?limit=x", http.StatusBadRequest},
+		{http.MethodGet, "/v1/channels/general/history?limit=1001", http.StatusBadRequest},
+		{http.MethodGet, "/v1/threads/general/1770000001.000000?limit=5000", http.StatusBadRequest},
+		{http.MethodGet, "/v1/channels?limit=1001", http.StatusBadRequest},
+		{http.MethodPost, "/v1/channels/general/history", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.method, tt.path), func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), tt.method, srv.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	srv := httptest.NewServer(server.NewWithResolver(fakeSlack{}, resolve))
+	defer srv.Close()
+
+	get := func(path string) string {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	get("/v1/channels/general/history")
+	get("/v1/channels/C9999ZZZZ/history")
+	body := get("/metrics")
+
+	for _, want := range []string{
+		`slack_reader_http_requests_total{route="GET /v1/channels/{channel}/history",code="200"} 1`,
+		`slack_reader_http_requests_total{route="GET /v1/channels/{channel}/history",code="404"} 1`,
+		`slack_reader_http_request_duration_seconds_count{route="GET /v1/channels/{channel}/history"} 2`,
+		`# TYPE slack_reader_api_rate_limited_total counter`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
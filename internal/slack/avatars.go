@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// avatarHosts are the hosts Slack serves avatar images from.
+var avatarHosts = []string{"slack-edge.com", "gravatar.com"}
+
+// MirrorAvatars downloads avatar images into dir, so exports that show them work
+// offline. It returns the local path (under dir) of each URL downloaded, or already
+// downloaded on an earlier run. Images that fail to download are left out and logged.
+func MirrorAvatars(ctx context.Context, client *Client, urls []string, dir string) (map[string]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create avatar dir: %w", err)
+	}
+
+	// Downloads write into their own slots; the map is only built after Wait.
+	paths := make([]string, len(urls))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for i, link := range urls {
+		g.Go(func() error {
+			p := filepath.Join(dir, avatarFileName(link))
+			if _, err := os.Stat(p); err == nil {
+				paths[i] = p
+				return nil
+			}
+			data, err := client.downloadAvatar(ctx, link)
+			if err != nil {
+				slog.Info("could not download avatar", "url", link, "error", err)
+				return nil
+			}
+			if err := os.WriteFile(p, data, 0o644); err != nil {
+				return fmt.Errorf("write avatar: %w", err)
+			}
+			paths[i] = p
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	mirrored := make(map[string]string)
+	for i, p := range paths {
+		if p != "" {
+			mirrored[urls[i]] = p
+		}
+	}
+	return mirrored, nil
+}
+
+// avatarFileName names an avatar's local copy by a hash of its URL, keeping the extension.
+func avatarFileName(link string) string {
+	sum := sha256.Sum256([]byte(link))
+	name := hex.EncodeToString(sum[:8])
+	if u, err := url.Parse(link); err == nil {
+		if ext := path.Ext(u.Path); ext != "" && len(ext) <= 5 {
+			name += strings.ToLower(ext)
+		}
+	}
+	return name
+}
+
+// downloadAvatar fetches a public avatar image. No credentials are sent, and only
+// Slack's avatar hosts are fetched.
+func (c *Client) downloadAvatar(ctx context.Context, link string) ([]byte, error) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "https" || !isAvatarHost(u.Hostname()) {
+		return nil, fmt.Errorf("download avatar: not a Slack avatar URL: %q", link)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download avatar: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download avatar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download avatar: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("download avatar: %w", err)
+	}
+	c.stats.recordCall("avatars.download", len(body), time.Since(start))
+	return body, nil
+}
+
+func isAvatarHost(host string) bool {
+	for _, h := range avatarHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package slack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestAvatarFileName(t *testing.T) {
+	a := slack.AvatarFileName("https://avatars.slack-edge.com/2024-01-01/123_abc_72.JPG")
+	b := slack.AvatarFileName("https://avatars.slack-edge.com/2024-01-01/456_def_72.jpg")
+	if !strings.HasSuffix(a, ".jpg") || !strings.HasSuffix(b, ".jpg") || a == b {
+		t.Errorf("AvatarFileName = %q, %q; want distinct .jpg names", a, b)
+	}
+	if got := slack.AvatarFileName("https://secure.gravatar.com/avatar/abc?s=72"); strings.Contains(got, ".") {
+		t.Errorf("AvatarFileName(no extension) = %q, want no extension", got)
+	}
+}
+
+func TestUserProvider_Avatars(t *testing.T) {
+	users := slack.NewUserProvider(&zonesAPI{})
+	users.CaptureAvatars()
+	users.Seed([]map[string]any{
+		{"user": "U1", "user_profile": map[string]any{"name": "alice", "image_72": "https://avatars.slack-edge.com/u1_72.png"}},
+		{"bot_id": "B1", "bot_profile": map[string]any{"name": "GitHub", "icons": map[string]any{"image_72": "https://avatars.slack-edge.com/b1_72.png"}}},
+	})
+	if got := users.AvatarForID("U1"); got != "https://avatars.slack-edge.com/u1_72.png" {
+		t.Errorf("AvatarForID(U1) = %q", got)
+	}
+	if got := users.AvatarForID("B1"); got != "https://avatars.slack-edge.com/b1_72.png" {
+		t.Errorf("AvatarForID(B1) = %q", got)
+	}
+	if got := users.AvatarForID("U2"); got != "" {
+		t.Errorf("AvatarForID(U2) = %q, want none", got)
+	}
+}
@@ -0,0 +1,96 @@
+package slack
+
+import "strings"
+
+// IsSharedMessage reports whether a message attachment is another Slack message shared
+// into this one (forwarded, or unfurled from a message link).
+func IsSharedMessage(att map[string]any) bool {
+	if share, _ := att["is_share"].(bool); share {
+		return true
+	}
+	unfurl, _ := att["is_msg_unfurl"].(bool)
+	return unfurl
+}
+
+// SharedMessageText returns the text of a shared message attachment as Slack mrkdwn: its
+// text, or else the text of the rich text blocks it carries in message_blocks.
+func SharedMessageText(att map[string]any) string {
+	if text, _ := att["text"].(string); text != "" {
+		return text
+	}
+	var parts []string
+	messageBlocks, _ := att["message_blocks"].([]any)
+	for _, mb := range messageBlocks {
+		mb, _ := mb.(map[string]any)
+		msg, _ := mb["message"].(map[string]any)
+		blocks, _ := msg["blocks"].([]any)
+		if text := BlocksText(blocks); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// BlocksText flattens rich text blocks to Slack mrkdwn, with mentions, channels, and
+// links in their <...> forms. Other block types are skipped.
+func BlocksText(blocks []any) string {
+	var b strings.Builder
+	for _, bl := range blocks {
+		block, _ := bl.(map[string]any)
+		if block["type"] != "rich_text" {
+			continue
+		}
+		elements, _ := block["elements"].([]any)
+		for _, el := range elements {
+			writeRichText(&b, el)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// writeRichText writes a rich text section, list, quote, or preformatted block.
+func writeRichText(b *strings.Builder, v any) {
+	el, _ := v.(map[string]any)
+	children, _ := el["elements"].([]any)
+	switch el["type"] {
+	case "rich_text_list":
+		for _, item := range children {
+			b.WriteString("• ")
+			writeRichText(b, item)
+			b.WriteString("\n")
+		}
+		return
+	case "rich_text_quote":
+		b.WriteString("> ")
+	case "rich_text_preformatted":
+		b.WriteString("```")
+		defer b.WriteString("```\n")
+	}
+	for _, c := range children {
+		c, _ := c.(map[string]any)
+		switch c["type"] {
+		case "text":
+			text, _ := c["text"].(string)
+			b.WriteString(text)
+		case "link":
+			url, _ := c["url"].(string)
+			if text, _ := c["text"].(string); text != "" {
+				b.WriteString("<" + url + "|" + text + ">")
+			} else {
+				b.WriteString("<" + url + ">")
+			}
+		case "user":
+			id, _ := c["user_id"].(string)
+			b.WriteString("<@" + id + ">")
+		case "channel":
+			id, _ := c["channel_id"].(string)
+			b.WriteString("<#" + id + ">")
+		case "emoji":
+			name, _ := c["name"].(string)
+			b.WriteString(":" + name + ":")
+		}
+	}
+	if el["type"] == "rich_text_quote" {
+		b.WriteString("\n")
+	}
+}
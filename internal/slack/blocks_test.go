@@ -0,0 +1,49 @@
+package slack_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestSharedMessageText(t *testing.T) {
+	att := map[string]any{
+		"is_share": true,
+		"message_blocks": []any{map[string]any{
+			"message": map[string]any{"blocks": []any{
+				map[string]any{"type": "rich_text", "elements": []any{
+					map[string]any{"type": "rich_text_section", "elements": []any{
+						map[string]any{"type": "text", "text": "cc "},
+						map[string]any{"type": "user", "user_id": "U123"},
+						map[string]any{"type": "text", "text": " see "},
+						map[string]any{"type": "link", "url": "https://example.com", "text": "docs"},
+						map[string]any{"type": "text", "text": " "},
+						map[string]any{"type": "emoji", "name": "eyes"},
+						map[string]any{"type": "text", "text": "\n"},
+					}},
+					map[string]any{"type": "rich_text_list", "elements": []any{
+						map[string]any{"type": "rich_text_section", "elements": []any{map[string]any{"type": "text", "text": "one"}}},
+						map[string]any{"type": "rich_text_section", "elements": []any{map[string]any{"type": "text", "text": "two"}}},
+					}},
+				}},
+				map[string]any{"type": "divider"},
+			}},
+		}},
+	}
+
+	if !slack.IsSharedMessage(att) {
+		t.Error("IsSharedMessage = false, want true")
+	}
+	want := "cc <@U123> see <https://example.com|docs> :eyes:\n• one\n• two"
+	if got := slack.SharedMessageText(att); got != want {
+		t.Errorf("SharedMessageText = %q, want %q", got, want)
+	}
+
+	// Text, when present, is used as is.
+	if got := slack.SharedMessageText(map[string]any{"text": "hello", "message_blocks": att["message_blocks"]}); got != "hello" {
+		t.Errorf("SharedMessageText = %q, want %q", got, "hello")
+	}
+	if slack.IsSharedMessage(map[string]any{"title": "a link preview"}) {
+		t.Error("IsSharedMessage = true for a link preview, want false")
+	}
+}
@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoCanvas is returned when a channel has no canvas.
+var ErrNoCanvas = errors.New("channel has no canvas")
+
+// FileClient is an APIClient that can also download private files.
+type FileClient interface {
+	APIClient
+	Download(ctx context.Context, link string) ([]byte, error)
+}
+
+// Canvas is a channel canvas and its content.
+type Canvas struct {
+	FileID  string `json:"file_id"`
+	Title   string `json:"title,omitempty"`
+	Updated int64  `json:"updated,omitempty"` // Unix seconds
+	URL     string `json:"url,omitempty"`
+	HTML    string `json:"html"`
+}
+
+// ChannelCanvasID returns the file ID of a channel's canvas, from conversations.info
+// properties: the channel canvas, else the first canvas tab.
+func ChannelCanvasID(channel map[string]any) string {
+	props, _ := channel["properties"].(map[string]any)
+	canvas, _ := props["canvas"].(map[string]any)
+	if id, _ := canvas["file_id"].(string); id != "" {
+		return id
+	}
+	tabs, _ := props["tabs"].([]any)
+	for _, t := range tabs {
+		tab, _ := t.(map[string]any)
+		if kind, _ := tab["type"].(string); kind != "canvas" {
+			continue
+		}
+		data, _ := tab["data"].(map[string]any)
+		if id, _ := data["file_id"].(string); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// GetChannelCanvas fetches a channel's canvas: it is located with conversations.info,
+// described by files.info, and its HTML content downloaded from the file's private URL.
+func GetChannelCanvas(ctx context.Context, client FileClient, channelID string) (*Canvas, error) {
+	channel, err := GetChannelInfo(ctx, client, channelID)
+	if err != nil {
+		return nil, err
+	}
+	fileID := ChannelCanvasID(channel)
+	if fileID == "" {
+		return nil, ErrNoCanvas
+	}
+
+	resp, err := client.API(ctx, "files.info", map[string]string{"file": fileID})
+	if err != nil {
+		return nil, fmt.Errorf("files.info: %w", err)
+	}
+	file, _ := resp["file"].(map[string]any)
+	if file == nil {
+		return nil, errors.New("files.info: no file in response")
+	}
+
+	c := &Canvas{FileID: fileID}
+	c.Title, _ = file["title"].(string)
+	c.URL, _ = file["permalink"].(string)
+	updated, _ := file["updated"].(float64)
+	c.Updated = int64(updated)
+
+	link, _ := file["url_private_download"].(string)
+	if link == "" {
+		link, _ = file["url_private"].(string)
+	}
+	if link == "" {
+		return nil, fmt.Errorf("files.info: canvas %s has no download URL", fileID)
+	}
+	body, err := client.Download(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	c.HTML = string(body)
+	return c, nil
+}
@@ -0,0 +1,59 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// canvasAPI serves a channel whose canvas is a tab, and the canvas file.
+type canvasAPI struct {
+	downloaded string
+}
+
+func (m *canvasAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "conversations.info":
+		if params["channel"] != "C1" {
+			return map[string]any{"ok": true, "channel": map[string]any{"id": params["channel"]}}, nil
+		}
+		return map[string]any{"ok": true, "channel": map[string]any{
+			"id": "C1",
+			"properties": map[string]any{"tabs": []any{
+				map[string]any{"type": "files"},
+				map[string]any{"type": "canvas", "data": map[string]any{"file_id": "F1"}},
+			}},
+		}}, nil
+	case "files.info":
+		return map[string]any{"ok": true, "file": map[string]any{
+			"id": params["file"], "title": "Runbook", "updated": float64(1770165109),
+			"url_private": "https://files.slack.com/files-pri/T1-F1/canvas",
+		}}, nil
+	}
+	return nil, errors.New("unexpected method " + method)
+}
+
+func (m *canvasAPI) Download(_ context.Context, link string) ([]byte, error) {
+	m.downloaded = link
+	return []byte("<h1>Runbook</h1>"), nil
+}
+
+func TestGetChannelCanvas(t *testing.T) {
+	api := &canvasAPI{}
+	c, err := slack.GetChannelCanvas(t.Context(), api, "C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.FileID != "F1" || c.Title != "Runbook" || c.Updated != 1770165109 || c.HTML != "<h1>Runbook</h1>" {
+		t.Errorf("got %+v", c)
+	}
+	if api.downloaded != "https://files.slack.com/files-pri/T1-F1/canvas" {
+		t.Errorf("downloaded %q", api.downloaded)
+	}
+
+	if _, err := slack.GetChannelCanvas(t.Context(), api, "C2"); !errors.Is(err, slack.ErrNoCanvas) {
+		t.Errorf("got error %v, want ErrNoCanvas", err)
+	}
+}
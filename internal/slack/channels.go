@@ -4,17 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
+	"maps"
+	"net/url"
+	"path"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 var channelIDPattern = regexp.MustCompile(`^[CDG][A-Z0-9]{8,}$`)
 
-// NormalizeChannelInput parses a channel reference (e.g., "#general", "general", "C0123ABC")
-// and returns the cleaned value and whether it's an ID.
+// NormalizeChannelInput parses a channel reference (e.g., "#general", "general", "C0123ABC",
+// or a link such as "https://myteam.slack.com/archives/C0123ABC") and returns the cleaned
+// value and whether it's an ID.
 func NormalizeChannelInput(input string) (string, bool) {
 	trimmed := strings.TrimSpace(input)
+	if channelID, _, _, ok := ParseArchiveURL(trimmed); ok {
+		return channelID, true
+	}
 	if strings.HasPrefix(trimmed, "#") {
 		return trimmed[1:], false
 	}
@@ -24,8 +34,48 @@ func NormalizeChannelInput(input string) (string, bool) {
 	return trimmed, false
 }
 
+// ParseArchiveURL parses a channel or message link, as Slack's "Copy link" produces
+// (https://myteam.slack.com/archives/C0123ABC/p1770165200000100?thread_ts=1770165109.628379).
+// ts is the linked message's timestamp and threadTS its thread's, when present.
+func ParseArchiveURL(link string) (channelID, ts, threadTS string, ok bool) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "archives" || !channelIDPattern.MatchString(parts[1]) {
+		return "", "", "", false
+	}
+	if len(parts) == 3 {
+		digits, found := strings.CutPrefix(parts[2], "p")
+		if !found || digits == "" {
+			return "", "", "", false
+		}
+		ts = NormalizeTimestamp(digits)
+	}
+	return parts[1], ts, u.Query().Get("thread_ts"), true
+}
+
+// ParseWorkspaceURL returns the workspace (team domain) of a Slack link: "myteam" for
+// https://myteam.slack.com/archives/C0123ABC. Links on other hosts, and to
+// app.slack.com, have none.
+func ParseWorkspaceURL(link string) (workspace string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", false
+	}
+	domain, found := strings.CutSuffix(strings.ToLower(u.Hostname()), ".slack.com")
+	if !found || domain == "" || domain == "app" || strings.Contains(domain, ".") {
+		return "", false
+	}
+	return domain, true
+}
+
 // ResolveChannelID resolves a channel name or ID to a channel ID.
-// Uses search.messages for fast resolution, falling back to conversations.list pagination.
+// Resolved names are cached on disk per workspace; on a cache miss it uses search.messages
+// for fast resolution, falling back to conversations.list pagination.
+// With WithFuzzyChannelNames, a name matching no channel exactly resolves to the best
+// partial match, if there is exactly one. Archived channels are only found with WithArchivedChannels.
 func ResolveChannelID(ctx context.Context, client *Client, input string) (string, error) {
 	name, isID := NormalizeChannelInput(input)
 	if isID {
@@ -35,10 +85,73 @@ func ResolveChannelID(ctx context.Context, client *Client, input string) (string
 		return "", errors.New("channel name is empty")
 	}
 
+	if channelID, ok := client.channels.Get(name); ok {
+		slog.Debug("channel cache hit", "channel", name, "id", channelID)
+		return channelID, nil
+	}
+
+	channelID, matched, err := resolveChannelName(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	// Cache the channel's real name, not a fuzzy input that happened to match it.
+	if err := client.channels.Set(matched, channelID); err != nil {
+		slog.Info("could not cache channel", "channel", matched, "error", err)
+	}
+	return channelID, nil
+}
+
+// GetChannelInfo fetches a conversation's metadata with conversations.info.
+// An unknown ID is reported as such rather than as Slack's bare error code.
+func GetChannelInfo(ctx context.Context, client APIClient, channelID string) (map[string]any, error) {
+	resp, err := client.API(ctx, "conversations.info", map[string]string{"channel": channelID})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "channel_not_found" {
+			return nil, withClass(fmt.Errorf("channel not found: %s", channelID), ErrNotFound)
+		}
+		return nil, fmt.Errorf("conversations.info: %w", err)
+	}
+	channel, _ := resp["channel"].(map[string]any)
+	if channel == nil {
+		return nil, errors.New("conversations.info: no channel in response")
+	}
+	return channel, nil
+}
+
+// ValidateChannelID checks that a channel ID exists and returns the channel's name
+// (empty for DMs, which have none).
+func ValidateChannelID(ctx context.Context, client APIClient, channelID string) (string, error) {
+	channel, err := GetChannelInfo(ctx, client, channelID)
+	if err != nil {
+		return "", err
+	}
+	name, _ := channel["name"].(string)
+	return name, nil
+}
+
+// InvalidateChannelOnError drops the cached ID for a channel name when err shows that
+// the channel could not be found, so the next invocation resolves it afresh.
+func InvalidateChannelOnError(client *Client, input string, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "channel_not_found" {
+		return
+	}
+	name, isID := NormalizeChannelInput(input)
+	if isID {
+		return
+	}
+	if err := client.channels.Delete(name); err != nil {
+		slog.Info("could not invalidate cached channel", "channel", name, "error", err)
+	}
+}
+
+// resolveChannelName returns the ID of the channel name refers to, and that channel's name.
+func resolveChannelName(ctx context.Context, client *Client, name string) (string, string, error) {
 	// Fast path: search.messages with in:#name resolves in 1 API call
 	channelID, err := resolveViaSearch(ctx, client, name)
 	if err == nil && channelID != "" {
-		return channelID, nil
+		return channelID, name, nil
 	}
 
 	// Slow path: paginate conversations.list
@@ -84,54 +197,263 @@ func resolveViaSearch(ctx context.Context, client *Client, name string) (string,
 	return channelID, nil
 }
 
-func resolveViaPagination(ctx context.Context, client *Client, name string) (string, error) {
-	cursor := ""
-	for {
-		params := map[string]string{
-			"exclude_archived": "true",
-			"limit":            "200",
+// resolveViaPagination scans conversations.list for name. Every name seen is cached along
+// the way, and a failed scan suggests the closest names it saw (or, with fuzzy matching,
+// uses the best of them when it is unambiguous).
+func resolveViaPagination(ctx context.Context, client *Client, name string) (string, string, error) {
+	channels := pager{
+		method: "conversations.list",
+		field:  "channels",
+		params: map[string]string{
+			"exclude_archived": strconv.FormatBool(!client.archivedChannels),
 			"types":            "public_channel,private_channel",
+		},
+	}.all(ctx, client)
+
+	seen := make(map[string]string)
+	defer func() {
+		if err := client.channels.SetMany(seen); err != nil {
+			slog.Info("could not cache channels", "error", err)
+		}
+	}()
+
+	for c, err := range channels {
+		if err != nil {
+			return "", "", err
+		}
+		cName, _ := c["name"].(string)
+		cID, _ := c["id"].(string)
+		if cName == "" || cID == "" {
+			continue
 		}
-		if cursor != "" {
-			params["cursor"] = cursor
+		if cName == name {
+			return cID, name, nil
 		}
+		seen[cName] = cID
+	}
 
-		resp, err := client.API(ctx, "conversations.list", params)
+	if client.fuzzyChannels {
+		best, ranked := fuzzyMatch(name, slices.Collect(maps.Keys(seen)))
+		if best != "" {
+			slog.Info("fuzzy matched channel", "input", name, "channel", best)
+			return seen[best], best, nil
+		}
+		if len(ranked) > 0 {
+			return "", "", fmt.Errorf("ambiguous channel name: #%s (matches #%s)", name, strings.Join(ranked, ", #"))
+		}
+	}
+
+	if suggestions := suggestNames(name, slices.Collect(maps.Keys(seen))); len(suggestions) > 0 {
+		return "", "", withClass(fmt.Errorf("could not resolve channel name: #%s (did you mean #%s?)",
+			name, strings.Join(suggestions, ", #")), ErrNotFound)
+	}
+	return "", "", withClass(fmt.Errorf("could not resolve channel name: #%s", name), ErrNotFound)
+}
+
+// channelsListedKey marks the channel cache as holding a full listing (from KnownChannels
+// or WarmChannels), not just the names resolved one at a time. No channel has this name.
+const channelsListedKey = "(listed)"
+
+// KnownChannels returns channel names mapped to IDs, from the channel cache when it holds
+// a full listing, or else from the current user's conversations (which are then cached).
+func KnownChannels(ctx context.Context, client *Client) (map[string]string, error) {
+	if known := client.channels.Entries(); known[channelsListedKey] != "" {
+		delete(known, channelsListedKey)
+		return known, nil
+	}
+
+	known := make(map[string]string)
+	for c, err := range IterUserConversations(ctx, client, "", ConversationOptions{IncludeArchived: client.archivedChannels}) {
 		if err != nil {
-			return "", fmt.Errorf("conversations.list: %w", err)
+			return nil, err
+		}
+		name, _ := c["name"].(string)
+		id, _ := c["id"].(string)
+		if name != "" && id != "" {
+			known[name] = id
 		}
+	}
+	if err := client.channels.SetMany(withListedMarker(known)); err != nil {
+		slog.Info("could not cache channels", "error", err)
+	}
+	return known, nil
+}
 
-		channels, _ := resp["channels"].([]any)
-		for _, ch := range channels {
-			c, _ := ch.(map[string]any)
-			if c == nil {
-				continue
-			}
-			if cName, _ := c["name"].(string); cName == name {
-				if cID, _ := c["id"].(string); cID != "" {
-					return cID, nil
+// withListedMarker returns known with channelsListedKey added, for caching a full listing.
+func withListedMarker(known map[string]string) map[string]string {
+	marked := maps.Clone(known)
+	marked[channelsListedKey] = "true"
+	return marked
+}
+
+// WarmChannels caches the name and ID of every channel visible in conversations.list
+// (archived ones too with WithArchivedChannels), so that ResolveChannelID needs no API
+// calls for them. It returns the number of channels cached.
+func WarmChannels(ctx context.Context, client *Client) (int, error) {
+	channels := pager{
+		method: "conversations.list",
+		field:  "channels",
+		params: map[string]string{
+			"exclude_archived": strconv.FormatBool(!client.archivedChannels),
+			"types":            "public_channel,private_channel",
+		},
+	}.all(ctx, client)
+
+	known := make(map[string]string)
+	for c, err := range channels {
+		if err != nil {
+			return 0, fmt.Errorf("conversations.list: %w", err)
+		}
+		name, _ := c["name"].(string)
+		id, _ := c["id"].(string)
+		if name != "" && id != "" {
+			known[name] = id
+		}
+	}
+	if err := client.channels.SetMany(withListedMarker(known)); err != nil {
+		return 0, fmt.Errorf("cache channels: %w", err)
+	}
+	return len(known), nil
+}
+
+// ConversationOptions controls which conversations are listed.
+type ConversationOptions struct {
+	IncludeArchived bool     // also list archived conversations
+	Types           []string // Slack conversation types (see ParseConversationTypes); empty = all
+}
+
+// conversationTypes maps short type names to Slack's conversation types.
+var conversationTypes = map[string]string{
+	"public":  "public_channel",
+	"private": "private_channel",
+	"im":      "im",
+	"mpim":    "mpim",
+}
+
+// ParseConversationTypes converts short type names (public, private, im, mpim) to Slack's
+// conversation types. Slack's own names (e.g., public_channel) are accepted too.
+func ParseConversationTypes(names []string) ([]string, error) {
+	var types []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		t, ok := conversationTypes[name]
+		if !ok {
+			for _, slackType := range conversationTypes {
+				if slackType == name {
+					t, ok = slackType, true
 				}
 			}
 		}
+		if !ok {
+			return nil, fmt.Errorf("unknown conversation type %q (want public, private, im, or mpim)", name)
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
 
-		meta, _ := resp["response_metadata"].(map[string]any)
-		next, _ := meta["next_cursor"].(string)
-		if next == "" {
-			break
+// MatchChannelNames returns a predicate matching conversations whose name matches the
+// glob pattern (e.g., "incident-*") and the regular expression, when given. Conversations
+// without a name (DMs) never match a non-empty pattern.
+func MatchChannelNames(glob, expr string) (func(channel map[string]any) bool, error) {
+	glob = strings.TrimPrefix(glob, "#")
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("invalid channel name pattern %q: %w", glob, err)
+	}
+	var re *regexp.Regexp
+	if expr != "" {
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid channel name regexp: %w", err)
 		}
-		cursor = next
 	}
 
-	return "", fmt.Errorf("could not resolve channel name: #%s", name)
+	return func(channel map[string]any) bool {
+		name, _ := channel["name"].(string)
+		if glob != "" {
+			if ok, _ := path.Match(glob, name); !ok || name == "" {
+				return false
+			}
+		}
+		return re == nil || (name != "" && re.MatchString(name))
+	}, nil
 }
 
-// ListUserConversations calls users.conversations to list channels for a user.
-func ListUserConversations(ctx context.Context, client *Client, user string, limit int, cursor string) (map[string]any, error) {
-	params := map[string]string{
-		"limit":            strconv.Itoa(normalizeLimit(limit)),
-		"types":            "public_channel,private_channel,im,mpim",
-		"exclude_archived": "true",
+// params returns the users.conversations / conversations.list filter params for o.
+func (o ConversationOptions) params() map[string]string {
+	types := "public_channel,private_channel,im,mpim"
+	if len(o.Types) > 0 {
+		types = strings.Join(o.Types, ",")
+	}
+	return map[string]string{
+		"types":            types,
+		"exclude_archived": strconv.FormatBool(!o.IncludeArchived),
+	}
+}
+
+// IterUserConversations streams every conversation a user (default: the current user) belongs to,
+// fetching users.conversations pages as they are consumed.
+func IterUserConversations(ctx context.Context, client APIClient, user string, opts ConversationOptions) iter.Seq2[map[string]any, error] {
+	params := opts.params()
+	if user != "" {
+		params["user"] = strings.TrimPrefix(strings.TrimSpace(user), "@")
+	}
+	return pager{method: "users.conversations", field: "channels", params: params}.all(ctx, client)
+}
+
+// IterAllConversations streams every workspace conversation,
+// fetching conversations.list pages as they are consumed.
+func IterAllConversations(ctx context.Context, client APIClient, opts ConversationOptions) iter.Seq2[map[string]any, error] {
+	return pager{method: "conversations.list", field: "channels", params: opts.params()}.all(ctx, client)
+}
+
+// IterSharedConversations streams the conversations every one of users belongs to (as far as
+// the current user can see), in the order users.conversations lists them for the first user.
+// Each user's full list is fetched before anything is yielded.
+func IterSharedConversations(ctx context.Context, client APIClient, users []string, opts ConversationOptions) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		if len(users) == 0 {
+			return
+		}
+		var shared []map[string]any
+		for c, err := range IterUserConversations(ctx, client, users[0], opts) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			shared = append(shared, c)
+		}
+
+		for _, user := range users[1:] {
+			member := make(map[string]bool)
+			for c, err := range IterUserConversations(ctx, client, user, opts) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				id, _ := c["id"].(string)
+				member[id] = true
+			}
+			shared = slices.DeleteFunc(shared, func(c map[string]any) bool {
+				id, _ := c["id"].(string)
+				return !member[id]
+			})
+		}
+
+		for _, c := range shared {
+			if !yield(c, nil) {
+				return
+			}
+		}
 	}
+}
+
+// ListUserConversations calls users.conversations to list channels for a user.
+func ListUserConversations(ctx context.Context, client *Client, user string, limit int, cursor string, opts ConversationOptions) (map[string]any, error) {
+	params := opts.params()
+	params["limit"] = strconv.Itoa(normalizeLimit(limit))
 	if user != "" {
 		// Strip leading @ if present
 		params["user"] = strings.TrimPrefix(strings.TrimSpace(user), "@")
@@ -144,12 +466,9 @@ func ListUserConversations(ctx context.Context, client *Client, user string, lim
 }
 
 // ListAllConversations calls conversations.list to list all workspace channels.
-func ListAllConversations(ctx context.Context, client *Client, limit int, cursor string) (map[string]any, error) {
-	params := map[string]string{
-		"limit":            strconv.Itoa(normalizeLimit(limit)),
-		"types":            "public_channel,private_channel,im,mpim",
-		"exclude_archived": "true",
-	}
+func ListAllConversations(ctx context.Context, client *Client, limit int, cursor string, opts ConversationOptions) (map[string]any, error) {
+	params := opts.params()
+	params["limit"] = strconv.Itoa(normalizeLimit(limit))
 	if cursor != "" {
 		params["cursor"] = cursor
 	}
@@ -158,6 +477,9 @@ func ListAllConversations(ctx context.Context, client *Client, limit int, cursor
 }
 
 // ResolveUserID resolves a @handle to a user ID by listing workspace members.
+// Handles (usernames) are unique; failing that, a user whose display name or real name
+// is handle (ignoring case) is found. When several are, the error is an
+// *AmbiguousUserError listing them.
 func ResolveUserID(ctx context.Context, client *Client, handle string) (string, error) {
 	cleaned := strings.TrimPrefix(strings.TrimSpace(handle), "@")
 	if cleaned == "" {
@@ -168,43 +490,72 @@ func ResolveUserID(ctx context.Context, client *Client, handle string) (string,
 	if regexp.MustCompile(`^U[A-Z0-9]{8,}$`).MatchString(cleaned) {
 		return cleaned, nil
 	}
+	return resolveUserName(ctx, client, cleaned)
+}
+
+// AmbiguousUserError is returned when a name matches the display or real names of
+// several users.
+type AmbiguousUserError struct {
+	Name       string
+	Candidates []UserMatch
+}
 
-	// Paginate users.list to find the user by name/display_name
-	cursor := ""
-	for {
-		params := map[string]string{"limit": "200"}
-		if cursor != "" {
-			params["cursor"] = cursor
+func (e *AmbiguousUserError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous user @%s matches %d users:", e.Name, len(e.Candidates))
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s @%s", c.ID, c.Name)
+		if c.RealName != "" {
+			fmt.Fprintf(&b, " (%s)", c.RealName)
+		}
+		if c.Title != "" {
+			fmt.Fprintf(&b, ", %s", c.Title)
 		}
+		if c.Deactivated {
+			b.WriteString(", deactivated")
+		}
+	}
+	return b.String()
+}
 
-		resp, err := client.API(ctx, "users.list", params)
+// resolveUserName scans users.list for the user named name, as ResolveUserID describes.
+// Deactivated users only match by display or real name when no active user does.
+func resolveUserName(ctx context.Context, client APIClient, name string) (string, error) {
+	var active, deactivated []map[string]any
+	for member, err := range (pager{method: "users.list", field: "members"}).all(ctx, client) {
 		if err != nil {
-			return "", fmt.Errorf("users.list: %w", err)
+			return "", err
 		}
-
-		members, _ := resp["members"].([]any)
-		for _, m := range members {
-			member, _ := m.(map[string]any)
-			if member == nil {
-				continue
-			}
-			name, _ := member["name"].(string)
-			if name == cleaned {
-				if id, _ := member["id"].(string); id != "" {
-					return id, nil
-				}
+		if handle, _ := member["name"].(string); handle == name {
+			if id, _ := member["id"].(string); id != "" {
+				return id, nil
 			}
 		}
-
-		meta, _ := resp["response_metadata"].(map[string]any)
-		next, _ := meta["next_cursor"].(string)
-		if next == "" {
-			break
+		profile, _ := member["profile"].(map[string]any)
+		displayName, _ := profile["display_name"].(string)
+		realName, _ := profile["real_name"].(string)
+		if !strings.EqualFold(displayName, name) && !strings.EqualFold(realName, name) {
+			continue
+		}
+		if deleted, _ := member["deleted"].(bool); deleted {
+			deactivated = append(deactivated, member)
+		} else {
+			active = append(active, member)
 		}
-		cursor = next
 	}
 
-	return "", fmt.Errorf("could not resolve user: @%s", cleaned)
+	matches := active
+	if len(matches) == 0 {
+		matches = deactivated
+	}
+	switch len(matches) {
+	case 0:
+		return "", withClass(fmt.Errorf("could not resolve user: @%s (try user search %s)", name, name), ErrNotFound)
+	case 1:
+		id, _ := matches[0]["id"].(string)
+		return id, nil
+	}
+	return "", &AmbiguousUserError{Name: name, Candidates: SearchUsers(matches, name)}
 }
 
 func normalizeLimit(limit int) int {
@@ -0,0 +1,278 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// infoAPI serves conversations.info for a fixed set of channels.
+type infoAPI struct {
+	channels map[string]string // id -> name
+}
+
+func (m *infoAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	name, ok := m.channels[params["channel"]]
+	if !ok {
+		return nil, &slack.APIError{Method: method, Code: "channel_not_found"}
+	}
+	return map[string]any{
+		"ok":      true,
+		"channel": map[string]any{"id": params["channel"], "name": name},
+	}, nil
+}
+
+func TestValidateChannelID(t *testing.T) {
+	mock := &infoAPI{channels: map[string]string{"C0123ABCD": "general"}}
+
+	name, err := slack.ValidateChannelID(t.Context(), mock, "C0123ABCD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "general" {
+		t.Errorf("got name %q, want %q", name, "general")
+	}
+
+	_, err = slack.ValidateChannelID(t.Context(), mock, "C0123ABCE")
+	if err == nil || err.Error() != "channel not found: C0123ABCE" {
+		t.Errorf("got error %v, want channel not found", err)
+	}
+}
+
+// listAPI records the params of each conversation listing call.
+type listAPI struct {
+	params []map[string]string
+}
+
+func (m *listAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.params = append(m.params, params)
+	return map[string]any{"ok": true, "channels": []any{}}, nil
+}
+
+func TestIterAllConversations_IncludeArchived(t *testing.T) {
+	for _, include := range []bool{false, true} {
+		mock := &listAPI{}
+		for _, err := range slack.IterAllConversations(t.Context(), mock, slack.ConversationOptions{IncludeArchived: include}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := "true"
+		if include {
+			want = "false"
+		}
+		if got := mock.params[0]["exclude_archived"]; got != want {
+			t.Errorf("IncludeArchived=%v: got exclude_archived=%q, want %q", include, got, want)
+		}
+	}
+}
+
+func TestParseConversationTypes(t *testing.T) {
+	got, err := slack.ParseConversationTypes([]string{"public", "Private", "mpim", "public_channel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"public_channel", "private_channel", "mpim"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := slack.ParseConversationTypes([]string{"dm"}); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
+
+func TestMatchChannelNames(t *testing.T) {
+	tests := []struct {
+		glob, expr string
+		name       string
+		want       bool
+	}{
+		{"incident-*", "", "incident-2026-01-31", true},
+		{"#incident-*", "", "incident-db", true},
+		{"incident-*", "", "eng-incidents", false},
+		{"", "^team-(web|api)", "team-api-oncall", true},
+		{"", "^team-(web|api)", "team-ios", false},
+		{"team-*", "oncall$", "team-api-oncall", true},
+		{"team-*", "oncall$", "team-api", false},
+		{"*", "", "", false}, // DMs have no name
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		match, err := slack.MatchChannelNames(tt.glob, tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := match(map[string]any{"name": tt.name}); got != tt.want {
+			t.Errorf("MatchChannelNames(%q, %q)(%q) = %v, want %v", tt.glob, tt.expr, tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := slack.MatchChannelNames("[", ""); err == nil {
+		t.Error("expected an error for a bad glob")
+	}
+	if _, err := slack.MatchChannelNames("", "("); err == nil {
+		t.Error("expected an error for a bad regexp")
+	}
+}
+
+// membershipAPI serves users.conversations from a fixed membership table.
+type membershipAPI struct {
+	member map[string][]string // user -> channel IDs
+}
+
+func (m *membershipAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	var channels []any
+	for _, id := range m.member[params["user"]] {
+		channels = append(channels, map[string]any{"id": id})
+	}
+	return map[string]any{"ok": true, "channels": channels}, nil
+}
+
+func TestIterSharedConversations(t *testing.T) {
+	mock := &membershipAPI{member: map[string][]string{
+		"U1": {"C1", "C2", "C3", "C4"},
+		"U2": {"C4", "C2", "C9"},
+		"U3": {"C2", "C4", "C5"},
+	}}
+
+	var got []string
+	for c, err := range slack.IterSharedConversations(t.Context(), mock, []string{"U1", "U2", "U3"}, slack.ConversationOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, c["id"].(string))
+	}
+	if want := []string{"C2", "C4"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseArchiveURL(t *testing.T) {
+	tests := []struct {
+		link                    string
+		channelID, ts, threadTS string
+		ok                      bool
+	}{
+		{"https://myteam.slack.com/archives/C0123ABCD", "C0123ABCD", "", "", true},
+		{"https://myteam.slack.com/archives/C0123ABCD/p1770165109628379", "C0123ABCD", "1770165109.628379", "", true},
+		{"https://myteam.slack.com/archives/C0123ABCD/p1770165200000100?thread_ts=1770165109.628379&cid=C0123ABCD",
+			"C0123ABCD", "1770165200.000100", "1770165109.628379", true},
+		{"https://myteam.slack.com/archives/general", "", "", "", false},
+		{"https://myteam.slack.com/messages/C0123ABCD", "", "", "", false},
+		{"#general", "", "", "", false},
+	}
+	for _, tt := range tests {
+		channelID, ts, threadTS, ok := slack.ParseArchiveURL(tt.link)
+		if channelID != tt.channelID || ts != tt.ts || threadTS != tt.threadTS || ok != tt.ok {
+			t.Errorf("ParseArchiveURL(%q) = %q, %q, %q, %v; want %q, %q, %q, %v", tt.link,
+				channelID, ts, threadTS, ok, tt.channelID, tt.ts, tt.threadTS, tt.ok)
+		}
+	}
+
+	if id, isID := slack.NormalizeChannelInput(" https://myteam.slack.com/archives/C0123ABCD/p1770165109628379 "); id != "C0123ABCD" || !isID {
+		t.Errorf("NormalizeChannelInput(link) = %q, %v; want C0123ABCD, true", id, isID)
+	}
+}
+
+func TestParseWorkspaceURL(t *testing.T) {
+	tests := []struct {
+		link      string
+		workspace string
+		ok        bool
+	}{
+		{"https://myteam.slack.com/archives/C0123ABCD/p1770165109628379", "myteam", true},
+		{"https://MyTeam.slack.com/archives/C0123ABCD", "myteam", true},
+		{"https://app.slack.com/client/T0123/C0123ABCD", "", false},
+		{"https://myorg.enterprise.slack.com/archives/C0123ABCD", "", false},
+		{"https://example.com/archives/C0123ABCD", "", false},
+		{"#general", "", false},
+	}
+	for _, tt := range tests {
+		workspace, ok := slack.ParseWorkspaceURL(tt.link)
+		if workspace != tt.workspace || ok != tt.ok {
+			t.Errorf("ParseWorkspaceURL(%q) = %q, %v; want %q, %v", tt.link, workspace, ok, tt.workspace, tt.ok)
+		}
+	}
+}
+
+// directoryAPI serves users.list with two Alexes and a deactivated Sam.
+type directoryAPI struct{}
+
+func (directoryAPI) API(context.Context, string, map[string]string) (map[string]any, error) {
+	member := func(id, name, display, real, title string, deleted bool) any {
+		return map[string]any{"id": id, "name": name, "deleted": deleted, "profile": map[string]any{
+			"display_name": display, "real_name": real, "title": title,
+		}}
+	}
+	return map[string]any{"ok": true, "members": []any{
+		member("U1", "asmith", "alex", "Alex Smith", "Engineer", false),
+		member("U2", "ajones", "Alex", "Alex Jones", "Designer", false),
+		member("U3", "alex", "lexi", "Alexandra Lee", "", false),
+		member("U4", "sam.old", "sam", "Sam Old", "", true),
+		member("U5", "kim", "kimberly", "Kim Park", "", false),
+	}}, nil
+}
+
+func TestResolveUserName(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"alex", "U3"},     // the handle wins over display names
+		{"kimberly", "U5"}, // by display name
+		{"kim park", "U5"}, // by real name, ignoring case
+		{"sam", "U4"},      // only a deactivated user matches
+	}
+	for _, tt := range tests {
+		got, err := slack.ResolveUserName(t.Context(), directoryAPI{}, tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveUserName(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	_, err := slack.ResolveUserName(t.Context(), directoryAPI{}, "ALEX")
+	var ambiguous *slack.AmbiguousUserError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("resolveUserName(ALEX) error = %v, want ambiguity between U1 and U2", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "U1 @asmith (Alex Smith), Engineer") || !strings.Contains(msg, "U2 @ajones (Alex Jones), Designer") {
+		t.Errorf("error = %q, want both candidates with titles", msg)
+	}
+
+	if _, err := slack.ResolveUserName(t.Context(), directoryAPI{}, "nobody"); err == nil {
+		t.Error("resolveUserName(nobody) succeeded, want error")
+	}
+}
+
+// The picker's channel list comes from the cache only when it holds a full listing, not
+// after names were resolved one at a time.
+func TestKnownChannels_FullListingOnly(t *testing.T) {
+	client, err := slack.NewCachedClient(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := map[string]string{"types": "public_channel,private_channel,im,mpim", "exclude_archived": "true", "limit": "200"}
+	if err := client.CacheResponse("users.conversations", params, map[string]any{"ok": true, "channels": []any{
+		map[string]any{"id": "C1", "name": "general"},
+		map[string]any{"id": "C2", "name": "random"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CacheChannel("general", "C1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"general": "C1", "random": "C2"}
+	for range 2 { // listed, then from the cache
+		known, err := slack.KnownChannels(t.Context(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(known, want) {
+			t.Errorf("KnownChannels = %v, want %v", known, want)
+		}
+	}
+}
@@ -3,13 +3,38 @@ package slack
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	slackapi "github.com/rneatherway/slack"
+	"github.com/sethrylan/slack-reader/internal/cache"
 )
 
+// defaultRequestTimeout bounds a single HTTP attempt when no timeout is configured.
+const defaultRequestTimeout = time.Minute
+
+// channelCacheTTL bounds how long a resolved channel name→ID mapping is trusted.
+const channelCacheTTL = 24 * time.Hour
+
+// userDirectoryTTL bounds how long the cached users.list member directory is trusted.
+const userDirectoryTTL = 24 * time.Hour
+
+// fileInfoCacheTTL bounds how long cached files.info metadata is trusted.
+const fileInfoCacheTTL = 24 * time.Hour
+
+// ErrAuthFailed is returned when no usable credentials could be found.
+var ErrAuthFailed = errors.New("authentication failed")
+
 // Auth holds the token and cookies needed for Slack API access.
 type Auth struct {
 	Token   string `json:"token"`
@@ -21,7 +46,7 @@ type Auth struct {
 func GetCookieAuth(domain string) (*Auth, error) {
 	auth, err := slackapi.GetCookieAuth(domain)
 	if err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
 	}
 	vals := url.Values{}
 	for k, v := range auth.Cookies {
@@ -32,24 +57,227 @@ func GetCookieAuth(domain string) (*Auth, error) {
 
 // Client wraps the rneatherway/slack client for Slack API access.
 type Client struct {
-	api    *slackapi.Client
-	domain string
+	api        *slackapi.Client
+	domain     string
+	maxRetries int
+	channels   *cache.Store
+	users      *cache.FileStore // the users.list directory, for SearchUsers
+	files      *cache.Store     // files.info metadata, for AddFileInfo
+	responses  *cache.FileStore
+
+	requestTimeout   time.Duration
+	transport        *http.Transport
+	httpClient       *http.Client
+	downloadAuth     func() (*slackapi.Auth, error)
+	stats            *Stats
+	envAuthOnly      bool
+	fuzzyChannels    bool
+	trace            bool
+	archivedChannels bool
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithResponseCache enables a read-through cache of successful API responses,
+// keyed by method and params, for the given TTL.
+func WithResponseCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl <= 0 {
+			return
+		}
+		c.responses = openFileCache(c.domain, "responses", ttl)
+	}
+}
+
+// WithRequestTimeout bounds each HTTP request to Slack (0 disables the timeout).
+// Time spent waiting out a rate limit does not count against it.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithMaxRetries sets how many times a rate-limited or transiently failing request is retried.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = max(n, 0)
+	}
+}
+
+// WithStats records API calls, bytes, retries, and cache hits into s.
+func WithStats(s *Stats) Option {
+	return func(c *Client) {
+		c.stats = s
+	}
+}
+
+// WithEnvAuthOnly restricts authentication to the SLACK_TOKEN and SLACK_COOKIES
+// environment variables, so credentials are never extracted from Slack Desktop.
+func WithEnvAuthOnly() Option {
+	return func(c *Client) {
+		c.envAuthOnly = true
+	}
+}
+
+// WithFuzzyChannelNames lets ResolveChannelID fall back to partial and misspelled names:
+// when no channel has exactly the given name, an unambiguous best match is used instead.
+func WithFuzzyChannelNames() Option {
+	return func(c *Client) {
+		c.fuzzyChannels = true
+	}
+}
+
+// WithArchivedChannels lets ResolveChannelID (and the channel picker) find archived channels,
+// whose history can still be read.
+func WithArchivedChannels() Option {
+	return func(c *Client) {
+		c.archivedChannels = true
+	}
+}
+
+// WithMaxIdleConns sets how many idle keep-alive connections to Slack are kept open.
+func WithMaxIdleConns(n int) Option {
+	return func(c *Client) {
+		c.transport.MaxIdleConns = n
+		c.transport.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle keep-alive connection is kept before closing.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.transport.IdleConnTimeout = d
+	}
+}
+
+// WithTLSConfig sets the TLS configuration (e.g., custom root CAs for an intercepting proxy).
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.transport.TLSClientConfig = cfg
+	}
+}
+
+// WithHTTPTrace logs every HTTP request and response (each retry attempt included) at
+// debug level, with tokens and cookies redacted.
+func WithHTTPTrace() Option {
+	return func(c *Client) {
+		c.trace = true
+	}
 }
 
 // NewClient creates a new Slack client for the given team domain.
-// It automatically sets up cookie-based authentication from local Slack Desktop data.
-func NewClient(domain string) (*Client, error) {
-	c := slackapi.NewClient(domain)
-	if err := c.WithCookieAuth(); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+// It automatically sets up cookie-based authentication from SLACK_TOKEN and SLACK_COOKIES,
+// or else from local Slack Desktop data (unless WithEnvAuthOnly is given).
+func NewClient(domain string, opts ...Option) (*Client, error) {
+	c := NewClientNoCreds(domain, opts...)
+	if c.envAuthOnly {
+		if _, ok := slackapi.TryGetEnvAuth(); !ok {
+			return nil, fmt.Errorf("%w: %s and %s must be set", ErrAuthFailed, slackapi.EnvSlackToken, slackapi.EnvSlackCookies)
+		}
 	}
-	return &Client{api: c, domain: domain}, nil
+	if err := c.api.WithCookieAuth(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
+	return c, nil
 }
 
 // NewClientNoCreds creates a client without triggering credential import.
 // Used for auth creds to handle the import step explicitly.
-func NewClientNoCreds(domain string) *Client {
-	return &Client{api: slackapi.NewClient(domain), domain: domain}
+func NewClientNoCreds(domain string, opts ...Option) *Client {
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	c := &Client{
+		api:            slackapi.NewClient(domain),
+		domain:         domain,
+		maxRetries:     defaultMaxRetries,
+		channels:       openCache(domain, "channels", channelCacheTTL),
+		users:          openFileCache(domain, "users", userDirectoryTTL),
+		files:          openCache(domain, "files", fileInfoCacheTTL),
+		requestTimeout: defaultRequestTimeout,
+		transport:      transport.Clone(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	base := http.RoundTripper(c.transport)
+	if c.trace {
+		base = &traceTransport{base: base}
+	}
+	retry := newRetryTransport(base, c.maxRetries, c.requestTimeout)
+	retry.onRetry = c.stats.recordRetry
+	c.httpClient = &http.Client{Transport: retry}
+	c.api.WithHTTPClient(c.httpClient)
+	c.downloadAuth = sync.OnceValues(c.lookupAuth)
+	return c
+}
+
+// lookupAuth finds credentials the way NewClient does, for requests made outside the API client.
+func (c *Client) lookupAuth() (*slackapi.Auth, error) {
+	if auth, ok := slackapi.TryGetEnvAuth(); ok {
+		return auth, nil
+	}
+	if c.envAuthOnly {
+		return nil, fmt.Errorf("%w: %s and %s must be set", ErrAuthFailed, slackapi.EnvSlackToken, slackapi.EnvSlackCookies)
+	}
+	auth, err := slackapi.GetCookieAuth(c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
+	return auth, nil
+}
+
+// Download fetches a private Slack file (e.g., a file's url_private) with the client's credentials.
+// Only slack.com URLs are fetched, so credentials are never sent elsewhere.
+func (c *Client) Download(ctx context.Context, link string) ([]byte, error) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "https" || (u.Hostname() != "slack.com" && !strings.HasSuffix(u.Hostname(), ".slack.com")) {
+		return nil, fmt.Errorf("download: not a Slack file URL: %q", link)
+	}
+	auth, err := c.downloadAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+auth.Token)
+	for name, value := range auth.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	c.stats.recordCall("files.download", len(body), time.Since(start))
+	return body, nil
+}
+
+// openCache opens a per-workspace cache file, returning nil (no caching) if it is unavailable.
+func openCache(domain, name string, ttl time.Duration) *cache.Store {
+	path, err := cache.WorkspacePath(domain, name)
+	if err != nil {
+		slog.Info("cache unavailable", "cache", name, "error", err)
+		return nil
+	}
+	s, err := cache.Open(path, ttl)
+	if err != nil {
+		slog.Info("cache unavailable", "cache", name, "error", err)
+		return nil
+	}
+	return s
 }
 
 // ImportCreds triggers the cookie-based authentication flow (extracts from Slack Desktop).
@@ -62,11 +290,75 @@ type APIClient interface {
 	API(ctx context.Context, method string, params map[string]string) (map[string]any, error)
 }
 
+// APIError is returned when Slack responds with ok=false.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slack API %s: %s", e.Method, e.Code)
+}
+
+// Is reports whether the error code is in the failure class target: "ratelimited"
+// matches ErrRateLimited, codes such as "channel_not_found" match ErrNotFound, and codes
+// such as "invalid_auth" match ErrAuthFailed.
+func (e *APIError) Is(target error) bool {
+	class := codeClass(e.Code)
+	return class != nil && target == class
+}
+
 // API makes a POST request to the given Slack API method and unmarshals the response.
+// Requests that are rate limited or fail transiently are retried with backoff.
 func (c *Client) API(ctx context.Context, method string, params map[string]string) (map[string]any, error) {
-	body, err := c.api.API(ctx, "POST", method, params, nil)
-	if err != nil {
-		return nil, fmt.Errorf("slack API %s: %w", method, err)
+	for attempt := 0; ; attempt++ {
+		result, err := c.call(ctx, method, params)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != "ratelimited" || attempt >= c.maxRetries {
+			return result, err
+		}
+
+		d := withJitter(defaultRetryAfter << attempt)
+		slog.Info("rate limited, retrying", "method", method, "attempt", attempt+1, "wait", d)
+		c.stats.recordRetry(true)
+		if err := sleepContext(ctx, d); err != nil {
+			return nil, requestError(ctx, method, err)
+		}
+	}
+}
+
+// requestError describes a request that failed to get a response, marking it ErrNetwork
+// when Slack could not be reached or the deadline passed. An ended context is reported
+// by its cause (e.g., an expired --timeout), not just "context deadline exceeded".
+func requestError(ctx context.Context, method string, err error) error {
+	if ctx.Err() != nil {
+		err = context.Cause(ctx)
+	}
+	err = fmt.Errorf("slack API %s: %w", method, err)
+	if isNetworkError(err) {
+		err = withClass(err, ErrNetwork)
+	}
+	return err
+}
+
+func (c *Client) call(ctx context.Context, method string, params map[string]string) (map[string]any, error) {
+	key := responseCacheKey(method, params)
+	body, cached := c.responses.Get(key)
+	if c.responses != nil {
+		c.stats.recordCache(cached)
+	}
+	if cached {
+		slog.Debug("response cache hit", "method", method)
+	} else {
+		start := time.Now()
+		var err error
+		body, err = c.api.API(ctx, "POST", method, params, nil)
+		if err != nil {
+			return nil, requestError(ctx, method, err)
+		}
+		elapsed := time.Since(start)
+		c.stats.recordCall(method, len(body), elapsed)
+		slog.Debug("api call", "method", method, "bytes", len(body), "elapsed", elapsed)
 	}
 
 	var result map[string]any
@@ -79,13 +371,38 @@ func (c *Client) API(ctx context.Context, method string, params map[string]strin
 		if errMsg == "" {
 			errMsg = "unknown error"
 		}
-		return nil, fmt.Errorf("slack API %s: %s", method, errMsg)
+		return nil, &APIError{Method: method, Code: errMsg}
+	}
+
+	if !cached {
+		if err := c.responses.Set(key, body); err != nil {
+			slog.Info("could not cache response", "method", method, "error", err)
+		}
 	}
 
 	return result, nil
 }
 
+// responseCacheKey identifies a request by method and (sorted) params.
+func responseCacheKey(method string, params map[string]string) string {
+	vals := url.Values{}
+	for k, v := range params {
+		vals.Set(k, v)
+	}
+	return method + "?" + vals.Encode()
+}
+
 // Domain returns the workspace domain.
 func (c *Client) Domain() string {
 	return c.domain
 }
+
+// openFileCache returns a per-workspace file cache directory, or nil (no caching) if it is unavailable.
+func openFileCache(domain, name string, ttl time.Duration) *cache.FileStore {
+	dir, err := cache.WorkspaceDir(domain)
+	if err != nil {
+		slog.Info("cache unavailable", "cache", name, "error", err)
+		return nil
+	}
+	return cache.NewFileStore(filepath.Join(dir, name), ttl)
+}
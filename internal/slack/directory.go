@@ -0,0 +1,50 @@
+package slack
+
+import "strconv"
+
+// DirectoryEntry is one member of the workspace directory, flattened for export.
+type DirectoryEntry struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	RealName    string `json:"real_name"`
+	Email       string `json:"email"` // "" unless visible to the token
+	Title       string `json:"title"`
+	StatusEmoji string `json:"status_emoji"`
+	StatusText  string `json:"status_text"`
+	IsBot       bool   `json:"is_bot"`
+	Deleted     bool   `json:"deleted"`
+}
+
+// DirectoryColumns are the CSV column names of a DirectoryEntry, in Row order.
+var DirectoryColumns = []string{
+	"id", "name", "display_name", "real_name", "email", "title", "status_emoji", "status_text", "is_bot", "deleted",
+}
+
+// NewDirectoryEntry flattens a users.list member.
+func NewDirectoryEntry(user map[string]any) DirectoryEntry {
+	profile, _ := user["profile"].(map[string]any)
+	var e DirectoryEntry
+	e.ID, _ = user["id"].(string)
+	e.Name, _ = user["name"].(string)
+	e.DisplayName, _ = profile["display_name"].(string)
+	e.RealName, _ = profile["real_name"].(string)
+	if e.RealName == "" {
+		e.RealName, _ = user["real_name"].(string)
+	}
+	e.Email, _ = profile["email"].(string)
+	e.Title, _ = profile["title"].(string)
+	e.StatusEmoji, _ = profile["status_emoji"].(string)
+	e.StatusText, _ = profile["status_text"].(string)
+	e.IsBot, _ = user["is_bot"].(bool)
+	e.Deleted, _ = user["deleted"].(bool)
+	return e
+}
+
+// Row returns the entry's fields as CSV values, in DirectoryColumns order.
+func (e DirectoryEntry) Row() []string {
+	return []string{
+		e.ID, e.Name, e.DisplayName, e.RealName, e.Email, e.Title, e.StatusEmoji, e.StatusText,
+		strconv.FormatBool(e.IsBot), strconv.FormatBool(e.Deleted),
+	}
+}
@@ -0,0 +1,25 @@
+package slack_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestNewDirectoryEntry(t *testing.T) {
+	e := slack.NewDirectoryEntry(map[string]any{
+		"id": "U1", "name": "asmith", "deleted": true,
+		"profile": map[string]any{
+			"display_name": "alice", "real_name": "Alice Smith", "email": "alice@example.com",
+			"title": "Engineer", "status_emoji": ":palm_tree:", "status_text": "Away",
+		},
+	})
+	want := []string{"U1", "asmith", "alice", "Alice Smith", "alice@example.com", "Engineer", ":palm_tree:", "Away", "false", "true"}
+	if got := e.Row(); !slices.Equal(got, want) {
+		t.Errorf("Row() = %v, want %v", got, want)
+	}
+	if len(slack.DirectoryColumns) != len(want) {
+		t.Errorf("DirectoryColumns has %d columns, want %d", len(slack.DirectoryColumns), len(want))
+	}
+}
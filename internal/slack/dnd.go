@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DND is a user's do-not-disturb state from dnd.info.
+type DND struct {
+	Enabled       bool   `json:"enabled"`              // a daily DND window is set
+	NextStart     string `json:"next_start,omitempty"` // RFC 3339, UTC
+	NextEnd       string `json:"next_end,omitempty"`   // RFC 3339, UTC
+	SnoozeEnabled bool   `json:"snooze_enabled"`       // notifications are paused by hand
+	SnoozeEnd     string `json:"snooze_end,omitempty"` // RFC 3339, UTC
+	Active        bool   `json:"active"`               // notifications are off at the time asked about
+}
+
+// UserStatus is a user's custom status.
+type UserStatus struct {
+	Emoji     string `json:"emoji,omitempty"`
+	Text      string `json:"text,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"` // RFC 3339, UTC; "" if it does not expire
+}
+
+// GetDND fetches a user's do-not-disturb state with dnd.info. Active reports whether the
+// user's notifications are paused at now, by a snooze or the daily DND window.
+func GetDND(ctx context.Context, client APIClient, userID string, now time.Time) (*DND, error) {
+	resp, err := client.API(ctx, "dnd.info", map[string]string{"user": userID})
+	if err != nil {
+		return nil, fmt.Errorf("dnd.info: %w", err)
+	}
+	enabled, _ := resp["dnd_enabled"].(bool)
+	start, _ := resp["next_dnd_start_ts"].(float64)
+	end, _ := resp["next_dnd_end_ts"].(float64)
+	snoozed, _ := resp["snooze_enabled"].(bool)
+	snoozeEnd, _ := resp["snooze_endtime"].(float64)
+
+	d := &DND{Enabled: enabled, SnoozeEnabled: snoozed}
+	if enabled {
+		d.NextStart, d.NextEnd = unixTime(start), unixTime(end)
+	}
+	if snoozed {
+		d.SnoozeEnd = unixTime(snoozeEnd)
+	}
+	unix := float64(now.Unix())
+	d.Active = (snoozed && (snoozeEnd == 0 || unix < snoozeEnd)) ||
+		(enabled && start > 0 && start <= unix && unix < end)
+	return d, nil
+}
+
+// StatusOf returns a user object's custom status, or nil when none is set.
+func StatusOf(user map[string]any) *UserStatus {
+	profile, _ := user["profile"].(map[string]any)
+	emoji, _ := profile["status_emoji"].(string)
+	text, _ := profile["status_text"].(string)
+	if emoji == "" && text == "" {
+		return nil
+	}
+	expiration, _ := profile["status_expiration"].(float64)
+	return &UserStatus{Emoji: emoji, Text: text, ExpiresAt: unixTime(expiration)}
+}
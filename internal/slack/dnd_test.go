@@ -0,0 +1,68 @@
+package slack_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// dndAPI serves a fixed dnd.info response.
+type dndAPI map[string]any
+
+func (m dndAPI) API(context.Context, string, map[string]string) (map[string]any, error) {
+	return m, nil
+}
+
+func TestGetDND(t *testing.T) {
+	now := time.Date(2026, 2, 4, 23, 0, 0, 0, time.UTC)
+	start := float64(time.Date(2026, 2, 4, 22, 0, 0, 0, time.UTC).Unix())
+	end := float64(time.Date(2026, 2, 5, 7, 0, 0, 0, time.UTC).Unix())
+
+	tests := []struct {
+		name string
+		resp dndAPI
+		want slack.DND
+	}{
+		{
+			name: "inside the daily window",
+			resp: dndAPI{"ok": true, "dnd_enabled": true, "next_dnd_start_ts": start, "next_dnd_end_ts": end},
+			want: slack.DND{Enabled: true, NextStart: "2026-02-04T22:00:00Z", NextEnd: "2026-02-05T07:00:00Z", Active: true},
+		},
+		{
+			name: "before the daily window",
+			resp: dndAPI{"ok": true, "dnd_enabled": true, "next_dnd_start_ts": start + 7200, "next_dnd_end_ts": end},
+			want: slack.DND{Enabled: true, NextStart: "2026-02-05T00:00:00Z", NextEnd: "2026-02-05T07:00:00Z"},
+		},
+		{
+			name: "snoozed",
+			resp: dndAPI{"ok": true, "snooze_enabled": true, "snooze_endtime": end},
+			want: slack.DND{SnoozeEnabled: true, SnoozeEnd: "2026-02-05T07:00:00Z", Active: true},
+		},
+		{
+			name: "off",
+			resp: dndAPI{"ok": true},
+			want: slack.DND{},
+		},
+	}
+	for _, tt := range tests {
+		got, err := slack.GetDND(t.Context(), tt.resp, "U1", now)
+		if err != nil || *got != tt.want {
+			t.Errorf("%s: GetDND = %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	user := map[string]any{"profile": map[string]any{
+		"status_emoji": ":palm_tree:", "status_text": "Vacationing", "status_expiration": float64(1770274800),
+	}}
+	want := slack.UserStatus{Emoji: ":palm_tree:", Text: "Vacationing", ExpiresAt: "2026-02-05T07:00:00Z"}
+	if got := slack.StatusOf(user); got == nil || *got != want {
+		t.Errorf("StatusOf = %+v, want %+v", got, want)
+	}
+	if got := slack.StatusOf(map[string]any{"profile": map[string]any{}}); got != nil {
+		t.Errorf("StatusOf(no status) = %+v, want nil", got)
+	}
+}
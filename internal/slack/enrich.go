@@ -0,0 +1,115 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// enrichConcurrency bounds the per-channel API calls EnrichChannels makes at once.
+const enrichConcurrency = 8
+
+// EnrichOptions selects the extra API calls EnrichChannels may make.
+type EnrichOptions struct {
+	// MemberCounts fetches num_members with conversations.info when the listing omitted it
+	// (users.conversations never includes it).
+	MemberCounts bool
+
+	// LastMessage probes each channel's history for its newest message.
+	LastMessage bool
+}
+
+// EnrichChannels adds joined-up fields to conversation objects in place, saving callers
+// the follow-up lookups:
+//
+//   - creator_name: the creator's display name
+//   - topic_text, purpose_text: the topic and purpose values
+//   - num_members: with opts.MemberCounts, when missing
+//   - last_message_ts, last_message_at: with opts.LastMessage (RFC 3339, UTC)
+func EnrichChannels(ctx context.Context, client APIClient, users *UserProvider, channels []map[string]any, opts EnrichOptions) error {
+	var creators []map[string]any
+	for _, c := range channels {
+		if creator, _ := c["creator"].(string); creator != "" {
+			creators = append(creators, map[string]any{"user": creator})
+		}
+	}
+	users.Prefetch(ctx, creators)
+
+	// Probes write into their own slots; the channel maps are only updated after Wait.
+	members := make([]any, len(channels))
+	lastTS := make([]string, len(channels))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for i, c := range channels {
+		if creator, _ := c["creator"].(string); creator != "" {
+			c["creator_name"], _ = users.UsernameForID(creator)
+		}
+		if topic, _ := c["topic"].(map[string]any); topic != nil {
+			c["topic_text"] = topic["value"]
+		}
+		if purpose, _ := c["purpose"].(map[string]any); purpose != nil {
+			c["purpose_text"] = purpose["value"]
+		}
+
+		id, _ := c["id"].(string)
+		if id == "" {
+			continue
+		}
+		if _, ok := c["num_members"]; opts.MemberCounts && !ok {
+			g.Go(func() (err error) {
+				members[i], err = memberCount(ctx, client, id)
+				return err
+			})
+		}
+		if opts.LastMessage {
+			g.Go(func() (err error) {
+				lastTS[i], err = lastMessageTS(ctx, client, id)
+				return err
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for i, c := range channels {
+		if members[i] != nil {
+			c["num_members"] = members[i]
+		}
+		if lastTS[i] != "" {
+			c["last_message_ts"] = lastTS[i]
+			if t, err := ParseTimestamp(lastTS[i]); err == nil {
+				c["last_message_at"] = t.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	return nil
+}
+
+// memberCount fetches a conversation's member count, or nil if Slack doesn't report one.
+func memberCount(ctx context.Context, client APIClient, id string) (any, error) {
+	resp, err := client.API(ctx, "conversations.info", map[string]string{
+		"channel":             id,
+		"include_num_members": "true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("conversations.info: %w", err)
+	}
+	info, _ := resp["channel"].(map[string]any)
+	return info["num_members"], nil
+}
+
+// lastMessageTS returns the timestamp of a channel's newest message ("" if it has none).
+func lastMessageTS(ctx context.Context, client APIClient, id string) (string, error) {
+	for msg, err := range IterChannelHistory(ctx, client, id, HistoryOptions{Limit: 1}) {
+		if err != nil {
+			return "", err
+		}
+		ts, _ := msg["ts"].(string)
+		return ts, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,63 @@
+package slack_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// enrichAPI serves the lookups EnrichChannels makes.
+type enrichAPI struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (m *enrichAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	m.calls[method]++
+	m.mu.Unlock()
+
+	switch method {
+	case "users.info":
+		return map[string]any{"ok": true, "user": map[string]any{"id": params["user"], "name": "alice"}}, nil
+	case "conversations.info":
+		return map[string]any{"ok": true, "channel": map[string]any{"id": params["channel"], "num_members": float64(42)}}, nil
+	case "conversations.history":
+		return map[string]any{"ok": true, "messages": []any{map[string]any{"ts": "1770000000.000100"}}}, nil
+	}
+	return nil, &slack.APIError{Method: method, Code: "unknown_method"}
+}
+
+func TestEnrichChannels(t *testing.T) {
+	mock := &enrichAPI{calls: make(map[string]int)}
+	channels := []map[string]any{
+		{"id": "C1", "name": "general", "creator": "U1", "topic": map[string]any{"value": "All hands"}},
+		{"id": "C2", "name": "eng", "num_members": float64(7)},
+	}
+
+	opts := slack.EnrichOptions{MemberCounts: true, LastMessage: true}
+	if err := slack.EnrichChannels(t.Context(), mock, slack.NewUserProvider(mock), channels, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := channels[0]["creator_name"]; got != "alice" {
+		t.Errorf("got creator_name %v, want alice", got)
+	}
+	if got := channels[0]["topic_text"]; got != "All hands" {
+		t.Errorf("got topic_text %v", got)
+	}
+	if got := channels[0]["num_members"]; got != float64(42) {
+		t.Errorf("got num_members %v, want 42", got)
+	}
+	if got := channels[1]["num_members"]; got != float64(7) {
+		t.Errorf("got num_members %v, want the listed 7", got)
+	}
+	if got := channels[1]["last_message_at"]; got != "2026-02-02T02:40:00Z" {
+		t.Errorf("got last_message_at %v", got)
+	}
+	if mock.calls["conversations.info"] != 1 || mock.calls["conversations.history"] != 2 {
+		t.Errorf("got calls %v, want 1 conversations.info and 2 conversations.history", mock.calls)
+	}
+}
@@ -0,0 +1,120 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+)
+
+// EnterpriseUserID returns the org-level ID (W...) of a user on Enterprise Grid, from
+// the user object's enterprise_user block, or "" outside Enterprise Grid.
+func EnterpriseUserID(user map[string]any) string {
+	eu, _ := user["enterprise_user"].(map[string]any)
+	id, _ := eu["id"].(string)
+	return id
+}
+
+// userIDs returns the IDs a user is known by: the requested ID, the user's local ID,
+// and its org-level ID on Enterprise Grid, without repeats.
+func userIDs(requested string, user map[string]any) []string {
+	ids := []string{requested}
+	local, _ := user["id"].(string)
+	for _, id := range []string{local, EnterpriseUserID(user)} {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// isUserNotFound reports whether err is Slack's user_not_found, which users.info returns
+// for an org-level ID it only knows by its local ID, and vice versa.
+func isUserNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == "user_not_found"
+}
+
+// translateUser finds a user that users.info did not know by id in the users.list
+// directory, which lists each member's local ID and (in enterprise_user) org-level ID.
+func (u *UserProvider) translateUser(ctx context.Context, id string) map[string]any {
+	return u.loadDirectory(ctx)[id]
+}
+
+// loadDirectory returns the users.list directory, loading it once per provider, from the
+// client's cache when it has one.
+func (u *UserProvider) loadDirectory(ctx context.Context) map[string]map[string]any {
+	if u.client == nil {
+		return nil
+	}
+	v, _, _ := u.group.Do("directory", func() (any, error) {
+		u.mu.Lock()
+		directory := u.directory
+		u.mu.Unlock()
+		if directory != nil {
+			return directory, nil
+		}
+
+		var members []map[string]any
+		var err error
+		if client, ok := u.client.(*Client); ok {
+			members, err = ListUsers(ctx, client)
+		} else {
+			for member, perr := range (pager{method: "users.list", field: "members"}).all(ctx, u.client) {
+				if perr != nil {
+					err = perr
+					break
+				}
+				members = append(members, member)
+			}
+		}
+		if err != nil {
+			slog.Info("could not list users", "error", err)
+		}
+		directory = indexDirectory(members)
+		u.mu.Lock()
+		u.directory = directory
+		u.mu.Unlock()
+		return directory, nil
+	})
+	directory, _ := v.(map[string]map[string]any)
+	return directory
+}
+
+// cachedDirectoryUser looks id up in the users.list directory if the client has it
+// cached (e.g., after cache warm), so that a warm cache resolves users without calling
+// users.info. The cache is read at most once per provider.
+func (u *UserProvider) cachedDirectoryUser(id string) map[string]any {
+	u.directoryOnce.Do(func() {
+		client, ok := u.client.(*Client)
+		if !ok {
+			return
+		}
+		members, ok := CachedUsers(client)
+		if !ok {
+			return
+		}
+		u.mu.Lock()
+		if u.directory == nil {
+			u.directory = indexDirectory(members)
+		}
+		u.mu.Unlock()
+	})
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.directory[id]
+}
+
+// indexDirectory maps users.list members by their local and org-level IDs.
+func indexDirectory(members []map[string]any) map[string]map[string]any {
+	directory := make(map[string]map[string]any, len(members))
+	for _, m := range members {
+		local, _ := m["id"].(string)
+		for _, id := range []string{local, EnterpriseUserID(m)} {
+			if id != "" {
+				directory[id] = m
+			}
+		}
+	}
+	return directory
+}
@@ -0,0 +1,82 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// gridAPI is an Enterprise Grid workspace: users.info knows alice by both of her IDs,
+// but bob only by his local ID, so his org-level ID needs the users.list directory.
+type gridAPI struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (a *gridAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	a.mu.Lock()
+	a.calls = append(a.calls, method+" "+params["user"])
+	a.mu.Unlock()
+	alice := map[string]any{
+		"id": "U1", "tz": "Europe/Paris", "profile": map[string]any{"display_name": "alice"},
+		"enterprise_user": map[string]any{"id": "W1", "enterprise_id": "E1"},
+	}
+	bob := map[string]any{
+		"id": "U2", "profile": map[string]any{"display_name": "bob"},
+		"enterprise_user": map[string]any{"id": "W2", "enterprise_id": "E1"},
+	}
+	switch {
+	case method == "users.info" && (params["user"] == "U1" || params["user"] == "W1"):
+		return map[string]any{"ok": true, "user": alice}, nil
+	case method == "users.info":
+		return nil, &slack.APIError{Method: method, Code: "user_not_found"}
+	case method == "users.list":
+		return map[string]any{"ok": true, "members": []any{alice, bob}}, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", method)
+}
+
+func TestUserProvider_EnterpriseIDs(t *testing.T) {
+	api := &gridAPI{}
+	users := slack.NewUserProvider(api)
+	users.Prefetch(t.Context(), []map[string]any{
+		{"user": "W1", "text": "ask <@W2> or <@W3>"},
+	})
+
+	for id, want := range map[string]string{"W1": "alice", "U1": "alice", "W2": "bob", "U2": "bob", "W3": "W3"} {
+		if got, _ := users.UsernameForID(id); got != want {
+			t.Errorf("UsernameForID(%s) = %q, want %q", id, got, want)
+		}
+	}
+	if n := len(slices.DeleteFunc(api.calls, func(c string) bool { return c != "users.list " })); n != 1 {
+		t.Errorf("users.list calls = %d, want 1", n)
+	}
+	if got := slack.EnterpriseUserID(map[string]any{"id": "U1"}); got != "" {
+		t.Errorf("EnterpriseUserID outside Enterprise Grid = %q, want empty", got)
+	}
+}
+
+func TestUserProvider_CachedDirectory(t *testing.T) {
+	client, err := slack.NewDirectoryClient(t.TempDir(), []map[string]any{
+		{"id": "U1", "tz": "UTC", "profile": map[string]any{"display_name": "alice"}},
+		{"id": "U2", "deleted": true, "profile": map[string]any{"real_name": "Bob Old"}, "enterprise_user": map[string]any{"id": "W2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := slack.NewUserProvider(client)
+	users.Prefetch(t.Context(), []map[string]any{{"user": "U1", "text": "thanks <@W2>"}})
+
+	for id, want := range map[string]string{"U1": "alice", "W2": "Bob Old (deactivated)", "U2": "Bob Old (deactivated)"} {
+		if got, _ := users.UsernameForID(id); got != want {
+			t.Errorf("UsernameForID(%s) = %q, want %q", id, got, want)
+		}
+	}
+	if locs := users.AuthorLocations(t.Context(), []map[string]any{{"user": "U1"}}); locs["U1"] == nil {
+		t.Errorf("AuthorLocations = %v, want U1's time zone from the directory", locs)
+	}
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Failure classes, for errors.Is. Errors from this package that fall in a class match
+// its sentinel: ErrAuthFailed, ErrNotFound, ErrRateLimited, or ErrNetwork.
+var (
+	// ErrNotFound matches a channel, user, message, or other object that does not exist
+	// (or is not visible to the token).
+	ErrNotFound = errors.New("not found")
+	// ErrNetwork matches a failure to reach Slack: connection errors and timeouts that
+	// persisted through retries, and an expired --timeout.
+	ErrNetwork = errors.New("network error")
+)
+
+// Slack error codes by failure class.
+var (
+	notFoundCodes = []string{
+		"channel_not_found", "user_not_found", "users_not_found", "message_not_found",
+		"thread_not_found", "file_not_found", "bot_not_found", "team_not_found",
+		"no_such_subteam", "canvas_not_found",
+	}
+	authCodes = []string{
+		"not_authed", "invalid_auth", "account_inactive", "token_revoked", "token_expired",
+	}
+)
+
+// classError puts an error in a failure class without changing its message.
+type classError struct {
+	err   error
+	class error
+}
+
+func (e *classError) Error() string { return e.err.Error() }
+
+func (e *classError) Unwrap() []error { return []error{e.err, e.class} }
+
+// withClass marks err as being in class (e.g., ErrNotFound).
+func withClass(err, class error) error {
+	if err == nil || errors.Is(err, class) {
+		return err
+	}
+	return &classError{err: err, class: class}
+}
+
+// isNetworkError reports whether err is a failure to reach Slack (see isTransient) or
+// an expired deadline.
+func isNetworkError(err error) bool {
+	return isTransient(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// codeClass returns the failure class of a Slack error code, or nil.
+func codeClass(code string) error {
+	switch {
+	case code == "ratelimited":
+		return ErrRateLimited
+	case slices.Contains(notFoundCodes, code):
+		return ErrNotFound
+	case slices.Contains(authCodes, code):
+		return ErrAuthFailed
+	}
+	return nil
+}
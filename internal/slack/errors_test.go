@@ -0,0 +1,57 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// notFoundAPI answers every call with channel_not_found.
+type notFoundAPI struct{}
+
+func (notFoundAPI) API(_ context.Context, method string, _ map[string]string) (map[string]any, error) {
+	return nil, &slack.APIError{Method: method, Code: "channel_not_found"}
+}
+
+func TestErrorClasses(t *testing.T) {
+	_, err := slack.GetChannelInfo(t.Context(), notFoundAPI{}, "C1")
+	if !errors.Is(err, slack.ErrNotFound) || err.Error() != "channel not found: C1" {
+		t.Errorf("GetChannelInfo error = %q, want ErrNotFound with its message unchanged", err)
+	}
+
+	for _, err := range []error{slack.ErrUserNotFound, slack.ErrMessageNotFound, slack.ErrUsergroupNotFound} {
+		if !errors.Is(err, slack.ErrNotFound) {
+			t.Errorf("%q is not ErrNotFound", err)
+		}
+	}
+	if got := slack.ErrUserNotFound.Error(); got != "user not found" {
+		t.Errorf("ErrUserNotFound = %q", got)
+	}
+
+	apiErr := &slack.APIError{Method: "auth.test", Code: "invalid_auth"}
+	if !errors.Is(apiErr, slack.ErrAuthFailed) || errors.Is(apiErr, slack.ErrNotFound) {
+		t.Errorf("invalid_auth classes: auth %v, not found %v", errors.Is(apiErr, slack.ErrAuthFailed), errors.Is(apiErr, slack.ErrNotFound))
+	}
+}
+
+func TestRequestError_Timeout(t *testing.T) {
+	cause := fmt.Errorf("timed out after 1ms (--timeout): %w", context.DeadlineExceeded)
+	ctx, cancel := context.WithTimeoutCause(t.Context(), time.Millisecond, cause)
+	defer cancel()
+	<-ctx.Done()
+
+	err := slack.RequestError(ctx, "conversations.history", ctx.Err())
+	if !errors.Is(err, slack.ErrNetwork) || err.Error() != "slack API conversations.history: timed out after 1ms (--timeout): context deadline exceeded" {
+		t.Errorf("RequestError = %q, want ErrNetwork naming --timeout", err)
+	}
+
+	canceled, cancelNow := context.WithCancel(t.Context())
+	cancelNow()
+	if err := slack.RequestError(canceled, "conversations.history", canceled.Err()); errors.Is(err, slack.ErrNetwork) {
+		t.Errorf("RequestError(canceled) = %q, want no ErrNetwork", err)
+	}
+}
@@ -0,0 +1,84 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/cache"
+)
+
+// NewRetryTransport exposes retryTransport to tests without sleeping between attempts.
+func NewRetryTransport(base http.RoundTripper, maxRetries int) http.RoundTripper {
+	t := newRetryTransport(base, maxRetries, 0)
+	t.wait = func(context.Context, time.Duration) error { return nil }
+	return t
+}
+
+// SuggestNames exposes suggestNames to tests.
+var SuggestNames = suggestNames
+
+// FuzzyMatch exposes fuzzyMatch to tests.
+var FuzzyMatch = fuzzyMatch
+
+// AvatarFileName exposes avatarFileName to tests.
+var AvatarFileName = avatarFileName
+
+// ResolveUserName exposes resolveUserName to tests.
+var ResolveUserName = resolveUserName
+
+// DescribeMembers exposes describeMembers to tests.
+var DescribeMembers = describeMembers
+
+// NewDirectoryClient returns a client with only a cached users.list directory (in dir),
+// for tests that must make no API calls: any call panics.
+func NewDirectoryClient(dir string, members []map[string]any) (*Client, error) {
+	c := &Client{users: cache.NewFileStore(dir, time.Hour)}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.users.Set(usersDirectoryKey, data)
+}
+
+// NewTraceTransport exposes traceTransport to tests.
+func NewTraceTransport(base http.RoundTripper) http.RoundTripper {
+	return &traceTransport{base: base}
+}
+
+// RequestError exposes requestError to tests.
+var RequestError = requestError
+
+// NewCachedClient returns a client with its channel, file and response caches in dir, whose
+// API calls are answered only from the response cache (see CacheResponse); others panic.
+func NewCachedClient(dir string) (*Client, error) {
+	channels, err := cache.Open(filepath.Join(dir, "channels.json"), time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	files, err := cache.Open(filepath.Join(dir, "files.json"), time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		channels:  channels,
+		files:     files,
+		responses: cache.NewFileStore(filepath.Join(dir, "responses"), time.Hour),
+	}, nil
+}
+
+// CacheResponse stores the response the client returns for method with params.
+func (c *Client) CacheResponse(method string, params map[string]string, resp map[string]any) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return c.responses.Set(responseCacheKey(method, params), data)
+}
+
+// CacheChannel caches a channel name, as resolving it does.
+func (c *Client) CacheChannel(name, id string) error {
+	return c.channels.Set(name, id)
+}
@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"github.com/sethrylan/slack-reader/internal/cache"
+	"golang.org/x/sync/errgroup"
+)
+
+// AddFileInfo fills in the files shared in messages with their files.info metadata, in
+// place, in addition to the stub history embeds: size, mimetype, title, is_external and
+// external_type (e.g., "gdrive"), and a preview of text files and snippets. Each file is
+// fetched once however many messages share it, and a Client caches the metadata for a
+// day between runs. Deleted files keep their stub.
+func AddFileInfo(ctx context.Context, client APIClient, messages []map[string]any) error {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		for _, f := range messageFiles(msg) {
+			if id, _ := f["id"].(string); id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	var files *cache.Store
+	if c, ok := client.(*Client); ok {
+		files = c.files
+	}
+
+	// Fetches write into their own slots; the messages are only updated after Wait.
+	infos := make([]map[string]any, len(ids))
+	var fetch []int // the ids not cached
+	for i, id := range ids {
+		if infos[i] = cachedFileInfo(files, id); infos[i] == nil {
+			fetch = append(fetch, i)
+		}
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for _, i := range fetch {
+		g.Go(func() (err error) {
+			infos[i], err = getFileInfo(gctx, client, ids[i])
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	fetched := make(map[string]string, len(fetch))
+	for _, i := range fetch {
+		if infos[i] == nil {
+			continue // deleted files are checked again next time
+		}
+		if data, err := json.Marshal(infos[i]); err == nil {
+			fetched[ids[i]] = string(data)
+		}
+	}
+	if err := files.SetMany(fetched); err != nil {
+		slog.Info("could not cache file info", "error", err)
+	}
+
+	byID := make(map[string]map[string]any, len(ids))
+	for i, id := range ids {
+		if infos[i] != nil {
+			byID[id] = infos[i]
+		}
+	}
+	for _, msg := range messages {
+		for _, f := range messageFiles(msg) {
+			id, _ := f["id"].(string)
+			if info := byID[id]; info != nil {
+				maps.Copy(f, info)
+			}
+		}
+	}
+	return nil
+}
+
+// messageFiles returns the file objects shared in a message.
+func messageFiles(msg map[string]any) []map[string]any {
+	files, _ := msg["files"].([]any)
+	var out []map[string]any
+	for _, f := range files {
+		if file, _ := f.(map[string]any); file != nil {
+			out = append(out, file)
+		}
+	}
+	return out
+}
+
+// cachedFileInfo returns a file's metadata from the files cache, or nil.
+func cachedFileInfo(files *cache.Store, id string) map[string]any {
+	data, ok := files.Get(id)
+	if !ok {
+		return nil
+	}
+	var file map[string]any
+	if err := json.Unmarshal([]byte(data), &file); err != nil {
+		return nil
+	}
+	return file
+}
+
+// getFileInfo fetches a file's metadata, or nil if the file can no longer be read.
+func getFileInfo(ctx context.Context, client APIClient, id string) (map[string]any, error) {
+	resp, err := client.API(ctx, "files.info", map[string]string{"file": id})
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.Code == "file_not_found" || apiErr.Code == "file_deleted") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("files.info: %w", err)
+	}
+	file, _ := resp["file"].(map[string]any)
+	return file, nil
+}
@@ -0,0 +1,94 @@
+package slack_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// filesAPI serves files.info for F1, and file_not_found for every other file.
+type filesAPI struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (m *filesAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	m.calls[params["file"]]++
+	m.mu.Unlock()
+	if params["file"] != "F1" {
+		return nil, &slack.APIError{Method: "files.info", Code: "file_not_found"}
+	}
+	return map[string]any{
+		"ok": true,
+		"file": map[string]any{
+			"id": "F1", "title": "notes.txt", "mimetype": "text/plain", "size": float64(42),
+			"is_external": false, "preview": "first line",
+		},
+	}, nil
+}
+
+func TestAddFileInfo(t *testing.T) {
+	mock := &filesAPI{calls: make(map[string]int)}
+	messages := []map[string]any{
+		{"ts": "1", "files": []any{map[string]any{"id": "F1", "mode": "hosted"}}},
+		{"ts": "2", "files": []any{map[string]any{"id": "F1"}, map[string]any{"id": "F2", "mode": "tombstone"}}},
+		{"ts": "3", "text": "no files"},
+	}
+
+	if err := slack.AddFileInfo(t.Context(), mock, messages); err != nil {
+		t.Fatal(err)
+	}
+
+	if mock.calls["F1"] != 1 || mock.calls["F2"] != 1 {
+		t.Errorf("got files.info calls %v, want one per file", mock.calls)
+	}
+	f1, _ := messages[0]["files"].([]any)[0].(map[string]any)
+	if f1["title"] != "notes.txt" || f1["size"] != float64(42) || f1["preview"] != "first line" || f1["mode"] != "hosted" {
+		t.Errorf("got file %v, want files.info metadata merged into the stub", f1)
+	}
+	shared, _ := messages[1]["files"].([]any)[0].(map[string]any)
+	if shared["mimetype"] != "text/plain" {
+		t.Errorf("got file %v in the second message, want it filled in too", shared)
+	}
+	deleted, _ := messages[1]["files"].([]any)[1].(map[string]any)
+	if len(deleted) != 2 || deleted["mode"] != "tombstone" {
+		t.Errorf("got deleted file %v, want its stub unchanged", deleted)
+	}
+}
+
+// File metadata fetched once is cached for later runs.
+func TestAddFileInfo_Cached(t *testing.T) {
+	dir := t.TempDir()
+	client, err := slack.NewCachedClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := map[string]any{"ok": true, "file": map[string]any{"id": "F1", "title": "notes.txt"}}
+	if err := client.CacheResponse("files.info", map[string]string{"file": "F1"}, resp); err != nil {
+		t.Fatal(err)
+	}
+	messages := []map[string]any{{"ts": "1", "files": []any{map[string]any{"id": "F1"}}}}
+	if err := slack.AddFileInfo(t.Context(), client, messages); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later run, without the response cache, makes no files.info call.
+	if err := os.RemoveAll(filepath.Join(dir, "responses")); err != nil {
+		t.Fatal(err)
+	}
+	if client, err = slack.NewCachedClient(dir); err != nil {
+		t.Fatal(err)
+	}
+	messages = []map[string]any{{"ts": "2", "files": []any{map[string]any{"id": "F1"}}}}
+	if err := slack.AddFileInfo(t.Context(), client, messages); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := messages[0]["files"].([]any)[0].(map[string]any); f["title"] != "notes.txt" {
+		t.Errorf("got file %v, want the cached metadata", f)
+	}
+}
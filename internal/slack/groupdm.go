@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// IsGroupDMName reports whether a conversation name is a group DM's, e.g.
+// "mpdm-alice--bob--carol-1".
+func IsGroupDMName(name string) bool {
+	return strings.HasPrefix(name, "mpdm-")
+}
+
+// GroupDMName returns the display names of a group DM's participants, e.g.
+// "alice, bob, carol", to show in place of its mpdm-... name or ID.
+func GroupDMName(ctx context.Context, client APIClient, users *UserProvider, channelID string) (string, error) {
+	members, err := ChannelMemberIDs(ctx, client, channelID)
+	if err != nil {
+		return "", err
+	}
+	people := make([]map[string]any, len(members))
+	for i, id := range members {
+		people[i] = map[string]any{"user": id}
+	}
+	users.Prefetch(ctx, people)
+	return participantNames(users, members), nil
+}
+
+// AddGroupDMNames sets display_name, the participants' display names, on the group DMs
+// (is_mpim) among conversations, in place.
+func AddGroupDMNames(ctx context.Context, client APIClient, users *UserProvider, conversations []map[string]any) error {
+	// Fetches write into their own slots; the conversations are only updated after Wait.
+	members := make([][]string, len(conversations))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for i, c := range conversations {
+		id, _ := c["id"].(string)
+		if mpim, _ := c["is_mpim"].(bool); !mpim || id == "" {
+			continue
+		}
+		g.Go(func() (err error) {
+			members[i], err = ChannelMemberIDs(gctx, client, id)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	var all []map[string]any
+	for _, ids := range members {
+		for _, id := range ids {
+			all = append(all, map[string]any{"user": id})
+		}
+	}
+	users.Prefetch(ctx, all)
+	for i, c := range conversations {
+		if members[i] != nil {
+			c["display_name"] = participantNames(users, members[i])
+		}
+	}
+	return nil
+}
+
+// participantNames joins users' display names with commas.
+func participantNames(users *UserProvider, ids []string) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i], _ = users.UsernameForID(id)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,52 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// groupDMAPI serves the members of group DM G1, and users.info.
+type groupDMAPI struct{}
+
+func (groupDMAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "conversations.members":
+		if params["channel"] != "G1" {
+			return nil, fmt.Errorf("unexpected channel %s", params["channel"])
+		}
+		return map[string]any{"ok": true, "members": []any{"U1", "U2", "U3"}}, nil
+	case "users.info":
+		names := map[string]string{"U1": "alice", "U2": "bob", "U3": "carol"}
+		return map[string]any{"ok": true, "user": map[string]any{"id": params["user"], "name": names[params["user"]]}}, nil
+	}
+	return nil, fmt.Errorf("unexpected method %s", method)
+}
+
+func TestAddGroupDMNames(t *testing.T) {
+	conversations := []map[string]any{
+		{"id": "C1", "name": "general"},
+		{"id": "G1", "name": "mpdm-alice--bob--carol-1", "is_mpim": true},
+	}
+	mock := groupDMAPI{}
+	if err := slack.AddGroupDMNames(t.Context(), mock, slack.NewUserProvider(mock), conversations); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conversations[1]["display_name"]; got != "alice, bob, carol" {
+		t.Errorf("display_name = %v, want participants", got)
+	}
+	if _, ok := conversations[0]["display_name"]; ok {
+		t.Errorf("channel gained display_name %v", conversations[0]["display_name"])
+	}
+
+	name, err := slack.GroupDMName(t.Context(), mock, slack.NewUserProvider(mock), "G1")
+	if err != nil || name != "alice, bob, carol" {
+		t.Errorf("GroupDMName = %q, %v", name, err)
+	}
+	if !slack.IsGroupDMName("mpdm-alice--bob--carol-1") || slack.IsGroupDMName("general") {
+		t.Error("IsGroupDMName misclassified a name")
+	}
+}
@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"time"
+)
+
+// Huddle describes a huddle or call, as recorded by its message in history.
+type Huddle struct {
+	Kind         string    // "Huddle" or "Call"
+	CreatedBy    string    // user ID
+	Participants []string  // user IDs of everyone who joined
+	Start        time.Time // zero if unknown
+	End          time.Time // zero while ongoing
+}
+
+// Duration returns how long the huddle lasted, or 0 if it is ongoing or its times are unknown.
+func (h *Huddle) Duration() time.Duration {
+	if h.Start.IsZero() || h.End.IsZero() {
+		return 0
+	}
+	return h.End.Sub(h.Start)
+}
+
+// ParseHuddle extracts a huddle from a huddle_thread message (its "room") or a call
+// from a message with a call block. ok is false for other messages.
+func ParseHuddle(msg map[string]any) (h *Huddle, ok bool) {
+	if room, _ := msg["room"].(map[string]any); room != nil {
+		h = &Huddle{Kind: "Huddle"}
+		h.CreatedBy, _ = room["created_by"].(string)
+		// participant_history has everyone who joined; participants only those still there.
+		h.Participants = stringList(room["participant_history"])
+		if len(h.Participants) == 0 {
+			h.Participants = stringList(room["participants"])
+		}
+		h.Start, h.End = unixTimes(room)
+		if h.CreatedBy == "" {
+			h.CreatedBy, _ = msg["user"].(string)
+		}
+		return h, true
+	}
+
+	blocks, _ := msg["blocks"].([]any)
+	for _, b := range blocks {
+		block, _ := b.(map[string]any)
+		if kind, _ := block["type"].(string); kind != "call" {
+			continue
+		}
+		call, _ := block["call"].(map[string]any)
+		v1, _ := call["v1"].(map[string]any)
+		if v1 == nil {
+			continue
+		}
+		h = &Huddle{Kind: "Call"}
+		h.CreatedBy, _ = v1["created_by"].(string)
+		if h.CreatedBy == "" {
+			h.CreatedBy, _ = msg["user"].(string)
+		}
+		all, _ := v1["all_participants"].([]any)
+		for _, p := range all {
+			participant, _ := p.(map[string]any)
+			if id, _ := participant["slack_id"].(string); id != "" {
+				h.Participants = append(h.Participants, id)
+			}
+		}
+		h.Start, h.End = unixTimes(v1)
+		return h, true
+	}
+	return nil, false
+}
+
+// IsHuddle reports whether a message records a huddle or call.
+func IsHuddle(msg map[string]any) bool {
+	_, ok := ParseHuddle(msg)
+	return ok
+}
+
+// unixTimes reads the date_start and date_end (Unix seconds) of a huddle room or call.
+func unixTimes(m map[string]any) (start, end time.Time) {
+	if s, _ := m["date_start"].(float64); s > 0 {
+		start = time.Unix(int64(s), 0)
+	}
+	if e, _ := m["date_end"].(float64); e > 0 {
+		end = time.Unix(int64(e), 0)
+	}
+	return start, end
+}
+
+func stringList(v any) []string {
+	items, _ := v.([]any)
+	var out []string
+	for _, it := range items {
+		if s, _ := it.(string); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package slack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestParseHuddle(t *testing.T) {
+	huddle := map[string]any{
+		"subtype": "huddle_thread",
+		"user":    "U1",
+		"room": map[string]any{
+			"created_by":          "U1",
+			"date_start":          float64(1770165000),
+			"date_end":            float64(1770166920),
+			"participants":        []any{},
+			"participant_history": []any{"U1", "U2", "U3", "U4"},
+		},
+	}
+	h, ok := slack.ParseHuddle(huddle)
+	if !ok {
+		t.Fatal("huddle_thread message not recognized")
+	}
+	if h.Kind != "Huddle" || h.CreatedBy != "U1" || len(h.Participants) != 4 || h.Duration() != 32*time.Minute {
+		t.Errorf("got %+v (duration %s)", h, h.Duration())
+	}
+
+	call := map[string]any{
+		"user": "U2",
+		"blocks": []any{map[string]any{
+			"type": "call",
+			"call": map[string]any{"v1": map[string]any{
+				"date_start":       float64(1770165000),
+				"all_participants": []any{map[string]any{"slack_id": "U2"}, map[string]any{"slack_id": "U3"}},
+			}},
+		}},
+	}
+	h, ok = slack.ParseHuddle(call)
+	if !ok {
+		t.Fatal("call message not recognized")
+	}
+	if h.Kind != "Call" || h.CreatedBy != "U2" || len(h.Participants) != 2 || !h.End.IsZero() {
+		t.Errorf("got %+v", h)
+	}
+
+	if slack.IsHuddle(map[string]any{"user": "U1", "text": "hi"}) {
+		t.Error("plain message recognized as a huddle")
+	}
+}
@@ -0,0 +1,177 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Guest kinds, from users.info's is_restricted and is_ultra_restricted.
+const (
+	GuestMultiChannel  = "multi_channel"  // may be in several channels
+	GuestSingleChannel = "single_channel" // may be in one channel
+)
+
+// Workspace roles, from users.list's is_primary_owner, is_owner, and is_admin.
+const (
+	RolePrimaryOwner = "primary_owner"
+	RoleOwner        = "owner"
+	RoleAdmin        = "admin"
+)
+
+// Member is a member of a conversation, user group, or the workspace.
+type Member struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	RealName    string `json:"real_name,omitempty"`
+	Title       string `json:"title,omitempty"`
+	TeamID      string `json:"team_id,omitempty"`
+	Guest       string `json:"guest,omitempty"` // GuestMultiChannel or GuestSingleChannel
+	Role        string `json:"role,omitempty"`  // RolePrimaryOwner, RoleOwner, or RoleAdmin
+	IsBot       bool   `json:"is_bot,omitempty"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+}
+
+// GuestKind returns whether a user object (or a message's user_profile) is a guest:
+// GuestSingleChannel, GuestMultiChannel, or "" for full members.
+func GuestKind(user map[string]any) string {
+	if ultra, _ := user["is_ultra_restricted"].(bool); ultra {
+		return GuestSingleChannel
+	}
+	if restricted, _ := user["is_restricted"].(bool); restricted {
+		return GuestMultiChannel
+	}
+	return ""
+}
+
+// UserRole returns a user's workspace role: RolePrimaryOwner, RoleOwner, RoleAdmin, or
+// "" for other members. Owners are admins too; the highest role is returned.
+func UserRole(user map[string]any) string {
+	for _, r := range []struct{ field, role string }{
+		{"is_primary_owner", RolePrimaryOwner},
+		{"is_owner", RoleOwner},
+		{"is_admin", RoleAdmin},
+	} {
+		if v, _ := user[r.field].(bool); v {
+			return r.role
+		}
+	}
+	return ""
+}
+
+// NewMember describes a user object (e.g., a users.list member) as a Member.
+func NewMember(user map[string]any) Member {
+	e := NewDirectoryEntry(user)
+	m := Member{
+		ID:          e.ID,
+		Name:        e.Name,
+		DisplayName: e.DisplayName,
+		RealName:    e.RealName,
+		Title:       e.Title,
+		Guest:       GuestKind(user),
+		Role:        UserRole(user),
+		IsBot:       e.IsBot,
+		Deactivated: e.Deleted,
+	}
+	m.TeamID, _ = user["team_id"].(string)
+	return m
+}
+
+// Admins returns the active workspace admins and owners in a users.list directory:
+// the primary owner first, then owners, then admins, each by username.
+func Admins(users []map[string]any) []Member {
+	rank := map[string]int{RolePrimaryOwner: 0, RoleOwner: 1, RoleAdmin: 2}
+	var admins []Member
+	for _, u := range users {
+		if m := NewMember(u); m.Role != "" && !m.Deactivated {
+			admins = append(admins, m)
+		}
+	}
+	sort.SliceStable(admins, func(i, j int) bool {
+		if rank[admins[i].Role] != rank[admins[j].Role] {
+			return rank[admins[i].Role] < rank[admins[j].Role]
+		}
+		return admins[i].Name < admins[j].Name
+	})
+	return admins
+}
+
+// ChannelMembers lists a conversation's members, with whether each is a guest. Members
+// are looked up in the cached users.list directory, and with users.info when not found
+// there (e.g., Slack Connect users from other organizations).
+func ChannelMembers(ctx context.Context, client *Client, channelID string) ([]Member, error) {
+	ids, err := ChannelMemberIDs(ctx, client, channelID)
+	if err != nil {
+		return nil, err
+	}
+	directory, err := ListUsers(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return describeMembers(ctx, client, ids, directory)
+}
+
+// ChannelMemberIDs pages through conversations.members, whose items are bare IDs.
+func ChannelMemberIDs(ctx context.Context, client APIClient, channelID string) ([]string, error) {
+	var ids []string
+	params := map[string]string{"channel": channelID, "limit": "1000"}
+	for {
+		resp, err := client.API(ctx, "conversations.members", params)
+		if err != nil {
+			return nil, fmt.Errorf("conversations.members: %w", err)
+		}
+		list, _ := resp["members"].([]any)
+		for _, m := range list {
+			if id, _ := m.(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		meta, _ := resp["response_metadata"].(map[string]any)
+		next, _ := meta["next_cursor"].(string)
+		if next == "" {
+			return ids, nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// describeMembers builds the Members for ids from directory, fetching users not in it.
+func describeMembers(ctx context.Context, client APIClient, ids []string, directory []map[string]any) ([]Member, error) {
+	byID := make(map[string]map[string]any, len(directory))
+	for _, u := range directory {
+		if id, _ := u["id"].(string); id != "" {
+			byID[id] = u
+		}
+	}
+
+	// Fetches write into their own slots; users is only read after Wait.
+	users := make([]map[string]any, len(ids))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for i, id := range ids {
+		if u, ok := byID[id]; ok {
+			users[i] = u
+			continue
+		}
+		g.Go(func() error {
+			u, err := GetUser(ctx, client, id)
+			if err != nil {
+				return err
+			}
+			users[i] = u
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, len(users))
+	for i, u := range users {
+		members[i] = NewMember(u)
+	}
+	return members, nil
+}
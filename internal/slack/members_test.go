@@ -0,0 +1,83 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestGuestKind(t *testing.T) {
+	tests := []struct {
+		user map[string]any
+		want string
+	}{
+		{map[string]any{}, ""},
+		{map[string]any{"is_restricted": true}, slack.GuestMultiChannel},
+		{map[string]any{"is_restricted": true, "is_ultra_restricted": true}, slack.GuestSingleChannel},
+	}
+	for _, tt := range tests {
+		if got := slack.GuestKind(tt.user); got != tt.want {
+			t.Errorf("GuestKind(%v) = %q, want %q", tt.user, got, tt.want)
+		}
+	}
+}
+
+// guestAPI serves users.info for an external guest missing from the directory.
+type guestAPI struct{ calls []string }
+
+func (a *guestAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	a.calls = append(a.calls, params["user"])
+	if method != "users.info" || params["user"] != "U3" {
+		return nil, fmt.Errorf("unexpected %s %v", method, params)
+	}
+	return map[string]any{"ok": true, "user": map[string]any{
+		"id": "U3", "name": "vendor", "team_id": "T2", "is_restricted": true, "is_ultra_restricted": true,
+	}}, nil
+}
+
+func TestDescribeMembers(t *testing.T) {
+	directory := []map[string]any{
+		{"id": "U1", "name": "alice", "team_id": "T1"},
+		{"id": "U2", "name": "contractor", "team_id": "T1", "is_restricted": true},
+	}
+	api := &guestAPI{}
+	members, err := slack.DescribeMembers(t.Context(), api, []string{"U1", "U2", "U3"}, directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []slack.Member{
+		{ID: "U1", Name: "alice", TeamID: "T1"},
+		{ID: "U2", Name: "contractor", TeamID: "T1", Guest: slack.GuestMultiChannel},
+		{ID: "U3", Name: "vendor", TeamID: "T2", Guest: slack.GuestSingleChannel},
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("members[%d] = %+v, want %+v", i, members[i], want[i])
+		}
+	}
+	if len(api.calls) != 1 {
+		t.Errorf("users.info calls = %v, want only U3", api.calls)
+	}
+}
+
+func TestAdmins(t *testing.T) {
+	users := []map[string]any{
+		{"id": "U1", "name": "zoe", "is_admin": true},
+		{"id": "U2", "name": "alice"},
+		{"id": "U3", "name": "owner", "is_admin": true, "is_owner": true, "is_primary_owner": true},
+		{"id": "U4", "name": "bob", "is_admin": true},
+		{"id": "U5", "name": "carol", "is_admin": true, "is_owner": true},
+		{"id": "U6", "name": "gone", "is_admin": true, "deleted": true},
+	}
+	var got []string
+	for _, m := range slack.Admins(users) {
+		got = append(got, m.Name+":"+m.Role)
+	}
+	want := []string{"owner:primary_owner", "carol:owner", "bob:admin", "zoe:admin"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Admins = %v, want %v", got, want)
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
+	"net/url"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // NormalizeTimestamp converts a Slack timestamp to the canonical
@@ -24,14 +29,185 @@ func NormalizeTimestamp(ts string) string {
 	return ts
 }
 
+// ParseTimestamp converts a Slack timestamp to a time.
+func ParseTimestamp(ts string) (time.Time, error) {
+	ts = NormalizeTimestamp(ts)
+	secs, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", ts)
+	}
+	var us int64
+	if frac != "" {
+		frac = (frac + "000000")[:6]
+		if us, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", ts)
+		}
+	}
+	return time.Unix(s, us*int64(time.Microsecond)), nil
+}
+
+// ParseTimeSpec parses a point in time given as a duration before now ("90m", "24h", "7d"),
+// a date ("2026-01-31", midnight UTC), an RFC 3339 time, or a Slack timestamp.
+func ParseTimeSpec(spec string, now time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	if days, ok := strings.CutSuffix(spec, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(spec); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.DateOnly, spec); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, spec); err == nil {
+		return t, nil
+	}
+	if t, err := ParseTimestamp(spec); err == nil && strings.Contains(NormalizeTimestamp(spec), ".") {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want e.g. 24h, 7d, 2026-01-31, or a Slack timestamp)", spec)
+}
+
+// FormatTimestamp converts a time to a Slack "seconds.microseconds" timestamp.
+func FormatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
+
+// EditedAt returns when a message was last edited, from its "edited" field.
+// ok is false for messages that were never edited.
+func EditedAt(msg map[string]any) (t time.Time, ok bool) {
+	edited, _ := msg["edited"].(map[string]any)
+	ts, _ := edited["ts"].(string)
+	if ts == "" {
+		return time.Time{}, false
+	}
+	t, err := ParseTimestamp(ts)
+	return t, err == nil
+}
+
+// MarkEdited adds "edited_at" (RFC 3339, UTC) to each edited message in place,
+// so edits are visible without decoding Slack timestamps.
+func MarkEdited(messages []map[string]any) {
+	for _, msg := range messages {
+		if t, ok := EditedAt(msg); ok {
+			msg["edited_at"] = t.UTC().Format(time.RFC3339)
+		}
+	}
+}
+
+// linkPattern matches a URL in Slack's message markup: <https://example.com> or
+// <https://example.com|label>.
+var linkPattern = regexp.MustCompile(`<(https?://[^|>]+)(?:\|[^>]*)?>`)
+
+// HasFiles reports whether a message shares files.
+func HasFiles(msg map[string]any) bool {
+	files, _ := msg["files"].([]any)
+	return len(files) > 0
+}
+
+// HasLinks reports whether a message's text, or one of its attachments (e.g., an
+// unfurled link preview), links to a web page.
+func HasLinks(msg map[string]any) bool {
+	if text, _ := msg["text"].(string); linkPattern.MatchString(text) {
+		return true
+	}
+	attachments, _ := msg["attachments"].([]any)
+	for _, a := range attachments {
+		att, _ := a.(map[string]any)
+		fromURL, _ := att["from_url"].(string)
+		titleLink, _ := att["title_link"].(string)
+		text, _ := att["text"].(string)
+		if fromURL != "" || titleLink != "" || linkPattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// systemSubtypes are the subtypes of messages Slack posts about the conversation itself
+// (joins, leaves, renames, topic changes, integrations added) rather than by people.
+var systemSubtypes = map[string]bool{
+	"channel_join": true, "channel_leave": true, "channel_topic": true, "channel_purpose": true,
+	"channel_name": true, "channel_archive": true, "channel_unarchive": true,
+	"channel_convert_to_private": true, "channel_convert_to_public": true,
+	"group_join": true, "group_leave": true, "group_topic": true, "group_purpose": true,
+	"group_name": true, "group_archive": true, "group_unarchive": true,
+	"bot_add": true, "bot_remove": true, "pinned_item": true, "unpinned_item": true,
+}
+
+// IsSystemMessage reports whether a message is a system message, like channel_join,
+// channel_topic, or bot_add, rather than one a person or bot wrote.
+func IsSystemMessage(msg map[string]any) bool {
+	subtype, _ := msg["subtype"].(string)
+	return systemSubtypes[subtype]
+}
+
+// MatchSubtypes returns a filter that keeps messages whose subtype is one of include
+// (when include is not empty) and not one of exclude. Plain messages have no subtype, so
+// include leaves them out.
+func MatchSubtypes(include, exclude []string) func(map[string]any) bool {
+	return func(msg map[string]any) bool {
+		subtype, _ := msg["subtype"].(string)
+		if len(include) > 0 && !slices.Contains(include, subtype) {
+			return false
+		}
+		return !slices.Contains(exclude, subtype)
+	}
+}
+
+// Permalink composes the web URL of a message. For thread replies (threadTS set and
+// different from ts), the thread context is included so Slack opens the reply in its thread.
+func Permalink(domain, channelID, ts, threadTS string) string {
+	ts = NormalizeTimestamp(ts)
+	link := fmt.Sprintf("https://%s.slack.com/archives/%s/p%s", domain, channelID, strings.ReplaceAll(ts, ".", ""))
+	if threadTS != "" && threadTS != ts {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", NormalizeTimestamp(threadTS), channelID)
+	}
+	return link
+}
+
+// GetPermalink asks Slack for a message's permalink with chat.getPermalink. Unlike Permalink,
+// it is exact for Enterprise Grid workspaces, whose links use the organization's domain.
+func GetPermalink(ctx context.Context, client APIClient, channelID string, ts string) (string, error) {
+	resp, err := client.API(ctx, "chat.getPermalink", map[string]string{
+		"channel":    channelID,
+		"message_ts": NormalizeTimestamp(ts),
+	})
+	if err != nil {
+		return "", fmt.Errorf("chat.getPermalink: %w", err)
+	}
+	link, _ := resp["permalink"].(string)
+	if link == "" {
+		return "", errors.New("chat.getPermalink: no permalink in response")
+	}
+	return link, nil
+}
+
+// AppLink composes a slack:// URI that opens a channel in Slack Desktop.
+// Desktop deep links address channels, not individual messages.
+func AppLink(teamID, channelID string) string {
+	return fmt.Sprintf("slack://channel?team=%s&id=%s", url.QueryEscape(teamID), url.QueryEscape(channelID))
+}
+
+// ErrMessageNotFound is returned when no message exists at the requested timestamp.
+var ErrMessageNotFound = fmt.Errorf("message %w", ErrNotFound)
+
 // MessageResult represents a single message fetch result.
 type MessageResult struct {
+	Channel string         `json:"channel,omitempty"`
 	Message map[string]any `json:"message"`
 	Thread  map[string]any `json:"thread,omitempty"`
 }
 
 // GetMessage fetches a single message by channel and timestamp.
-func GetMessage(ctx context.Context, client APIClient, channelID string, ts string) (*MessageResult, error) {
+//
+// conversations.history only returns top-level messages, so when the lookup misses, the
+// message is fetched from conversations.replies instead. threadTS (the parent's timestamp)
+// is optional: Slack accepts the timestamp of any message in a thread in its place.
+func GetMessage(ctx context.Context, client APIClient, channelID string, ts string, threadTS string) (*MessageResult, error) {
 	ts = NormalizeTimestamp(ts)
 	resp, err := client.API(ctx, "conversations.history", map[string]string{
 		"channel":   channelID,
@@ -46,7 +222,7 @@ func GetMessage(ctx context.Context, client APIClient, channelID string, ts stri
 
 	messages, _ := resp["messages"].([]any)
 	if len(messages) == 0 {
-		return nil, fmt.Errorf("message not found at ts=%s", ts)
+		return getReply(ctx, client, channelID, ts, threadTS)
 	}
 
 	msg, _ := messages[0].(map[string]any)
@@ -54,137 +230,342 @@ func GetMessage(ctx context.Context, client APIClient, channelID string, ts stri
 		return nil, errors.New("invalid message format")
 	}
 
+	return newMessageResult(msg), nil
+}
+
+// newMessageResult wraps a top-level message, including thread metadata if it has replies.
+func newMessageResult(msg map[string]any) *MessageResult {
 	result := &MessageResult{Message: msg}
 
-	// If message has replies, include thread metadata
 	replyCount, _ := msg["reply_count"].(float64)
 	if replyCount > 0 {
 		threadTS, _ := msg["thread_ts"].(string)
 		if threadTS == "" {
-			threadTS = ts
+			threadTS, _ = msg["ts"].(string)
 		}
 		result.Thread = map[string]any{
 			"ts":     threadTS,
 			"length": int(replyCount),
 		}
 	}
-
-	return result, nil
+	return result
 }
 
-// ListChannelHistory fetches recent messages from a channel, paginated.
-func ListChannelHistory(ctx context.Context, client APIClient, channelID string, limit int) ([]map[string]any, error) {
-	unlimited := limit <= 0
-	if unlimited {
-		limit = 0
+// IncludeThread replaces the {ts, length} thread stub of a result with the whole
+// conversation: result.Thread["messages"] holds the parent followed by every reply.
+// Results without a thread are left unchanged.
+func IncludeThread(ctx context.Context, client APIClient, channelID string, result *MessageResult) error {
+	threadTS, _ := result.Thread["ts"].(string)
+	if threadTS == "" {
+		return nil
 	}
+	messages, err := CollectMessages(IterThread(ctx, client, channelID, threadTS, HistoryOptions{}))
+	if err != nil {
+		return err
+	}
+	result.Thread["messages"] = messages
+	return nil
+}
 
-	var allMessages []map[string]any
-	cursor := ""
-
-	for {
-		// Fetch up to 200 per page (Slack API max)
-		pageSize := 200
-		if !unlimited && limit-len(allMessages) < pageSize {
-			pageSize = limit - len(allMessages)
-		}
-		if pageSize <= 0 {
-			break
-		}
+// batchWindow is the widest gap between timestamps that GetMessages fetches in one
+// conversations.history window; farther-apart timestamps start a new window.
+const batchWindow = time.Hour
 
-		params := map[string]string{
-			"channel": channelID,
-			"limit":   strconv.Itoa(pageSize),
+// GetMessages fetches several messages from one channel, grouping nearby timestamps into
+// shared conversations.history windows. Results follow the order of tss. Timestamps not
+// found in channel history (e.g., thread replies) are looked up individually with GetMessage.
+func GetMessages(ctx context.Context, client APIClient, channelID string, tss []string) ([]*MessageResult, error) {
+	found := make(map[string]*MessageResult)
+	for _, window := range groupTimestamps(tss, batchWindow) {
+		wanted := make(map[string]bool, len(window))
+		for _, ts := range window {
+			wanted[ts] = true
 		}
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-
-		resp, err := client.API(ctx, "conversations.history", params)
-		if err != nil {
-			return nil, fmt.Errorf("conversations.history: %w", err)
+		opts := HistoryOptions{Oldest: window[0], Latest: window[len(window)-1], Inclusive: true}
+		for msg, err := range IterChannelHistory(ctx, client, channelID, opts) {
+			if err != nil {
+				return nil, err
+			}
+			ts, _ := msg["ts"].(string)
+			if !wanted[ts] {
+				continue
+			}
+			found[ts] = newMessageResult(msg)
+			delete(wanted, ts)
+			if len(wanted) == 0 {
+				break
+			}
 		}
+	}
 
-		messages, _ := resp["messages"].([]any)
-		for _, m := range messages {
-			msg, _ := m.(map[string]any)
-			if msg != nil {
-				allMessages = append(allMessages, msg)
+	results := make([]*MessageResult, 0, len(tss))
+	for _, ts := range tss {
+		ts = NormalizeTimestamp(ts)
+		result, ok := found[ts]
+		if !ok {
+			var err error
+			if result, err = GetMessage(ctx, client, channelID, ts, ""); err != nil {
+				return nil, err
 			}
+			found[ts] = result
 		}
+		results = append(results, result)
+	}
+	return results, nil
+}
 
-		// Stop if we've reached the requested limit.
-		if !unlimited && len(allMessages) >= limit {
-			allMessages = allMessages[:limit]
-			break
+// groupTimestamps sorts and de-duplicates timestamps, splitting them wherever consecutive
+// timestamps are more than gap apart. Unparseable timestamps are dropped.
+func groupTimestamps(tss []string, gap time.Duration) [][]string {
+	type stamp struct {
+		ts string
+		t  time.Time
+	}
+	var stamps []stamp
+	seen := make(map[string]bool)
+	for _, ts := range tss {
+		ts = NormalizeTimestamp(ts)
+		t, err := ParseTimestamp(ts)
+		if err != nil || seen[ts] {
+			continue
 		}
+		seen[ts] = true
+		stamps = append(stamps, stamp{ts, t})
+	}
+	sort.Slice(stamps, func(i, j int) bool { return stamps[i].t.Before(stamps[j].t) })
 
-		meta, _ := resp["response_metadata"].(map[string]any)
-		next, _ := meta["next_cursor"].(string)
-		if next == "" {
-			break
+	var groups [][]string
+	for i, s := range stamps {
+		if i == 0 || s.t.Sub(stamps[i-1].t) > gap {
+			groups = append(groups, nil)
 		}
-		cursor = next
+		groups[len(groups)-1] = append(groups[len(groups)-1], s.ts)
 	}
+	return groups
+}
 
-	// Sort chronologically (oldest first)
-	sort.Slice(allMessages, func(i, j int) bool {
-		tsI, _ := allMessages[i]["ts"].(string)
-		tsJ, _ := allMessages[j]["ts"].(string)
-		return tsI < tsJ
-	})
+// contextWindow is the first span after a message that MessageContext searches for the
+// messages following it; the span doubles until it holds enough of them.
+const contextWindow = time.Hour
 
-	return allMessages, nil
-}
+// MessageContext returns the top-level message at ts with up to before messages preceding
+// it and up to after messages following it, oldest first. The surrounding messages are
+// marked "context": true, as FilterMessagesContext marks them.
+//
+// History lists newest first, so the preceding messages are the first page of the window
+// ending at ts, while the following ones are found by messagesAfter.
+func MessageContext(ctx context.Context, client APIClient, channelID string, ts string, before, after int) ([]map[string]any, error) {
+	ts = NormalizeTimestamp(ts)
+	at, err := ParseTimestamp(ts)
+	if err != nil {
+		return nil, err
+	}
 
-// ListThread fetches all replies in a thread, paginated.
-func ListThread(ctx context.Context, client APIClient, channelID string, threadTS string, limit int) ([]map[string]any, error) {
-	threadTS = NormalizeTimestamp(threadTS)
-	var allMessages []map[string]any
-	cursor := ""
+	earlier, err := CollectMessages(IterChannelHistory(ctx, client, channelID, HistoryOptions{
+		Latest: ts, Inclusive: true, Limit: max(before, 0) + 1,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(earlier) == 0 || earlier[len(earlier)-1]["ts"] != ts {
+		return nil, fmt.Errorf("%w at ts=%s in channel history", ErrMessageNotFound, ts)
+	}
 
-	for {
-		params := map[string]string{
-			"channel": channelID,
-			"ts":      threadTS,
-			"limit":   "200",
+	var later []map[string]any
+	if after > 0 {
+		if later, err = messagesAfter(ctx, client, channelID, ts, at, after); err != nil {
+			return nil, err
 		}
-		if cursor != "" {
-			params["cursor"] = cursor
+	}
+
+	messages := append(earlier, later...)
+	for _, msg := range messages {
+		if msg["ts"] != ts {
+			msg["context"] = true
 		}
+	}
+	return messages, nil
+}
 
-		resp, err := client.API(ctx, "conversations.replies", params)
+// messagesAfter returns up to n messages following ts (at), oldest first. It lists windows
+// starting at ts, doubling their span until one holds n messages. A window that would
+// reach the present is never listed whole: only its newest n messages are. If there are
+// fewer, they are all that follow ts; otherwise the messages wanted are older, and the
+// search goes on below the oldest of them.
+func messagesAfter(ctx context.Context, client APIClient, channelID, ts string, at time.Time, n int) ([]map[string]any, error) {
+	var above []map[string]any // the newest n messages listed, oldest first, all after bound
+	bound, boundTime := "", time.Now()
+	for span := contextWindow; ; span *= 2 {
+		opts := HistoryOptions{Oldest: ts, Latest: bound, Limit: n}
+		if end := at.Add(span); end.Before(boundTime) {
+			opts.Latest, opts.Limit = FormatTimestamp(end), 0
+		}
+		window, err := CollectMessages(IterChannelHistory(ctx, client, channelID, opts))
 		if err != nil {
-			return nil, fmt.Errorf("conversations.replies: %w", err)
+			return nil, err
+		}
+		switch {
+		case opts.Limit == 0 && len(window) >= n:
+			return window[:n], nil
+		case opts.Limit == 0:
+			continue // too few in the window: widen it
+		case len(window) < n:
+			// Every message between ts and bound, then the earliest of those above it.
+			return append(window, above[:min(len(above), n-len(window))]...), nil
 		}
+		above = window
+		bound, _ = window[0]["ts"].(string)
+		if boundTime, err = ParseTimestamp(bound); err != nil {
+			return nil, err
+		}
+	}
+}
 
-		messages, _ := resp["messages"].([]any)
-		for _, m := range messages {
-			msg, _ := m.(map[string]any)
-			if msg != nil {
-				allMessages = append(allMessages, msg)
-			}
+// getReply fetches a thread reply by timestamp. The thread metadata comes from the
+// parent, which conversations.replies always returns first.
+func getReply(ctx context.Context, client APIClient, channelID string, ts string, threadTS string) (*MessageResult, error) {
+	parentTS := ts
+	if threadTS != "" {
+		parentTS = NormalizeTimestamp(threadTS)
+	}
+	resp, err := client.API(ctx, "conversations.replies", map[string]string{
+		"channel":   channelID,
+		"ts":        parentTS,
+		"latest":    ts,
+		"oldest":    ts,
+		"inclusive": "true",
+		"limit":     "2",
+	})
+	if err != nil {
+		// Slack reports an unknown ts as thread_not_found; surface it as a missing message.
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "thread_not_found" {
+			return nil, fmt.Errorf("%w at ts=%s", ErrMessageNotFound, ts)
 		}
+		return nil, fmt.Errorf("conversations.replies: %w", err)
+	}
 
-		meta, _ := resp["response_metadata"].(map[string]any)
-		next, _ := meta["next_cursor"].(string)
-		if next == "" {
-			break
+	messages, _ := resp["messages"].([]any)
+	var parent, reply map[string]any
+	for _, m := range messages {
+		msg, _ := m.(map[string]any)
+		if msg == nil {
+			continue
+		}
+		msgTS, _ := msg["ts"].(string)
+		msgThreadTS, _ := msg["thread_ts"].(string)
+		if parent == nil && msgTS == msgThreadTS {
+			parent = msg
+		}
+		if msgTS == ts {
+			reply = msg
 		}
-		cursor = next
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("%w at ts=%s", ErrMessageNotFound, ts)
+	}
 
-		if limit > 0 && len(allMessages) >= limit {
-			allMessages = allMessages[:limit]
-			break
+	result := &MessageResult{Message: reply}
+	if replyThreadTS, _ := reply["thread_ts"].(string); replyThreadTS != "" {
+		result.Thread = map[string]any{"ts": replyThreadTS}
+		if replyCount, _ := parent["reply_count"].(float64); replyCount > 0 {
+			result.Thread["length"] = int(replyCount)
 		}
 	}
+	return result, nil
+}
 
-	// Sort chronologically
-	sort.Slice(allMessages, func(i, j int) bool {
-		tsI, _ := allMessages[i]["ts"].(string)
-		tsJ, _ := allMessages[j]["ts"].(string)
-		return tsI < tsJ
-	})
+// HistoryOptions controls which messages a history or thread listing returns.
+type HistoryOptions struct {
+	Oldest    string // only messages after this timestamp
+	Latest    string // only messages before this timestamp
+	Inclusive bool   // include messages exactly at Oldest/Latest
+	Limit     int    // maximum number of messages (0 = unlimited)
+	Unlimited bool   // fetch every message in the window, ignoring Limit
+	PageSize  int    // messages requested per API call (0 = 200, the Slack maximum)
+	Cursor    string // continue an earlier listing from the cursor it returned through Resume
+
+	// Resume, if set, receives the cursor to pass as Cursor to continue where the listing
+	// stopped, or "" if it reached the end. When a listing stops partway through a page,
+	// the cursor also records the last message listed, so continuing skips it and those
+	// before it.
+	Resume *string
+}
+
+// limit returns the number of messages to stop after (0 = unlimited).
+func (o HistoryOptions) limit() int {
+	if o.Unlimited {
+		return 0
+	}
+	return max(o.Limit, 0)
+}
+
+// params returns the conversations.history/replies window params for opts.
+func (o HistoryOptions) params() map[string]string {
+	params := make(map[string]string)
+	if o.Oldest != "" {
+		params["oldest"] = NormalizeTimestamp(o.Oldest)
+	}
+	if o.Latest != "" {
+		params["latest"] = NormalizeTimestamp(o.Latest)
+	}
+	if o.Inclusive {
+		params["inclusive"] = "true"
+	}
+	return params
+}
+
+// IterChannelHistory streams a channel's messages, newest first, fetching pages as they are consumed.
+func IterChannelHistory(ctx context.Context, client APIClient, channelID string, opts HistoryOptions) iter.Seq2[map[string]any, error] {
+	params := opts.params()
+	params["channel"] = channelID
+	return pager{
+		method:   "conversations.history",
+		field:    "messages",
+		params:   params,
+		pageSize: opts.PageSize,
+		limit:    opts.limit(),
+		cursor:   opts.Cursor,
+		resume:   opts.Resume,
+		key:      "ts",
+	}.all(ctx, client)
+}
+
+// IterThread streams a thread's messages (root first), fetching pages as they are consumed.
+func IterThread(ctx context.Context, client APIClient, channelID string, threadTS string, opts HistoryOptions) iter.Seq2[map[string]any, error] {
+	params := opts.params()
+	params["channel"] = channelID
+	params["ts"] = NormalizeTimestamp(threadTS)
+	return pager{
+		method:   "conversations.replies",
+		field:    "messages",
+		params:   params,
+		pageSize: opts.PageSize,
+		limit:    opts.limit(),
+		cursor:   opts.Cursor,
+		resume:   opts.Resume,
+		key:      "ts",
+	}.all(ctx, client)
+}
+
+// ListChannelHistory fetches recent messages from a channel, paginated.
+func ListChannelHistory(ctx context.Context, client APIClient, channelID string, limit int) ([]map[string]any, error) {
+	return ListChannelHistoryWithOptions(ctx, client, channelID, HistoryOptions{Limit: limit})
+}
+
+// ListChannelHistoryWithOptions fetches a channel's messages in the window opts describes,
+// paginated, and returns them oldest first.
+func ListChannelHistoryWithOptions(ctx context.Context, client APIClient, channelID string, opts HistoryOptions) ([]map[string]any, error) {
+	return CollectMessages(IterChannelHistory(ctx, client, channelID, opts))
+}
+
+// ListThread fetches all replies in a thread, paginated.
+func ListThread(ctx context.Context, client APIClient, channelID string, threadTS string, limit int) ([]map[string]any, error) {
+	return ListThreadWithOptions(ctx, client, channelID, threadTS, HistoryOptions{Limit: limit})
+}
 
-	return allMessages, nil
+// ListThreadWithOptions fetches a thread's messages in the window opts describes,
+// paginated, and returns them root first.
+func ListThreadWithOptions(ctx context.Context, client APIClient, channelID string, threadTS string, opts HistoryOptions) ([]map[string]any, error) {
+	return CollectMessages(IterThread(ctx, client, channelID, threadTS, opts))
 }
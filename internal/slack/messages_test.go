@@ -1,7 +1,11 @@
 package slack_test
 
 import (
+	"context"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/sethrylan/slack-reader/internal/slack"
 )
@@ -28,3 +32,322 @@ func TestNormalizeTimestamp(t *testing.T) {
 		})
 	}
 }
+
+func TestPermalink(t *testing.T) {
+	tests := []struct {
+		name     string
+		ts       string
+		threadTS string
+		want     string
+	}{
+		{"top-level", "1770165109.628379", "", "https://myteam.slack.com/archives/C0123ABC/p1770165109628379"},
+		{"thread root", "1770165109.628379", "1770165109.628379", "https://myteam.slack.com/archives/C0123ABC/p1770165109628379"},
+		{"reply", "1770165200.000100", "1770165109.628379", "https://myteam.slack.com/archives/C0123ABC/p1770165200000100?thread_ts=1770165109.628379&cid=C0123ABC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slack.Permalink("myteam", "C0123ABC", tt.ts, tt.threadTS)
+			if got != tt.want {
+				t.Errorf("Permalink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// repliesAPI serves a thread whose replies are absent from conversations.history.
+type repliesAPI struct {
+	methods []string
+}
+
+func (m *repliesAPI) API(_ context.Context, method string, _ map[string]string) (map[string]any, error) {
+	m.methods = append(m.methods, method)
+	if method == "conversations.history" {
+		return map[string]any{"ok": true, "messages": []any{}}, nil
+	}
+	return map[string]any{
+		"ok": true,
+		"messages": []any{
+			map[string]any{"ts": "1770165109.628379", "thread_ts": "1770165109.628379", "reply_count": float64(3)},
+			map[string]any{"ts": "1770165200.000100", "thread_ts": "1770165109.628379", "text": "reply"},
+		},
+	}, nil
+}
+
+func TestGetMessage_ThreadReply(t *testing.T) {
+	mock := &repliesAPI{}
+
+	result, err := slack.GetMessage(t.Context(), mock, "C0123ABC", "1770165200000100", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if text := result.Message["text"]; text != "reply" {
+		t.Errorf("got message text %q, want %q", text, "reply")
+	}
+	if ts := result.Thread["ts"]; ts != "1770165109.628379" {
+		t.Errorf("got thread ts %v, want parent ts", ts)
+	}
+	if n := result.Thread["length"]; n != 3 {
+		t.Errorf("got thread length %v, want 3", n)
+	}
+	if len(mock.methods) != 2 || mock.methods[1] != "conversations.replies" {
+		t.Errorf("got calls %v, want history then replies", mock.methods)
+	}
+}
+
+func TestIncludeThread(t *testing.T) {
+	mock := &repliesAPI{}
+	result, err := slack.GetMessage(t.Context(), mock, "C0123ABC", "1770165200.000100", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := slack.IncludeThread(t.Context(), mock, "C0123ABC", result); err != nil {
+		t.Fatal(err)
+	}
+	messages, _ := result.Thread["messages"].([]map[string]any)
+	if len(messages) != 2 || messages[0]["ts"] != "1770165109.628379" || messages[1]["text"] != "reply" {
+		t.Errorf("thread messages = %v, want parent then reply", messages)
+	}
+
+	// A message outside any thread is left as is.
+	plain := &slack.MessageResult{Message: map[string]any{"ts": "1770165300.000200"}}
+	if err := slack.IncludeThread(t.Context(), mock, "C0123ABC", plain); err != nil {
+		t.Fatal(err)
+	}
+	if plain.Thread != nil {
+		t.Errorf("thread = %v, want none", plain.Thread)
+	}
+}
+
+// historyAPI serves conversations.history windows from a fixed set of top-level messages.
+type historyAPI struct {
+	ts    []string // newest first, as Slack returns them
+	calls int
+}
+
+func (m *historyAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.calls++
+	var msgs []any
+	for _, ts := range m.ts {
+		if ts >= params["oldest"] && ts <= params["latest"] {
+			msgs = append(msgs, map[string]any{"ts": ts})
+		}
+	}
+	return map[string]any{"ok": true, "messages": msgs}, nil
+}
+
+func TestGetMessages_GroupsNearbyTimestamps(t *testing.T) {
+	mock := &historyAPI{ts: []string{
+		"1770200000.000000", // a day later: its own window
+		"1770100200.000000",
+		"1770100100.000000",
+		"1770100000.000000",
+	}}
+
+	tss := []string{"1770200000.000000", "1770100000000000", "1770100200.000000"}
+	results, err := slack.GetMessages(t.Context(), mock, "C0123ABC", tss)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"1770200000.000000", "1770100000.000000", "1770100200.000000"}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if ts := r.Message["ts"]; ts != want[i] {
+			t.Errorf("result %d: got ts %v, want %s", i, ts, want[i])
+		}
+	}
+	if mock.calls != 2 {
+		t.Errorf("made %d history calls, want 2", mock.calls)
+	}
+}
+
+func TestAppLink(t *testing.T) {
+	got := slack.AppLink("T0123ABCD", "C0123ABC")
+	want := "slack://channel?team=T0123ABCD&id=C0123ABC"
+	if got != want {
+		t.Errorf("AppLink() = %q, want %q", got, want)
+	}
+}
+
+func TestParseTimeSpec(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2026-01-31", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2026-01-31T08:00:00Z", time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)},
+		{"1770165109.628379", time.Unix(1770165109, 628379000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := slack.ParseTimeSpec(tt.spec, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimeSpec(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"", "yesterday", "-3d", "12345"} {
+		if _, err := slack.ParseTimeSpec(spec, now); err == nil {
+			t.Errorf("ParseTimeSpec(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestHasFilesAndLinks(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          map[string]any
+		files, links bool
+	}{
+		{"plain", map[string]any{"text": "see the doc"}, false, false},
+		{"file", map[string]any{"text": "", "files": []any{map[string]any{"id": "F1"}}}, true, false},
+		{"link", map[string]any{"text": "see <https://example.com/doc|the doc>"}, false, true},
+		{"bare link", map[string]any{"text": "<http://example.com>"}, false, true},
+		{"mention", map[string]any{"text": "<@U123> and <#C123|general>"}, false, false},
+		{"unfurl", map[string]any{"text": "", "attachments": []any{
+			map[string]any{"from_url": "https://example.com/post", "title": "Post"},
+		}}, false, true},
+	}
+	for _, tt := range tests {
+		if got := slack.HasFiles(tt.msg); got != tt.files {
+			t.Errorf("%s: HasFiles = %v, want %v", tt.name, got, tt.files)
+		}
+		if got := slack.HasLinks(tt.msg); got != tt.links {
+			t.Errorf("%s: HasLinks = %v, want %v", tt.name, got, tt.links)
+		}
+	}
+}
+
+func TestIsSystemMessage(t *testing.T) {
+	tests := []struct {
+		msg  map[string]any
+		want bool
+	}{
+		{map[string]any{"text": "hello"}, false},
+		{map[string]any{"subtype": "channel_join", "text": "<@U123> has joined the channel"}, true},
+		{map[string]any{"subtype": "bot_add"}, true},
+		{map[string]any{"subtype": "bot_message", "text": "deploy finished"}, false},
+		{map[string]any{"subtype": "thread_broadcast"}, false},
+	}
+	for _, tt := range tests {
+		if got := slack.IsSystemMessage(tt.msg); got != tt.want {
+			t.Errorf("IsSystemMessage(%v) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestMatchSubtypes(t *testing.T) {
+	plain := map[string]any{"text": "hello"}
+	bot := map[string]any{"subtype": "bot_message"}
+	broadcast := map[string]any{"subtype": "thread_broadcast"}
+
+	only := slack.MatchSubtypes([]string{"bot_message", "me_message"}, nil)
+	if only(plain) || !only(bot) || only(broadcast) {
+		t.Error("include: want only bot_message kept")
+	}
+	except := slack.MatchSubtypes(nil, []string{"bot_message"})
+	if !except(plain) || except(bot) || !except(broadcast) {
+		t.Error("exclude: want everything but bot_message kept")
+	}
+}
+
+// windowHistoryAPI serves conversations.history like Slack: newest first, within
+// oldest/latest (exclusive unless inclusive), up to limit messages.
+type windowHistoryAPI struct {
+	ts    []string // newest first
+	calls []map[string]string
+}
+
+func (m *windowHistoryAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.calls = append(m.calls, params)
+	limit, _ := strconv.Atoi(params["limit"])
+	inclusive := params["inclusive"] == "true"
+	var msgs []any
+	for _, ts := range m.ts {
+		if oldest := params["oldest"]; oldest != "" && (ts < oldest || ts == oldest && !inclusive) {
+			continue
+		}
+		if latest := params["latest"]; latest != "" && (ts > latest || ts == latest && !inclusive) {
+			continue
+		}
+		if len(msgs) == limit {
+			break
+		}
+		msgs = append(msgs, map[string]any{"ts": ts})
+	}
+	return map[string]any{"ok": true, "messages": msgs}, nil
+}
+
+func TestMessageContext(t *testing.T) {
+	mock := &windowHistoryAPI{ts: []string{
+		"1770200000.000000", // a day later: beyond the first window
+		"1770100600.000000",
+		"1770100000.000000", // the message
+		"1770099900.000000",
+		"1770099800.000000",
+		"1770099700.000000",
+	}}
+
+	messages, err := slack.MessageContext(t.Context(), mock, "C0123ABC", "1770100000000000", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1770099800.000000", "1770099900.000000", "1770100000.000000", "1770100600.000000", "1770200000.000000"}
+	if len(messages) != len(want) {
+		t.Fatalf("got %d messages, want %d: %v", len(messages), len(want), messages)
+	}
+	for i, msg := range messages {
+		if msg["ts"] != want[i] {
+			t.Errorf("message %d: got ts %v, want %s", i, msg["ts"], want[i])
+		}
+		if isContext := msg["context"] == true; isContext != (i != 2) {
+			t.Errorf("message %d: context = %v", i, msg["context"])
+		}
+	}
+
+	if _, err := slack.MessageContext(t.Context(), mock, "C0123ABC", "1770100001.000000", 2, 2); err == nil {
+		t.Error("want an error for a timestamp not in history")
+	}
+}
+
+// Messages following a recent one are found without listing history back from the
+// present unbounded, even when most of them are newer than those wanted.
+func TestMessageContext_Recent(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) string { return slack.FormatTimestamp(now.Add(-d)) }
+	mock := &windowHistoryAPI{ts: []string{
+		ago(6 * time.Minute), ago(7 * time.Minute), ago(8 * time.Minute), ago(9 * time.Minute), ago(10 * time.Minute),
+		ago(150 * time.Minute),
+		ago(3 * time.Hour), // the message
+	}}
+
+	messages, err := slack.MessageContext(t.Context(), mock, "C0123ABC", ago(3*time.Hour), 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{ago(3 * time.Hour), ago(150 * time.Minute), ago(10 * time.Minute), ago(9 * time.Minute)}
+	var got []string
+	for _, msg := range messages {
+		got = append(got, msg["ts"].(string))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, call := range mock.calls {
+		if call["latest"] == "" && call["limit"] != "3" {
+			t.Errorf("listed up to the present with limit %q, want 3", call["limit"])
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"maps"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPageSize is the largest page Slack returns for cursor-paginated methods.
+const maxPageSize = 200
+
+// pager walks a cursor-paginated Slack method, yielding the objects in one response field.
+type pager struct {
+	method   string
+	field    string            // response field holding the page's items (e.g., "messages")
+	params   map[string]string // request params, excluding limit and cursor
+	pageSize int               // items requested per page (0 or > maxPageSize = maxPageSize)
+	limit    int               // total items to yield (0 = unlimited)
+	cursor   string            // cursor of the first page ("" = start from the beginning)
+	resume   *string           // if set, receives the cursor to continue from where iteration ended ("" = exhausted)
+	key      string            // item field marking a position within a page (e.g., "ts"), if any
+}
+
+// positionSep separates a Slack cursor from the in-page position appended to it.
+// Slack cursors are base64, so they never contain it.
+const positionSep = "@"
+
+// all fetches pages lazily as the returned sequence is consumed.
+// Stopping iteration early stops pagination; an error ends the sequence.
+//
+// Slack cursors mark page boundaries, so when iteration ends partway through a page and
+// the pager has a key, the resume cursor is that page's cursor followed by the key of
+// the last item yielded: continuing from it refetches the page and skips the items
+// through that one. Limits are met exactly by requesting smaller pages.
+func (p pager) all(ctx context.Context, client APIClient) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		pageSize := p.pageSize
+		if pageSize <= 0 || pageSize > maxPageSize {
+			pageSize = maxPageSize
+		}
+
+		cursor, after := p.cursor, ""
+		if p.key != "" {
+			if i := strings.LastIndex(cursor, positionSep); i >= 0 {
+				cursor, after = cursor[:i], cursor[i+len(positionSep):]
+			}
+		}
+		setResume := func(c string) {
+			if p.resume != nil {
+				*p.resume = c
+			}
+		}
+		setResume(p.cursor)
+		// stopped records where iteration ended: at the next page after item i of the
+		// page, or partway through it.
+		stopped := func(items []any, i int, item map[string]any, next string) {
+			if i == len(items)-1 {
+				setResume(next)
+				return
+			}
+			if pos, _ := item[p.key].(string); p.key != "" && pos != "" {
+				setResume(cursor + positionSep + pos)
+			}
+		}
+
+		fetched := 0
+		for page := 1; ; page++ {
+			size := pageSize
+			if p.limit > 0 && p.limit-fetched < size {
+				size = p.limit - fetched
+			}
+			if size <= 0 {
+				return
+			}
+
+			params := maps.Clone(p.params)
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params["limit"] = strconv.Itoa(size)
+			if cursor != "" {
+				params["cursor"] = cursor
+			}
+
+			resp, err := client.API(ctx, p.method, params)
+			if err != nil {
+				yield(nil, fmt.Errorf("%s: %w", p.method, err))
+				return
+			}
+
+			meta, _ := resp["response_metadata"].(map[string]any)
+			next, _ := meta["next_cursor"].(string)
+
+			items, _ := resp[p.field].([]any)
+			slog.Debug("fetched page", "method", p.method, "page", page, "items", len(items), "more", next != "")
+			start := 0
+			if after != "" {
+				// Resuming partway through this page: skip the items already yielded.
+				for i, it := range items {
+					if item, _ := it.(map[string]any); item != nil && item[p.key] == after {
+						start = i + 1
+						break
+					}
+				}
+				after = ""
+			}
+			for i := start; i < len(items); i++ {
+				item, _ := items[i].(map[string]any)
+				if item == nil {
+					continue
+				}
+				if !yield(item, nil) {
+					stopped(items, i, item, next)
+					return
+				}
+				fetched++
+				if p.limit > 0 && fetched >= p.limit {
+					stopped(items, i, item, next)
+					return
+				}
+			}
+
+			setResume(next)
+			if next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// FilterMessages yields the messages from seq that keep accepts, stopping after limit
+// of them (0 = unlimited). Errors are passed through.
+func FilterMessages(seq iter.Seq2[map[string]any, error], keep func(map[string]any) bool, limit int) iter.Seq2[map[string]any, error] {
+	return FilterMessagesContext(seq, keep, limit, 0)
+}
+
+// FilterMessagesContext is FilterMessages that also yields up to n messages on either
+// side of each accepted one, as they appear in seq, marked with "context": true.
+// After the limit-th accepted message, it continues for that message's n following ones.
+func FilterMessagesContext(seq iter.Seq2[map[string]any, error], keep func(map[string]any) bool, limit, n int) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		var before []map[string]any // unyielded messages preceding the next match
+		after := 0                  // messages still to yield after the last match
+		kept := 0
+		for msg, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if keep(msg) {
+				for _, b := range before {
+					b["context"] = true
+					if !yield(b, nil) {
+						return
+					}
+				}
+				before = before[:0]
+				if !yield(msg, nil) {
+					return
+				}
+				kept++
+				after = n
+			} else if after > 0 {
+				msg["context"] = true
+				if !yield(msg, nil) {
+					return
+				}
+				after--
+			} else if n > 0 {
+				if len(before) == n {
+					before = before[1:]
+				}
+				before = append(before, msg)
+			}
+			if limit > 0 && kept >= limit && after == 0 {
+				return
+			}
+		}
+	}
+}
+
+// MatchText returns a filter accepting messages whose text, or the text of one of their
+// attachments, matches re.
+func MatchText(re *regexp.Regexp) func(map[string]any) bool {
+	return func(msg map[string]any) bool {
+		if text, _ := msg["text"].(string); re.MatchString(text) {
+			return true
+		}
+		attachments, _ := msg["attachments"].([]any)
+		for _, a := range attachments {
+			att, _ := a.(map[string]any)
+			text, _ := att["text"].(string)
+			fallback, _ := att["fallback"].(string)
+			if re.MatchString(text) || re.MatchString(fallback) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CollectMessages drains a message sequence and sorts it chronologically (oldest first).
+func CollectMessages(seq iter.Seq2[map[string]any, error]) ([]map[string]any, error) {
+	var messages []map[string]any
+	for msg, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		tsI, _ := messages[i]["ts"].(string)
+		tsJ, _ := messages[j]["ts"].(string)
+		return tsI < tsJ
+	})
+	return messages, nil
+}
@@ -3,7 +3,10 @@ package slack_test
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/sethrylan/slack-reader/internal/slack"
@@ -186,3 +189,300 @@ func TestListChannelHistory_SmallLimit(t *testing.T) {
 		t.Errorf("sent limit=%d, want 25", lim)
 	}
 }
+
+// Breaking out of the iterator must stop pagination without fetching further pages.
+func TestIterChannelHistory_StopsEarly(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(200, "cursor_page2"),
+			makePage(200, "cursor_page3"),
+			makePage(200, ""),
+		},
+	}
+
+	seen := 0
+	for _, err := range slack.IterChannelHistory(t.Context(), mock, "C123", slack.HistoryOptions{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		seen++
+		if seen == 250 {
+			break
+		}
+	}
+
+	if got := len(mock.calls); got != 2 {
+		t.Errorf("made %d API calls, want 2", got)
+	}
+}
+
+// Errors are yielded and end the sequence.
+func TestIterChannelHistory_YieldsError(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(200, "cursor_page2"),
+		},
+	}
+
+	seen := 0
+	var gotErr error
+	for _, err := range slack.IterChannelHistory(t.Context(), mock, "C123", slack.HistoryOptions{}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		seen++
+	}
+
+	if seen != 200 {
+		t.Errorf("got %d messages before the error, want 200", seen)
+	}
+	if gotErr == nil {
+		t.Error("expected an error from the second page")
+	}
+}
+
+// A smaller page size is sent on every request.
+func TestIterChannelHistory_PageSize(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(50, "cursor_page2"),
+			makePage(50, "cursor_page3"),
+			makePage(20, ""),
+		},
+	}
+
+	msgs, err := slack.CollectMessages(slack.IterChannelHistory(t.Context(), mock, "C123", slack.HistoryOptions{PageSize: 50}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(msgs); got != 120 {
+		t.Errorf("got %d messages, want 120", got)
+	}
+	for i, call := range mock.calls {
+		if lim := call["limit"]; lim != "50" {
+			t.Errorf("page %d: sent limit=%q, want \"50\"", i, lim)
+		}
+	}
+}
+
+// The window is sent on every page, and Unlimited overrides Limit.
+func TestListChannelHistoryWithOptions_Window(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(200, "cursor_page2"),
+			makePage(100, ""),
+		},
+	}
+
+	msgs, err := slack.ListChannelHistoryWithOptions(t.Context(), mock, "C123", slack.HistoryOptions{
+		Oldest:    "1770000000000000",
+		Latest:    "1770100000.000000",
+		Inclusive: true,
+		Limit:     10,
+		Unlimited: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(msgs); got != 300 {
+		t.Errorf("got %d messages, want 300", got)
+	}
+	for i, call := range mock.calls {
+		if call["oldest"] != "1770000000.000000" || call["latest"] != "1770100000.000000" || call["inclusive"] != "true" {
+			t.Errorf("page %d: sent %v, want the window", i, call)
+		}
+	}
+}
+
+// FilterMessages counts its limit in kept messages, paginating past the rest.
+func TestFilterMessages_LimitCountsKept(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(200, "cursor_page2"),
+			makePage(200, ""),
+		},
+	}
+	everyHundredth := func(msg map[string]any) bool {
+		ts, _ := msg["ts"].(string)
+		return strings.HasSuffix(ts, "00")
+	}
+
+	msgs, err := slack.CollectMessages(slack.FilterMessages(
+		slack.IterChannelHistory(t.Context(), mock, "C123", slack.HistoryOptions{}), everyHundredth, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Errorf("got %d messages, want 3", len(msgs))
+	}
+	if got := len(mock.calls); got != 2 {
+		t.Errorf("made %d API calls, want 2", got)
+	}
+}
+
+func TestFilterMessagesContext(t *testing.T) {
+	texts := []string{"a", "b", "deploy started", "c", "d", "e", "f", "rollback", "g"}
+	seq := func(yield func(map[string]any, error) bool) {
+		for i, text := range texts {
+			if !yield(map[string]any{"ts": fmt.Sprintf("1770000000.%06d", i), "text": text}, nil) {
+				return
+			}
+		}
+	}
+	keep := slack.MatchText(regexp.MustCompile(`deploy|rollback`))
+
+	var got []string
+	for msg, err := range slack.FilterMessagesContext(seq, keep, 0, 1) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, _ := msg["text"].(string)
+		if msg["context"] == true {
+			text = "(" + text + ")"
+		}
+		got = append(got, text)
+	}
+	want := []string{"(b)", "deploy started", "(c)", "(f)", "rollback", "(g)"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The limit counts matches, and the last match keeps its following context.
+	got = nil
+	for msg := range slack.FilterMessagesContext(seq, keep, 1, 2) {
+		text, _ := msg["text"].(string)
+		got = append(got, text)
+	}
+	if want := []string{"a", "b", "deploy started", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("with limit: got %v, want %v", got, want)
+	}
+}
+
+func TestIterChannelHistory_Resume(t *testing.T) {
+	mock := &mockAPI{
+		pages: []map[string]any{
+			makePage(200, "cursor_page3"),
+			makePage(100, "cursor_page4"),
+		},
+	}
+
+	// Starting from a cursor and stopping at a limit that ends a page resumes at the next page.
+	var next string
+	opts := slack.HistoryOptions{Limit: 300, Cursor: "cursor_page2", Resume: &next}
+	msgs, err := slack.CollectMessages(slack.IterChannelHistory(t.Context(), mock, "C123", opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 300 {
+		t.Errorf("got %d messages, want 300", len(msgs))
+	}
+	if c := mock.calls[0]["cursor"]; c != "cursor_page2" {
+		t.Errorf("first call cursor=%q, want \"cursor_page2\"", c)
+	}
+	if next != "cursor_page4" {
+		t.Errorf("resume cursor = %q, want \"cursor_page4\"", next)
+	}
+
+	// Stopping partway through a page resumes after the last message listed on it.
+	mock = &mockAPI{pages: []map[string]any{makePage(200, "cursor_page5")}}
+	opts = slack.HistoryOptions{Cursor: "cursor_page4", Resume: &next}
+	n := 0
+	for _, err := range slack.IterChannelHistory(t.Context(), mock, "C123", opts) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n++; n == 50 {
+			break
+		}
+	}
+	if want := "cursor_page4@1770000000.000049"; next != want {
+		t.Errorf("resume cursor = %q, want %q", next, want)
+	}
+
+	mock = &mockAPI{pages: []map[string]any{makePage(200, "cursor_page5")}}
+	opts = slack.HistoryOptions{Limit: 10, Cursor: next, Resume: &next}
+	msgs, err = slack.CollectMessages(slack.IterChannelHistory(t.Context(), mock, "C123", opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := mock.calls[0]["cursor"]; c != "cursor_page4" {
+		t.Errorf("resumed call cursor=%q, want \"cursor_page4\"", c)
+	}
+	if len(msgs) != 10 || msgs[0]["ts"] != "1770000000.000050" {
+		t.Errorf("resumed at %v (%d messages), want 10 from 1770000000.000050", msgs[0]["ts"], len(msgs))
+	}
+	if want := "cursor_page4@1770000000.000059"; next != want {
+		t.Errorf("resume cursor = %q, want %q", next, want)
+	}
+
+	// Reaching the end leaves nothing to resume.
+	mock = &mockAPI{pages: []map[string]any{makePage(10, "")}}
+	opts = slack.HistoryOptions{Cursor: "cursor_page5", Resume: &next}
+	if _, err := slack.CollectMessages(slack.IterChannelHistory(t.Context(), mock, "C123", opts)); err != nil {
+		t.Fatal(err)
+	}
+	if next != "" {
+		t.Errorf("resume cursor = %q, want none", next)
+	}
+}
+
+func TestFilterMessages_ResumeAcrossPages(t *testing.T) {
+	// Two pages of 200 messages, newest first; every third one matches.
+	page := func(base int, next string) map[string]any {
+		msgs := make([]any, 200)
+		for i := range msgs {
+			n := base - i
+			msgs[i] = map[string]any{"ts": fmt.Sprintf("1770000000.%06d", n), "text": strconv.Itoa(n % 3)}
+		}
+		resp := map[string]any{"ok": true, "messages": msgs}
+		if next != "" {
+			resp["response_metadata"] = map[string]any{"next_cursor": next}
+		}
+		return resp
+	}
+	pages := map[string]map[string]any{"": page(399, "cursor_page2"), "cursor_page2": page(199, "")}
+	client := apiFunc(func(params map[string]string) (map[string]any, error) {
+		return pages[params["cursor"]], nil
+	})
+	keep := func(msg map[string]any) bool { return msg["text"] == "0" }
+
+	// Page through the channel five matches at a time, as message list --limit 5 does.
+	var seen []string
+	cursor := ""
+	for range 100 {
+		var next string
+		opts := slack.HistoryOptions{Cursor: cursor, Resume: &next}
+		for msg, err := range slack.FilterMessages(slack.IterChannelHistory(t.Context(), client, "C123", opts), keep, 5) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen = append(seen, msg["ts"].(string))
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 134 {
+		t.Errorf("listed %d matches, want 134", len(seen))
+	}
+	if len(seen) > 5 && seen[5] != "1770000000.000384" {
+		t.Errorf("second run started at %s, want 1770000000.000384", seen[5])
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] >= seen[i-1] {
+			t.Fatalf("match %d (%s) repeats or precedes match %d (%s)", i, seen[i], i-1, seen[i-1])
+		}
+	}
+}
+
+// apiFunc adapts a function to slack.APIClient.
+type apiFunc func(params map[string]string) (map[string]any, error)
+
+func (f apiFunc) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	return f(params)
+}
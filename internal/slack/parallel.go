@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ListChannelHistoryParallel fetches every message in a channel between opts.Oldest and
+// opts.Latest (exclusive, both required) by splitting the range into n equal time windows,
+// walking each window's cursor concurrently, and merging the results oldest first. For
+// multi-year channels this avoids one long sequential cursor walk. opts.Limit and
+// opts.Inclusive are ignored.
+func ListChannelHistoryParallel(ctx context.Context, client APIClient, channelID string, opts HistoryOptions, n int) ([]map[string]any, error) {
+	if opts.Oldest == "" || opts.Latest == "" {
+		return nil, errors.New("parallel history requires both oldest and latest")
+	}
+	oldest, err := ParseTimestamp(opts.Oldest)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := ParseTimestamp(opts.Latest)
+	if err != nil {
+		return nil, err
+	}
+	if !latest.After(oldest) {
+		return nil, errors.New("history window is empty: latest must be after oldest")
+	}
+	n = max(n, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	span := latest.Sub(oldest) / time.Duration(n)
+	results := make([][]map[string]any, n)
+
+	// The first failure cancels the other windows; report it rather than their cancellations.
+	var firstErr error
+	var once sync.Once
+
+	var wg sync.WaitGroup
+	for i := range n {
+		start := oldest.Add(time.Duration(i) * span)
+		end := oldest.Add(time.Duration(i+1) * span)
+		if i == n-1 {
+			end = latest
+		}
+
+		wg.Go(func() {
+			// Windows share their boundaries inclusively so that no message falls
+			// between two exclusive bounds; duplicates are dropped when merging.
+			window := HistoryOptions{
+				Oldest:    FormatTimestamp(start),
+				Latest:    FormatTimestamp(end),
+				Inclusive: true,
+				PageSize:  opts.PageSize,
+			}
+			msgs, err := CollectMessages(IterChannelHistory(ctx, client, channelID, window))
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = msgs
+		})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// Windows are ordered and each is sorted, so concatenating preserves order.
+	oldestTS, latestTS := FormatTimestamp(oldest), FormatTimestamp(latest)
+	seen := make(map[string]bool)
+	var merged []map[string]any
+	for _, msgs := range results {
+		for _, msg := range msgs {
+			ts, _ := msg["ts"].(string)
+			if seen[ts] || ts <= oldestTS || ts >= latestTS {
+				continue
+			}
+			seen[ts] = true
+			merged = append(merged, msg)
+		}
+	}
+	return merged, nil
+}
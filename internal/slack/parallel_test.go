@@ -0,0 +1,63 @@
+package slack_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// windowAPI serves conversations.history from a fixed set of timestamps, honoring oldest/latest/inclusive.
+type windowAPI struct {
+	mu    sync.Mutex
+	ts    []string
+	calls int
+}
+
+func (w *windowAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+
+	inclusive := params["inclusive"] == "true"
+	var msgs []any
+	for _, ts := range w.ts {
+		afterOldest := ts > params["oldest"] || (inclusive && ts == params["oldest"])
+		beforeLatest := ts < params["latest"] || (inclusive && ts == params["latest"])
+		if afterOldest && beforeLatest {
+			msgs = append(msgs, map[string]any{"ts": ts})
+		}
+	}
+	return map[string]any{"ok": true, "messages": msgs}, nil
+}
+
+func TestListChannelHistoryParallel(t *testing.T) {
+	api := &windowAPI{ts: []string{
+		"1000.000000", // at oldest: excluded
+		"1050.000000",
+		"1100.000000", // on a window boundary: returned once
+		"1250.000000",
+		"1399.000000",
+		"1400.000000", // at latest: excluded
+	}}
+
+	opts := slack.HistoryOptions{Oldest: "1000.000000", Latest: "1400.000000"}
+	msgs, err := slack.ListChannelHistoryParallel(t.Context(), api, "C123", opts, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"1050.000000", "1100.000000", "1250.000000", "1399.000000"}
+	if len(msgs) != len(want) {
+		t.Fatalf("got %d messages, want %d: %v", len(msgs), len(want), msgs)
+	}
+	for i, ts := range want {
+		if got := msgs[i]["ts"]; got != ts {
+			t.Errorf("msgs[%d].ts = %v, want %s", i, got, ts)
+		}
+	}
+	if api.calls != 4 {
+		t.Errorf("made %d API calls, want 4 (one per window)", api.calls)
+	}
+}
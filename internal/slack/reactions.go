@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// AddReactionUsers fills in who reacted to each message, in place. Slack embeds at most a
+// few dozen users per reaction in history, so reactions listing fewer users than their
+// count are refetched in full with reactions.get. Each reaction then gains "user_names",
+// its users resolved to display names.
+func AddReactionUsers(ctx context.Context, client APIClient, users *UserProvider, channelID string, messages []map[string]any) error {
+	// Fetches write into their own slots; the messages are only updated after Wait.
+	full := make([][]any, len(messages))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for i, msg := range messages {
+		ts, _ := msg["ts"].(string)
+		if ts == "" || !reactionsTruncated(msg) {
+			continue
+		}
+		g.Go(func() (err error) {
+			full[i], err = getReactions(gctx, client, channelID, ts)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	var reactors []map[string]any
+	for i, msg := range messages {
+		if full[i] != nil {
+			msg["reactions"] = full[i]
+		}
+		for _, id := range reactionUserIDs(msg) {
+			reactors = append(reactors, map[string]any{"user": id})
+		}
+	}
+	users.Prefetch(ctx, reactors)
+
+	for _, msg := range messages {
+		reactions, _ := msg["reactions"].([]any)
+		for _, r := range reactions {
+			reaction, _ := r.(map[string]any)
+			if reaction == nil {
+				continue
+			}
+			ids, _ := reaction["users"].([]any)
+			names := make([]string, 0, len(ids))
+			for _, id := range ids {
+				if id, _ := id.(string); id != "" {
+					name, _ := users.UsernameForID(id)
+					names = append(names, name)
+				}
+			}
+			reaction["user_names"] = names
+		}
+	}
+	return nil
+}
+
+// reactionsTruncated reports whether any of a message's reactions lists fewer users than its count.
+func reactionsTruncated(msg map[string]any) bool {
+	reactions, _ := msg["reactions"].([]any)
+	for _, r := range reactions {
+		reaction, _ := r.(map[string]any)
+		count, _ := reaction["count"].(float64)
+		ids, _ := reaction["users"].([]any)
+		if len(ids) < int(count) {
+			return true
+		}
+	}
+	return false
+}
+
+// reactionUserIDs returns the user IDs listed in a message's reactions.
+func reactionUserIDs(msg map[string]any) []string {
+	var ids []string
+	reactions, _ := msg["reactions"].([]any)
+	for _, r := range reactions {
+		reaction, _ := r.(map[string]any)
+		users, _ := reaction["users"].([]any)
+		for _, u := range users {
+			if id, _ := u.(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// getReactions fetches every reaction to a message, with complete user lists.
+func getReactions(ctx context.Context, client APIClient, channelID, ts string) ([]any, error) {
+	resp, err := client.API(ctx, "reactions.get", map[string]string{
+		"channel":   channelID,
+		"timestamp": ts,
+		"full":      "true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reactions.get: %w", err)
+	}
+	// Files and file comments are returned under their own keys; messages under "message".
+	msg, _ := resp["message"].(map[string]any)
+	reactions, _ := msg["reactions"].([]any)
+	return reactions, nil
+}
@@ -0,0 +1,75 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// reactionsAPI serves reactions.get with complete user lists, and users.info.
+type reactionsAPI struct {
+	mu    sync.Mutex
+	calls []string // timestamps passed to reactions.get
+}
+
+func (m *reactionsAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "reactions.get":
+		m.mu.Lock()
+		m.calls = append(m.calls, params["timestamp"])
+		m.mu.Unlock()
+		return map[string]any{"ok": true, "type": "message", "message": map[string]any{
+			"ts": params["timestamp"],
+			"reactions": []any{
+				map[string]any{"name": "+1", "count": float64(3), "users": []any{"U1", "U2", "U3"}},
+			},
+		}}, nil
+	case "users.info":
+		return map[string]any{"ok": true, "user": map[string]any{
+			"id": params["user"], "name": "user-" + params["user"],
+		}}, nil
+	}
+	return nil, fmt.Errorf("unexpected method %s", method)
+}
+
+func TestAddReactionUsers(t *testing.T) {
+	mock := &reactionsAPI{}
+	messages := []map[string]any{
+		{"ts": "1770165000.000100", "reactions": []any{
+			map[string]any{"name": "eyes", "count": float64(1), "users": []any{"U2"}},
+		}},
+		// Slack listed only two of the three users.
+		{"ts": "1770165000.000200", "reactions": []any{
+			map[string]any{"name": "+1", "count": float64(3), "users": []any{"U1", "U2"}},
+		}},
+		{"ts": "1770165000.000300", "text": "no reactions"},
+	}
+
+	err := slack.AddReactionUsers(t.Context(), mock, slack.NewUserProvider(mock), "C1", messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(mock.calls, []string{"1770165000.000200"}) {
+		t.Errorf("reactions.get calls = %v, want only the truncated message", mock.calls)
+	}
+	names := func(msg map[string]any) []string {
+		reactions, _ := msg["reactions"].([]any)
+		reaction, _ := reactions[0].(map[string]any)
+		names, _ := reaction["user_names"].([]string)
+		return names
+	}
+	if got := names(messages[0]); !slices.Equal(got, []string{"user-U2"}) {
+		t.Errorf("eyes users = %v", got)
+	}
+	if got := names(messages[1]); !slices.Equal(got, []string{"user-U1", "user-U2", "user-U3"}) {
+		t.Errorf("+1 users = %v, want all three", got)
+	}
+	if _, ok := messages[2]["reactions"]; ok {
+		t.Errorf("message without reactions gained %v", messages[2]["reactions"])
+	}
+}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// referenceTSPattern matches the message timestamp of a "#channel/ts" reference.
+var referenceTSPattern = regexp.MustCompile(`^\d{10}\.?\d{6}$`)
+
+// Reference is what a Slack reference names: a user's DM (User), a channel (Channel), or a
+// message in a channel (Channel and TS, and ThreadTS for a reply known to be in a thread).
+type Reference struct {
+	Channel  string // as accepted by ResolveChannelID
+	User     string // handle or ID, without the @
+	TS       string
+	ThreadTS string
+}
+
+// ParseReference parses a reference to something to read: "#channel" (or a name or ID),
+// "@user", a channel or message link, or "#channel/1770165109.628379".
+func ParseReference(s string) (Reference, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "#" || s == "@" {
+		return Reference{}, errors.New("empty reference")
+	}
+	if user, ok := strings.CutPrefix(s, "@"); ok {
+		return Reference{User: user}, nil
+	}
+	if channelID, ts, threadTS, ok := ParseArchiveURL(s); ok {
+		return Reference{Channel: channelID, TS: ts, ThreadTS: threadTS}, nil
+	}
+	if channel, ts, ok := strings.Cut(s, "/"); ok {
+		if !referenceTSPattern.MatchString(ts) || channel == "" {
+			return Reference{}, fmt.Errorf("invalid reference %q (want #channel/1770165109.628379)", s)
+		}
+		return Reference{Channel: channel, TS: NormalizeTimestamp(ts)}, nil
+	}
+	return Reference{Channel: s}, nil
+}
+
+// FindDM returns the ID of the current user's direct message conversation with a user.
+// Unlike conversations.open, it never creates one.
+func FindDM(ctx context.Context, client APIClient, userID string) (string, error) {
+	for c, err := range IterUserConversations(ctx, client, "", ConversationOptions{Types: []string{"im"}}) {
+		if err != nil {
+			return "", err
+		}
+		if user, _ := c["user"].(string); user == userID {
+			id, _ := c["id"].(string)
+			return id, nil
+		}
+	}
+	return "", withClass(fmt.Errorf("no direct message conversation with %s", userID), ErrNotFound)
+}
@@ -0,0 +1,61 @@
+package slack_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slack.Reference
+	}{
+		{"#general", slack.Reference{Channel: "#general"}},
+		{"C0123ABCD", slack.Reference{Channel: "C0123ABCD"}},
+		{"@alice", slack.Reference{User: "alice"}},
+		{"#general/1770165109.628379", slack.Reference{Channel: "#general", TS: "1770165109.628379"}},
+		{"C0123ABCD/1770165109628379", slack.Reference{Channel: "C0123ABCD", TS: "1770165109.628379"}},
+		{"https://myteam.slack.com/archives/C0123ABCD", slack.Reference{Channel: "C0123ABCD"}},
+		{
+			"https://myteam.slack.com/archives/C0123ABCD/p1770165200000100?thread_ts=1770165109.628379",
+			slack.Reference{Channel: "C0123ABCD", TS: "1770165200.000100", ThreadTS: "1770165109.628379"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := slack.ParseReference(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "@", "#general/yesterday"} {
+		if _, err := slack.ParseReference(in); err == nil {
+			t.Errorf("ParseReference(%q) succeeded, want error", in)
+		}
+	}
+}
+
+// dmAPI serves the current user's DMs.
+type dmAPI struct{}
+
+func (dmAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	if params["types"] != "im" {
+		return map[string]any{"ok": true, "channels": []any{}}, nil
+	}
+	return map[string]any{"ok": true, "channels": []any{
+		map[string]any{"id": "D1", "is_im": true, "user": "U1"},
+		map[string]any{"id": "D2", "is_im": true, "user": "U2"},
+	}}, nil
+}
+
+func TestFindDM(t *testing.T) {
+	id, err := slack.FindDM(t.Context(), dmAPI{}, "U2")
+	if err != nil || id != "D2" {
+		t.Errorf("FindDM = %q, %v; want D2", id, err)
+	}
+	if _, err := slack.FindDM(t.Context(), dmAPI{}, "U3"); err == nil {
+		t.Error("FindDM succeeded for a user without a DM, want error")
+	}
+}
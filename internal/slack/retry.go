@@ -0,0 +1,181 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// defaultMaxRetries is the number of times a rate-limited or transiently failing request is retried.
+const defaultMaxRetries = 5
+
+// defaultRetryAfter is used when Slack rate limits a request without a usable Retry-After header.
+const defaultRetryAfter = time.Second
+
+// Backoff bounds for transient failures (network errors, 5xx).
+const (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+// ErrRateLimited is returned when Slack keeps rate limiting a request after all retries are exhausted.
+var ErrRateLimited = errors.New("rate limited")
+
+// retryTransport retries rate-limited (HTTP 429) responses after the Retry-After duration,
+// and transient failures (network errors, timeouts, 5xx) with exponential backoff, both jittered.
+// The underlying rneatherway/slack client retries 429s forever and panics on a missing
+// Retry-After header, so we handle them here before it ever sees one.
+//
+// Each attempt, including reading the body, is bounded by timeout (if set); time spent
+// waiting between attempts doesn't count against it.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	timeout    time.Duration
+	wait       func(ctx context.Context, d time.Duration) error
+	onRetry    func(rateLimited bool)
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int, timeout time.Duration) *retryTransport {
+	return &retryTransport{base: base, maxRetries: maxRetries, timeout: timeout, wait: sleepContext}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := t.attempt(r)
+
+		var d time.Duration
+		switch {
+		case err != nil:
+			// Cancellation by the caller (e.g., --timeout) is final.
+			if req.Context().Err() != nil || !isTransient(err) {
+				return nil, err
+			}
+			if attempt >= t.maxRetries {
+				return nil, fmt.Errorf("%w (gave up after %d retries)", err, attempt)
+			}
+			d = withJitter(backoff(attempt))
+			slog.Info("transient error, retrying", "path", req.URL.Path, "attempt", attempt+1, "wait", d, "error", err)
+
+		case resp.StatusCode == http.StatusTooManyRequests:
+			if attempt >= t.maxRetries {
+				return nil, fmt.Errorf("%s: %w after %d retries", req.URL.Path, ErrRateLimited, attempt)
+			}
+			d = withJitter(parseRetryAfter(resp.Header.Get("Retry-After")))
+			slog.Info("rate limited, retrying", "path", req.URL.Path, "attempt", attempt+1, "wait", d)
+
+		case resp.StatusCode >= http.StatusInternalServerError:
+			if attempt >= t.maxRetries {
+				return resp, nil
+			}
+			d = withJitter(backoff(attempt))
+			slog.Info("server error, retrying", "path", req.URL.Path, "status", resp.StatusCode, "attempt", attempt+1, "wait", d)
+
+		default:
+			return resp, nil
+		}
+
+		if t.onRetry != nil {
+			t.onRetry(resp != nil && resp.StatusCode == http.StatusTooManyRequests)
+		}
+		if err := t.wait(req.Context(), d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt performs one request and reads the whole body, so that a connection
+// dropped mid-body is retried like any other transient failure.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+	defer cancel()
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// isTransient reports whether err is a network-level failure worth retrying: a timeout,
+// or a connection reset or dropped mid-request. Refused connections and failed DNS
+// lookups are not, as they persist (e.g., a wrong workspace or no network).
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the exponential delay before retry number attempt+1.
+func backoff(attempt int) time.Duration {
+	d := initialBackoff << min(attempt, 16)
+	return min(d, maxBackoff)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds.
+func parseRetryAfter(v string) time.Duration {
+	s, err := strconv.Atoi(v)
+	if err != nil || s < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(s) * time.Second
+}
+
+// withJitter adds up to 25% random jitter so concurrent callers don't retry in lockstep.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int64N(int64(d)/4+1)) //nolint:gosec // jitter does not need a secure source
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,200 @@
+package slack_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func response(status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+	}
+}
+
+func TestRetryTransport_RetriesThenSucceeds(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return response(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}}), nil
+		}
+		return response(http.StatusOK, nil), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/conversations.history", nil)
+	resp, err := slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("made %d calls, want 3", calls)
+	}
+}
+
+// A missing Retry-After header must not break retries.
+func TestRetryTransport_MissingRetryAfter(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return response(http.StatusTooManyRequests, nil), nil
+		}
+		return response(http.StatusOK, nil), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+	resp, err := slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("made %d calls, want 2", calls)
+	}
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return response(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"0"}}), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+	_, err := slack.NewRetryTransport(base, 2).RoundTrip(req)
+	if !errors.Is(err, slack.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	// One initial attempt plus two retries.
+	if calls != 3 {
+		t.Errorf("made %d calls, want 3", calls)
+	}
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return response(http.StatusBadGateway, nil), nil
+		}
+		return response(http.StatusOK, nil), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+	resp, err := slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("status = %d after %d calls, want 200 after 2", resp.StatusCode, calls)
+	}
+}
+
+func TestRetryTransport_RetriesConnectionReset(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return nil, syscall.ECONNRESET
+		}
+		return response(http.StatusOK, nil), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+	resp, err := slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 3 {
+		t.Errorf("made %d calls, want 3", calls)
+	}
+}
+
+// Permanent failures (client errors, non-network errors) are returned immediately.
+func TestRetryTransport_DoesNotRetryPermanentFailures(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return response(http.StatusNotFound, nil), nil
+	})
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+	resp, err := slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("made %d calls for a 404, want 1", calls)
+	}
+
+	calls = 0
+	permanent := errors.New("certificate signed by unknown authority")
+	base = roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		calls++
+		return nil, permanent
+	})
+	_, err = slack.NewRetryTransport(base, 5).RoundTrip(req)
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the permanent error after 1", err, calls)
+	}
+}
+
+// Refused connections and failed DNS lookups are not retried; resets are.
+func TestRetryTransport_NetworkErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 1},
+		{"dns", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nope.slack.com", IsNotFound: true}}, 1},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 3},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "example.slack.com", IsTimeout: true}, 3},
+	}
+	for _, tt := range tests {
+		calls := 0
+		base := roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+			calls++
+			return nil, tt.err
+		})
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.slack.com/api/users.list", nil)
+		if _, err := slack.NewRetryTransport(base, 2).RoundTrip(req); err == nil {
+			t.Errorf("%s: got no error", tt.name)
+		}
+		if calls != tt.calls {
+			t.Errorf("%s: made %d calls, want %d", tt.name, calls, tt.calls)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package slack_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// savedAPI serves a Later list, and the one saved message that still exists.
+type savedAPI struct{}
+
+func (savedAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "saved.list":
+		return map[string]any{"ok": true, "saved_items": []any{
+			map[string]any{"item_type": "message", "item_id": "C1", "ts": "1772359200.000100", "date_created": float64(1772359200)},
+			map[string]any{"item_type": "message", "item_id": "C1", "ts": "1772445600.000100", "date_created": float64(1772445600), "date_due": float64(1772532000)},
+			map[string]any{"item_type": "message", "item_id": "C2", "ts": "1772100000.000100", "date_created": float64(1772100000), "date_completed": float64(1772200000)},
+			map[string]any{"item_type": "message", "item_id": "C1", "ts": "1772500000.000100", "date_created": float64(1772500000), "state": "archived"},
+		}}, nil
+	case "conversations.history":
+		if params["latest"] == "1772445600.000100" {
+			return map[string]any{"ok": true, "messages": []any{map[string]any{"ts": params["latest"], "text": "review the RFC"}}}, nil
+		}
+		return map[string]any{"ok": true, "messages": []any{}}, nil
+	}
+	return nil, &slack.APIError{Method: method, Code: "thread_not_found"}
+}
+
+func TestListSavedItems(t *testing.T) {
+	items, err := slack.ListSavedItems(t.Context(), savedAPI{}, slack.SavedOptions{State: slack.SavedInProgress})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want the 2 in progress", len(items))
+	}
+	due := items[0]
+	if due.TS != "1772445600.000100" || due.DueAt != "2026-03-03T10:00:00Z" || due.Message["text"] != "review the RFC" {
+		t.Errorf("first item = %+v, want the one due, with its message", due)
+	}
+	if items[1].TS != "1772359200.000100" || items[1].Message != nil {
+		t.Errorf("second item = %+v, want the deleted message, without one", items[1])
+	}
+
+	all, err := slack.ListSavedItems(t.Context(), savedAPI{}, slack.SavedOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := map[string]int{}
+	for _, item := range all {
+		states[item.State]++
+	}
+	if states[slack.SavedInProgress] != 2 || states[slack.SavedCompleted] != 1 || states[slack.SavedArchived] != 1 {
+		t.Errorf("got states %v, want 2 in progress, 1 completed, 1 archived", states)
+	}
+}
@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ScheduledMessage is a message queued to be posted later.
+type ScheduledMessage struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"`
+	PostAt      string `json:"post_at"`      // RFC 3339, UTC
+	DateCreated string `json:"date_created"` // RFC 3339, UTC
+	Text        string `json:"text"`
+}
+
+// ListScheduledMessages lists the messages the user has scheduled in a channel that are
+// not yet posted, soonest first.
+func ListScheduledMessages(ctx context.Context, client APIClient, channelID string) ([]ScheduledMessage, error) {
+	p := pager{
+		method: "chat.scheduledMessages.list",
+		field:  "scheduled_messages",
+		params: map[string]string{"channel": channelID},
+	}
+	var scheduled []ScheduledMessage
+	for item, err := range p.all(ctx, client) {
+		if err != nil {
+			return nil, err
+		}
+		id, _ := item["id"].(string)
+		channel, _ := item["channel_id"].(string)
+		postAt, _ := item["post_at"].(float64)
+		created, _ := item["date_created"].(float64)
+		text, _ := item["text"].(string)
+		scheduled = append(scheduled, ScheduledMessage{
+			ID:          id,
+			ChannelID:   channel,
+			PostAt:      unixTime(postAt),
+			DateCreated: unixTime(created),
+			Text:        text,
+		})
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool { return scheduled[i].PostAt < scheduled[j].PostAt })
+	return scheduled, nil
+}
+
+// unixTime formats Unix seconds as an RFC 3339 UTC time, or "" for 0.
+func unixTime(secs float64) string {
+	if secs <= 0 {
+		return ""
+	}
+	return time.Unix(int64(secs), 0).UTC().Format(time.RFC3339)
+}
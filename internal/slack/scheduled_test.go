@@ -0,0 +1,54 @@
+package slack_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// scheduledAPI serves chat.scheduledMessages.list in two pages.
+type scheduledAPI struct {
+	params []map[string]string
+}
+
+func (m *scheduledAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.params = append(m.params, params)
+	if params["cursor"] == "" {
+		return map[string]any{
+			"ok": true,
+			"scheduled_messages": []any{
+				map[string]any{"id": "Q2", "channel_id": "C0123ABC", "post_at": float64(1772535600), "date_created": float64(1772359200), "text": "later"},
+			},
+			"response_metadata": map[string]any{"next_cursor": "page2"},
+		}, nil
+	}
+	return map[string]any{
+		"ok": true,
+		"scheduled_messages": []any{
+			map[string]any{"id": "Q1", "channel_id": "C0123ABC", "post_at": float64(1772449200), "date_created": float64(1772359200), "text": "sooner"},
+		},
+	}, nil
+}
+
+func TestListScheduledMessages(t *testing.T) {
+	mock := &scheduledAPI{}
+	scheduled, err := slack.ListScheduledMessages(t.Context(), mock, "C0123ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mock.params) != 2 || mock.params[0]["channel"] != "C0123ABC" {
+		t.Errorf("got requests %v, want two pages for C0123ABC", mock.params)
+	}
+	if len(scheduled) != 2 {
+		t.Fatalf("got %d scheduled messages, want 2", len(scheduled))
+	}
+	want := slack.ScheduledMessage{ID: "Q1", ChannelID: "C0123ABC", PostAt: "2026-03-02T11:00:00Z", DateCreated: "2026-03-01T10:00:00Z", Text: "sooner"}
+	if scheduled[0] != want {
+		t.Errorf("got %+v, want %+v", scheduled[0], want)
+	}
+	if scheduled[1].ID != "Q2" {
+		t.Errorf("got %s second, want Q2", scheduled[1].ID)
+	}
+}
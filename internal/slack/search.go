@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// searchPageSize is the number of matches requested per search.messages page (its maximum).
+const searchPageSize = 100
+
+// SearchMessages streams the messages matching a search query (Slack search syntax, e.g.
+// "from:<@U0123ABCD> after:2026-01-31"), newest first, fetching pages as they are consumed.
+// Slack search does not cover every conversation a history scan does (nor, usually,
+// messages from the last few seconds). Each match's channel object is flattened into
+// "channel" (the ID) and "channel_name", as a history message's channel would be.
+func SearchMessages(ctx context.Context, client APIClient, query string) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		for page := 1; ; page++ {
+			resp, err := client.API(ctx, "search.messages", map[string]string{
+				"query":    query,
+				"count":    strconv.Itoa(searchPageSize),
+				"page":     strconv.Itoa(page),
+				"sort":     "timestamp",
+				"sort_dir": "desc",
+			})
+			if err != nil {
+				yield(nil, fmt.Errorf("search.messages: %w", err))
+				return
+			}
+			messages, _ := resp["messages"].(map[string]any)
+			matches, _ := messages["matches"].([]any)
+			for _, m := range matches {
+				msg, _ := m.(map[string]any)
+				if msg == nil {
+					continue
+				}
+				if channel, ok := msg["channel"].(map[string]any); ok {
+					msg["channel"], _ = channel["id"].(string)
+					msg["channel_name"], _ = channel["name"].(string)
+				}
+				if !yield(msg, nil) {
+					return
+				}
+			}
+			paging, _ := messages["paging"].(map[string]any)
+			pages, _ := paging["pages"].(float64)
+			if len(matches) == 0 || float64(page) >= pages {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package slack_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// searchAPI serves two pages of search.messages matches.
+type searchAPI struct {
+	pages []string
+}
+
+func (m *searchAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.pages = append(m.pages, params["page"])
+	match := func(ts string) any {
+		return map[string]any{"ts": ts, "user": "U1", "channel": map[string]any{"id": "C1", "name": "eng"}}
+	}
+	matches := []any{match("1770165300.000100"), match("1770165200.000100")}
+	if params["page"] == "2" {
+		matches = []any{match("1770165100.000100")}
+	}
+	return map[string]any{"ok": true, "messages": map[string]any{
+		"matches": matches,
+		"paging":  map[string]any{"page": params["page"], "pages": float64(2)},
+	}}, nil
+}
+
+func TestSearchMessages(t *testing.T) {
+	api := &searchAPI{}
+	var tss []string
+	for msg, err := range slack.SearchMessages(t.Context(), api, "from:<@U1>") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg["channel"] != "C1" || msg["channel_name"] != "eng" {
+			t.Errorf("channel = %v, %v; want C1, eng", msg["channel"], msg["channel_name"])
+		}
+		ts, _ := msg["ts"].(string)
+		tss = append(tss, ts)
+	}
+	if len(tss) != 3 || len(api.pages) != 2 {
+		t.Errorf("got %v from pages %v, want 3 matches from 2 pages", tss, api.pages)
+	}
+}
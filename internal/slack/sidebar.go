@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// sidebarSectionNames names the built-in sidebar sections, which Slack returns unnamed.
+var sidebarSectionNames = map[string]string{
+	"channels":        "Channels",
+	"direct_messages": "Direct messages",
+	"stars":           "Starred",
+	"slack_connect":   "External connections",
+	"recent_apps":     "Apps",
+}
+
+// Sidebar is the current user's sidebar arrangement: which section each conversation
+// is filed under, and which conversations are muted.
+type Sidebar struct {
+	Sections map[string]string // conversation ID -> section name
+	Muted    map[string]bool   // conversation ID -> muted
+}
+
+// GetSidebar reads the current user's muted conversations (users.prefs.get) and sidebar
+// sections (users.channelSections.list). A section lists only its first page of
+// conversations; the rest are paged in with users.channelSections.channels.list.
+func GetSidebar(ctx context.Context, client APIClient) (*Sidebar, error) {
+	muted, err := MutedConversations(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	s := &Sidebar{Sections: make(map[string]string), Muted: muted}
+
+	resp, err := client.API(ctx, "users.channelSections.list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("users.channelSections.list: %w", err)
+	}
+	sections, _ := resp["channel_sections"].([]any)
+	for _, sec := range sections {
+		section, _ := sec.(map[string]any)
+		name, _ := section["name"].(string)
+		if name == "" {
+			kind, _ := section["type"].(string)
+			name = sidebarSectionNames[kind]
+		}
+		page, _ := section["channel_ids_page"].(map[string]any)
+		sectionID, _ := section["channel_section_id"].(string)
+		ids, err := sectionChannelIDs(ctx, client, sectionID, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			s.Sections[id] = name
+		}
+	}
+	return s, nil
+}
+
+// sectionChannelIDs returns the conversation IDs of a sidebar section, from its first
+// channel_ids_page and the pages after it, until next_cursor is empty.
+func sectionChannelIDs(ctx context.Context, client APIClient, sectionID string, page map[string]any) ([]string, error) {
+	var ids []string
+	for {
+		ids = append(ids, stringList(page["channel_ids"])...)
+		cursor, _ := page["next_cursor"].(string)
+		if cursor == "" || sectionID == "" {
+			return ids, nil
+		}
+		resp, err := client.API(ctx, "users.channelSections.channels.list", map[string]string{
+			"channel_section_id": sectionID,
+			"cursor":             cursor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("users.channelSections.channels.list: %w", err)
+		}
+		page, _ = resp["channel_ids_page"].(map[string]any)
+	}
+}
+
+// MutedConversations returns the IDs of the conversations the current user has muted,
+// from the muted_channels preference.
+func MutedConversations(ctx context.Context, client APIClient) (map[string]bool, error) {
+	resp, err := client.API(ctx, "users.prefs.get", nil)
+	if err != nil {
+		return nil, fmt.Errorf("users.prefs.get: %w", err)
+	}
+	prefs, _ := resp["prefs"].(map[string]any)
+	list, _ := prefs["muted_channels"].(string)
+
+	muted := make(map[string]bool)
+	for id := range strings.SplitSeq(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			muted[id] = true
+		}
+	}
+	return muted, nil
+}
+
+// Annotate adds the sidebar "section" (when filed under one) and "muted" fields to a
+// conversation object in place.
+func (s *Sidebar) Annotate(channel map[string]any) {
+	id, _ := channel["id"].(string)
+	if section := s.Sections[id]; section != "" {
+		channel["section"] = section
+	}
+	channel["muted"] = s.Muted[id]
+}
@@ -0,0 +1,97 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// sidebarAPI serves users.prefs.get and users.channelSections.list.
+type sidebarAPI struct{}
+
+func (sidebarAPI) API(_ context.Context, method string, _ map[string]string) (map[string]any, error) {
+	switch method {
+	case "users.prefs.get":
+		return map[string]any{"ok": true, "prefs": map[string]any{"muted_channels": "C2, C3"}}, nil
+	case "users.channelSections.list":
+		return map[string]any{"ok": true, "channel_sections": []any{
+			map[string]any{"name": "Projects", "type": "standard",
+				"channel_ids_page": map[string]any{"channel_ids": []any{"C1", "C2"}}},
+			map[string]any{"name": "", "type": "channels",
+				"channel_ids_page": map[string]any{"channel_ids": []any{"C4"}}},
+		}}, nil
+	}
+	return nil, errors.New("unexpected method " + method)
+}
+
+func TestGetSidebar(t *testing.T) {
+	s, err := slack.GetSidebar(t.Context(), sidebarAPI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]struct {
+		section string
+		muted   bool
+	}{
+		"C1": {"Projects", false},
+		"C2": {"Projects", true},
+		"C3": {"", true},
+		"C4": {"Channels", false},
+	} {
+		channel := map[string]any{"id": id}
+		s.Annotate(channel)
+		section, _ := channel["section"].(string)
+		muted, _ := channel["muted"].(bool)
+		if section != want.section || muted != want.muted {
+			t.Errorf("%s: got section %q, muted %v; want %q, %v", id, section, muted, want.section, want.muted)
+		}
+	}
+}
+
+// pagedSidebarAPI serves a section whose conversations span three pages.
+type pagedSidebarAPI struct {
+	cursors []string
+}
+
+func (m *pagedSidebarAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "users.prefs.get":
+		return map[string]any{"ok": true, "prefs": map[string]any{}}, nil
+	case "users.channelSections.list":
+		return map[string]any{"ok": true, "channel_sections": []any{
+			map[string]any{"channel_section_id": "S1", "name": "Projects", "type": "standard",
+				"channel_ids_page": map[string]any{"channel_ids": []any{"C1"}, "next_cursor": "p2"}},
+		}}, nil
+	case "users.channelSections.channels.list":
+		if params["channel_section_id"] != "S1" {
+			return nil, errors.New("unexpected section " + params["channel_section_id"])
+		}
+		m.cursors = append(m.cursors, params["cursor"])
+		page := map[string]any{"channel_ids": []any{"C2"}, "next_cursor": "p3"}
+		if params["cursor"] == "p3" {
+			page = map[string]any{"channel_ids": []any{"C3"}, "next_cursor": ""}
+		}
+		return map[string]any{"ok": true, "channel_ids_page": page}, nil
+	}
+	return nil, errors.New("unexpected method " + method)
+}
+
+func TestGetSidebar_Pages(t *testing.T) {
+	api := &pagedSidebarAPI{}
+	s, err := slack.GetSidebar(t.Context(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"C1", "C2", "C3"} {
+		if s.Sections[id] != "Projects" {
+			t.Errorf("%s: got section %q, want Projects", id, s.Sections[id])
+		}
+	}
+	if want := []string{"p2", "p3"}; !slices.Equal(api.cursors, want) {
+		t.Errorf("got cursors %v, want %v", api.cursors, want)
+	}
+}
@@ -0,0 +1,63 @@
+package slack
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConversationSortKeys are the orders SortConversations accepts.
+var ConversationSortKeys = []string{"name", "members", "created", "recent-activity"}
+
+// SortConversations orders conversation objects in place: by name (A to Z), members
+// (most first), created (newest first), or recent-activity (most recently active first).
+// Ties are broken by name.
+func SortConversations(channels []map[string]any, by string) error {
+	var key func(c map[string]any) float64
+	switch by {
+	case "name":
+	case "members":
+		key = func(c map[string]any) float64 { n, _ := c["num_members"].(float64); return n }
+	case "created":
+		key = func(c map[string]any) float64 { n, _ := c["created"].(float64); return n }
+	case "recent-activity":
+		key = lastActivity
+	default:
+		return fmt.Errorf("invalid sort %q (want %s)", by, strings.Join(ConversationSortKeys, ", "))
+	}
+
+	slices.SortStableFunc(channels, func(a, b map[string]any) int {
+		if key != nil {
+			// Descending: larger values first.
+			if c := cmp.Compare(key(b), key(a)); c != 0 {
+				return c
+			}
+		}
+		nameA, _ := a["name"].(string)
+		nameB, _ := b["name"].(string)
+		return cmp.Compare(nameA, nameB)
+	})
+	return nil
+}
+
+// lastActivity estimates when a conversation was last active, in Unix seconds: its latest
+// message when known (from EnrichChannels or Slack's latest field), else its updated time
+// (in milliseconds), else its creation.
+func lastActivity(c map[string]any) float64 {
+	ts, _ := c["last_message_ts"].(string)
+	if latest, _ := c["latest"].(map[string]any); ts == "" && latest != nil {
+		ts, _ = latest["ts"].(string)
+	}
+	if ts != "" {
+		if secs, err := strconv.ParseFloat(ts, 64); err == nil {
+			return secs
+		}
+	}
+	if updated, _ := c["updated"].(float64); updated > 0 {
+		return updated / 1000
+	}
+	created, _ := c["created"].(float64)
+	return created
+}
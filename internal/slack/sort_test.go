@@ -0,0 +1,44 @@
+package slack_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestSortConversations(t *testing.T) {
+	channels := func() []map[string]any {
+		return []map[string]any{
+			{"name": "random", "num_members": float64(50), "created": float64(300), "updated": float64(900_000)},
+			{"name": "general", "num_members": float64(100), "created": float64(100), "updated": float64(500_000)},
+			{"name": "eng", "num_members": float64(20), "created": float64(200), "latest": map[string]any{"ts": "1000.000100"}},
+		}
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		{"name", []string{"eng", "general", "random"}},
+		{"members", []string{"general", "random", "eng"}},
+		{"created", []string{"random", "eng", "general"}},
+		{"recent-activity", []string{"eng", "random", "general"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			got := channels()
+			if err := slack.SortConversations(got, tt.by); err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range got {
+				if c["name"] != tt.want[i] {
+					t.Fatalf("got %v at %d, want order %v", c["name"], i, tt.want)
+				}
+			}
+		})
+	}
+
+	if err := slack.SortConversations(channels(), "size"); err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
@@ -0,0 +1,104 @@
+package slack
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Stats accumulates API usage. The zero value is ready to use, and a nil *Stats records nothing.
+type Stats struct {
+	mu          sync.Mutex
+	calls       map[string]int
+	callTime    map[string]time.Duration
+	bytes       int64
+	retries     int
+	rateLimited int
+	cacheHits   int
+	cacheMisses int
+}
+
+// StatsSummary is a point-in-time copy of Stats.
+type StatsSummary struct {
+	Calls        map[string]int     `json:"calls"`
+	CallSeconds  map[string]float64 `json:"call_seconds"` // total time spent in each method's calls, retries included
+	TotalCalls   int                `json:"total_calls"`
+	Bytes        int64              `json:"bytes"`
+	Retries      int                `json:"retries"`
+	RateLimited  int                `json:"rate_limited"` // retries caused by rate limiting
+	CacheHits    int                `json:"cache_hits"`
+	CacheMisses  int                `json:"cache_misses"`
+	CacheHitRate float64            `json:"cache_hit_rate"`
+}
+
+func (s *Stats) recordCall(method string, bytes int, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls == nil {
+		s.calls = make(map[string]int)
+		s.callTime = make(map[string]time.Duration)
+	}
+	s.calls[method]++
+	s.callTime[method] += elapsed
+	s.bytes += int64(bytes)
+}
+
+func (s *Stats) recordRetry(rateLimited bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+	if rateLimited {
+		s.rateLimited++
+	}
+}
+
+func (s *Stats) recordCache(hit bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+// Summary returns a copy of the counters collected so far.
+func (s *Stats) Summary() StatsSummary {
+	if s == nil {
+		return StatsSummary{Calls: map[string]int{}, CallSeconds: map[string]float64{}}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summary := StatsSummary{
+		Calls:       maps.Clone(s.calls),
+		CallSeconds: make(map[string]float64, len(s.callTime)),
+		Bytes:       s.bytes,
+		Retries:     s.retries,
+		RateLimited: s.rateLimited,
+		CacheHits:   s.cacheHits,
+		CacheMisses: s.cacheMisses,
+	}
+	if summary.Calls == nil {
+		summary.Calls = map[string]int{}
+	}
+	for method, d := range s.callTime {
+		summary.CallSeconds[method] = d.Seconds()
+	}
+	for _, n := range s.calls {
+		summary.TotalCalls += n
+	}
+	if lookups := s.cacheHits + s.cacheMisses; lookups > 0 {
+		summary.CacheHitRate = float64(s.cacheHits) / float64(lookups)
+	}
+	return summary
+}
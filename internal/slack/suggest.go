@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps how many near-miss names an error suggests.
+const maxSuggestions = 3
+
+// suggestNames returns the candidates closest to name: those within a small edit
+// distance, or sharing a prefix with it (e.g., "general" and "general-dev").
+func suggestNames(name string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+
+	// Allow roughly one typo per three characters, up to three.
+	threshold := min(max(len(name)/3, 1), 3)
+
+	var matches []scored
+	for _, c := range candidates {
+		d := editDistance(name, c)
+		if d <= threshold || strings.HasPrefix(c, name) || strings.HasPrefix(name, c) {
+			matches = append(matches, scored{c, d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var names []string
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Fuzzy match tiers, best first.
+const (
+	tierExact     = iota // same name, ignoring case
+	tierPrefix           // name starts the candidate
+	tierSubstring        // name appears within the candidate
+	tierWords            // every word of name is a word of the candidate
+	tierTypo             // within suggestNames' edit distance threshold
+	tierNone
+)
+
+// fuzzyMatch ranks candidates against a partial or misspelled name. It returns the best
+// match when it is unambiguous (alone in the best tier), and the top-ranked candidates.
+func fuzzyMatch(name string, candidates []string) (string, []string) {
+	type scored struct {
+		name     string
+		tier     int
+		distance int
+	}
+
+	name = strings.ToLower(name)
+	threshold := min(max(len(name)/3, 1), 3)
+
+	var matches []scored
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := editDistance(name, lc)
+		tier := tierNone
+		switch {
+		case lc == name:
+			tier = tierExact
+		case strings.HasPrefix(lc, name):
+			tier = tierPrefix
+		case strings.Contains(lc, name):
+			tier = tierSubstring
+		case containsWords(lc, name):
+			tier = tierWords
+		case d <= threshold:
+			tier = tierTypo
+		}
+		if tier != tierNone {
+			matches = append(matches, scored{c, tier, d})
+		}
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].tier != matches[j].tier {
+			return matches[i].tier < matches[j].tier
+		}
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var ranked []string
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		ranked = append(ranked, m.name)
+	}
+	if len(matches) > 1 && matches[1].tier == matches[0].tier {
+		return "", ranked
+	}
+	return matches[0].name, ranked
+}
+
+// containsWords reports whether every word of name (split on - and _) is a word of candidate.
+func containsWords(candidate, name string) bool {
+	isSep := func(r rune) bool { return r == '-' || r == '_' }
+	words := strings.FieldsFunc(candidate, isSep)
+	wanted := strings.FieldsFunc(name, isSep)
+	if len(wanted) == 0 {
+		return false
+	}
+	for _, w := range wanted {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package slack_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestSuggestNames(t *testing.T) {
+	candidates := []string{"general", "general-dev", "general-uk", "random", "engineering"}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"genral", []string{"general"}},
+		{"general-de", []string{"general-dev", "general-uk", "general"}},
+		{"randm", []string{"random"}},
+		{"marketing", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slack.SuggestNames(tt.name, candidates)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SuggestNames(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	candidates := []string{"team-backend-platform-eng", "team-frontend", "backend-oncall", "general", "general-dev"}
+
+	tests := []struct {
+		name     string
+		wantBest string
+		wantTop  string
+	}{
+		{"platform", "team-backend-platform-eng", "team-backend-platform-eng"}, // unique substring
+		{"backend", "backend-oncall", "backend-oncall"},                        // prefix beats substring
+		{"backend-eng", "team-backend-platform-eng", "team-backend-platform-eng"},
+		{"genral", "general", "general"},
+		{"team", "", "team-frontend"}, // two prefix matches: ambiguous, closest first
+		{"marketing", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, ranked := slack.FuzzyMatch(tt.name, candidates)
+			if best != tt.wantBest {
+				t.Errorf("best = %q, want %q", best, tt.wantBest)
+			}
+			top := ""
+			if len(ranked) > 0 {
+				top = ranked[0]
+			}
+			if top != tt.wantTop {
+				t.Errorf("top ranked = %q (of %v), want %q", top, ranked, tt.wantTop)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package slack
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snippetLength is the most runes of a thread root's text kept in a ThreadSummary.
+const snippetLength = 80
+
+// ThreadSummary is an overview of one thread: its root message and reply activity.
+type ThreadSummary struct {
+	ChannelID        string   `json:"channel_id"`
+	TS               string   `json:"ts"`
+	User             string   `json:"user,omitempty"`
+	Author           string   `json:"author,omitempty"` // display name of User, when resolved
+	Snippet          string   `json:"snippet"`
+	ReplyCount       int      `json:"reply_count"`
+	ReplyUsers       []string `json:"reply_users,omitempty"`  // as embedded in history; Slack lists a handful at most
+	Participants     []string `json:"participants,omitempty"` // display names of ReplyUsers, when resolved
+	ParticipantCount int      `json:"participant_count"`
+	LatestReply      string   `json:"latest_reply,omitempty"`
+	LastActivity     string   `json:"last_activity,omitempty"` // time of LatestReply, RFC 3339, UTC
+	Permalink        string   `json:"permalink,omitempty"`
+}
+
+// ListThreads scans a channel's history in the window opts describes for messages with
+// replies, and summarizes those threads, most recently active first. Only roots posted in
+// the window are found; replies are not fetched.
+func ListThreads(ctx context.Context, client APIClient, channelID string, opts HistoryOptions) ([]*ThreadSummary, error) {
+	var threads []*ThreadSummary
+	for msg, err := range IterChannelHistory(ctx, client, channelID, opts) {
+		if err != nil {
+			return nil, err
+		}
+		if t := summarizeThread(channelID, msg); t != nil {
+			threads = append(threads, t)
+		}
+	}
+	slices.SortStableFunc(threads, func(a, b *ThreadSummary) int {
+		return cmp.Compare(b.LatestReply, a.LatestReply)
+	})
+	return threads, nil
+}
+
+// summarizeThread returns the summary of a thread root, or nil if msg has no replies.
+func summarizeThread(channelID string, msg map[string]any) *ThreadSummary {
+	replyCount, _ := msg["reply_count"].(float64)
+	if replyCount == 0 {
+		return nil
+	}
+	t := &ThreadSummary{ChannelID: channelID, ReplyCount: int(replyCount)}
+	t.TS, _ = msg["ts"].(string)
+	t.User, _ = msg["user"].(string)
+	text, _ := msg["text"].(string)
+	t.Snippet = snippet(text, snippetLength)
+	t.ReplyUsers = stringList(msg["reply_users"])
+	participants, _ := msg["reply_users_count"].(float64)
+	t.ParticipantCount = max(int(participants), len(t.ReplyUsers))
+	t.LatestReply, _ = msg["latest_reply"].(string)
+	if tm, err := ParseTimestamp(t.LatestReply); err == nil {
+		t.LastActivity = tm.UTC().Format(time.RFC3339)
+	}
+	return t
+}
+
+// SubscribedThread is a thread in the current user's Threads view: one they started,
+// replied to, or follow.
+type SubscribedThread struct {
+	ThreadSummary
+	UnreadCount   int              `json:"unread_count"`
+	UnreadReplies []map[string]any `json:"unread_replies,omitempty"`
+}
+
+// SubscribedThreadOptions controls what ListSubscribedThreads returns.
+type SubscribedThreadOptions struct {
+	UnreadOnly bool // only threads with unread replies
+	Limit      int  // maximum number of threads (0 = 20)
+}
+
+// threadsPageSize is the most threads subscriptions.thread.getView returns per call.
+const threadsPageSize = 50
+
+// ListSubscribedThreads returns the threads in the current user's Threads view, most
+// recently active first, with their unread replies. It uses subscriptions.thread.getView,
+// the undocumented method behind the Slack client's Threads view.
+func ListSubscribedThreads(ctx context.Context, client APIClient, opts SubscribedThreadOptions) ([]*SubscribedThread, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var threads []*SubscribedThread
+	maxTS := ""
+	for len(threads) < limit {
+		params := map[string]string{"limit": strconv.Itoa(threadsPageSize)}
+		if maxTS != "" {
+			params["max_ts"] = maxTS
+		}
+		resp, err := client.API(ctx, "subscriptions.thread.getView", params)
+		if err != nil {
+			return nil, fmt.Errorf("subscriptions.thread.getView: %w", err)
+		}
+
+		items, _ := resp["threads"].([]any)
+		for _, it := range items {
+			item, _ := it.(map[string]any)
+			root, _ := item["root_msg"].(map[string]any)
+			if root == nil {
+				continue
+			}
+			channelID, _ := root["channel"].(string)
+			summary := summarizeThread(channelID, root)
+			if summary == nil {
+				continue
+			}
+			t := &SubscribedThread{ThreadSummary: *summary}
+			t.UnreadReplies = toMessages(item["unread_replies"])
+			t.UnreadCount = len(t.UnreadReplies)
+			if opts.UnreadOnly && t.UnreadCount == 0 {
+				continue
+			}
+			if len(threads) < limit {
+				threads = append(threads, t)
+			}
+		}
+
+		hasMore, _ := resp["has_more"].(bool)
+		next := oldestActivity(items)
+		if !hasMore || next == "" || next == maxTS {
+			break
+		}
+		maxTS = next
+	}
+	return threads, nil
+}
+
+// oldestActivity returns the earliest latest_reply among the roots of a Threads view page,
+// which bounds the next page.
+func oldestActivity(items []any) string {
+	oldest := ""
+	for _, it := range items {
+		item, _ := it.(map[string]any)
+		root, _ := item["root_msg"].(map[string]any)
+		latest, _ := root["latest_reply"].(string)
+		if latest != "" && (oldest == "" || latest < oldest) {
+			oldest = latest
+		}
+	}
+	return oldest
+}
+
+// toMessages returns the message objects in a JSON array.
+func toMessages(v any) []map[string]any {
+	items, _ := v.([]any)
+	var out []map[string]any
+	for _, it := range items {
+		if msg, _ := it.(map[string]any); msg != nil {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// snippet returns the first line of text, cut to at most n runes with an ellipsis.
+func snippet(text string, n int) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	runes := []rune(strings.TrimSpace(line))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
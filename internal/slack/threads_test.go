@@ -0,0 +1,108 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// threadsAPI serves one page of channel history containing two threads.
+type threadsAPI struct{}
+
+func (threadsAPI) API(_ context.Context, _ string, _ map[string]string) (map[string]any, error) {
+	return map[string]any{"ok": true, "messages": []any{
+		map[string]any{"ts": "1770165300.000100", "user": "U1", "text": "no replies here"},
+		map[string]any{
+			"ts": "1770165200.000100", "user": "U2", "text": "Deploy plan for Friday\nstep one: ...",
+			"reply_count": float64(4), "reply_users_count": float64(3), "reply_users": []any{"U1", "U3"},
+			"latest_reply": "1770170000.000100",
+		},
+		map[string]any{
+			"ts": "1770165100.000100", "user": "U1", "text": "Who owns the flaky test? " + longText,
+			"reply_count": float64(1), "reply_users": []any{"U2"}, "latest_reply": "1770180000.000100",
+		},
+	}}, nil
+}
+
+const longText = "It has been failing on main for two days and blocks every merge to the release branch."
+
+func TestListThreads(t *testing.T) {
+	threads, err := slack.ListThreads(t.Context(), threadsAPI{}, "C1", slack.HistoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("got %d threads, want 2", len(threads))
+	}
+
+	// Most recently active first.
+	if threads[0].TS != "1770165100.000100" || threads[1].TS != "1770165200.000100" {
+		t.Errorf("order = %s, %s; want the later latest_reply first", threads[0].TS, threads[1].TS)
+	}
+	if got := []rune(threads[0].Snippet); len(got) != 80 || got[len(got)-1] != '…' {
+		t.Errorf("snippet = %q, want 80 runes ending in an ellipsis", threads[0].Snippet)
+	}
+
+	deploy := threads[1]
+	if deploy.Snippet != "Deploy plan for Friday" || deploy.ReplyCount != 4 || deploy.ParticipantCount != 3 {
+		t.Errorf("thread = %+v", deploy)
+	}
+	if deploy.LastActivity != "2026-02-04T01:53:20Z" {
+		t.Errorf("last activity = %q", deploy.LastActivity)
+	}
+}
+
+// threadsViewAPI serves two pages of subscriptions.thread.getView, paged by max_ts.
+type threadsViewAPI struct {
+	maxTS []string
+}
+
+func (m *threadsViewAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	if method != "subscriptions.thread.getView" {
+		return nil, fmt.Errorf("unexpected method %s", method)
+	}
+	m.maxTS = append(m.maxTS, params["max_ts"])
+	thread := func(channel, ts, latest string, unread int) any {
+		replies := make([]any, unread)
+		for i := range replies {
+			replies[i] = map[string]any{"ts": latest, "thread_ts": ts, "text": "new reply"}
+		}
+		return map[string]any{
+			"root_msg": map[string]any{
+				"channel": channel, "ts": ts, "user": "U1", "text": "root " + ts,
+				"reply_count": float64(5), "latest_reply": latest,
+			},
+			"unread_replies": replies,
+		}
+	}
+	if params["max_ts"] == "" {
+		return map[string]any{"ok": true, "has_more": true, "threads": []any{
+			thread("C1", "1770160000.000100", "1770170000.000100", 2),
+			thread("C2", "1770160000.000200", "1770169000.000100", 0),
+		}}, nil
+	}
+	return map[string]any{"ok": true, "has_more": false, "threads": []any{
+		thread("C3", "1770150000.000100", "1770160000.000100", 1),
+	}}, nil
+}
+
+func TestListSubscribedThreads(t *testing.T) {
+	mock := &threadsViewAPI{}
+	threads, err := slack.ListSubscribedThreads(t.Context(), mock, slack.SubscribedThreadOptions{UnreadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(threads) != 2 || threads[0].ChannelID != "C1" || threads[1].ChannelID != "C3" {
+		t.Fatalf("threads = %+v, want C1 and C3 (with unread replies)", threads)
+	}
+	if threads[0].UnreadCount != 2 || threads[0].ReplyCount != 5 || len(threads[0].UnreadReplies) != 2 {
+		t.Errorf("thread = %+v", threads[0])
+	}
+	if want := []string{"", "1770169000.000100"}; !slices.Equal(mock.maxTS, want) {
+		t.Errorf("max_ts params = %v, want %v", mock.maxTS, want)
+	}
+}
@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// traceBodyLimit is how much of each response body HTTP tracing logs.
+const traceBodyLimit = 2048
+
+// secretPattern matches Slack tokens (xoxc-, xoxp-, xoxb-, ...) and session cookies (xoxd-).
+var secretPattern = regexp.MustCompile(`xox[a-z]-[A-Za-z0-9%._-]+`)
+
+// secretHeaders are request headers whose values are never logged.
+var secretHeaders = []string{"Authorization", "Cookie"}
+
+// traceTransport logs each HTTP request and response at debug level: the method, URL
+// and query parameters, headers, status, timing, and the start of the response body.
+// Tokens and cookies are redacted wherever they appear.
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	slog.Debug("http request",
+		"method", req.Method,
+		"url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path,
+		"params", redactParams(req.URL.Query()),
+		"headers", redactHeaders(req.Header))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		slog.Debug("http error", "url", req.URL.Path, "elapsed", elapsed, "error", redact(err.Error()))
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		slog.Debug("http error", "url", req.URL.Path, "status", resp.StatusCode, "elapsed", elapsed, "error", err)
+		// The caller reads what arrived, then the error, as from the original body.
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+		return resp, nil
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	logged := body
+	if len(logged) > traceBodyLimit {
+		logged = logged[:traceBodyLimit]
+	}
+	slog.Debug("http response",
+		"url", req.URL.Path,
+		"status", resp.StatusCode,
+		"elapsed", elapsed,
+		"bytes", len(body),
+		"body", redact(string(logged)))
+	return resp, nil
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// redact replaces tokens and cookies in s.
+func redact(s string) string {
+	return secretPattern.ReplaceAllString(s, "[REDACTED]")
+}
+
+// redactParams formats query parameters for logging, hiding tokens.
+func redactParams(params url.Values) map[string]string {
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		value := strings.Join(v, ",")
+		if k == "token" {
+			value = "[REDACTED]"
+		}
+		out[k] = redact(value)
+	}
+	return out
+}
+
+// redactHeaders formats request headers for logging, hiding credentials.
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		value := strings.Join(v, ", ")
+		for _, secret := range secretHeaders {
+			if http.CanonicalHeaderKey(k) == secret {
+				value = "[REDACTED]"
+			}
+		}
+		out[k] = redact(value)
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Unread is a conversation with unread messages.
+type Unread struct {
+	ChannelID    string           `json:"channel_id"`
+	Name         string           `json:"name,omitempty"`
+	User         string           `json:"user,omitempty"` // the other party, for DMs
+	UnreadCount  int              `json:"unread_count"`
+	MentionCount int              `json:"mention_count"`
+	LastRead     string           `json:"last_read,omitempty"`
+	LastReadAt   string           `json:"last_read_at,omitempty"` // RFC 3339, UTC
+	Messages     []map[string]any `json:"messages,omitempty"`
+}
+
+// UnreadOptions controls what ListUnreads fetches.
+type UnreadOptions struct {
+	// Fetch pulls each conversation's unread messages (those after last_read).
+	Fetch bool
+
+	// Limit bounds the messages fetched per conversation (0 = all unread).
+	Limit int
+}
+
+// ListUnreads returns the current user's conversations with unread messages, most unread
+// first. client.counts finds them, and conversations.info gives each one's name and
+// unread count. Thread replies are not counted.
+func ListUnreads(ctx context.Context, client APIClient, opts UnreadOptions) ([]*Unread, error) {
+	resp, err := client.API(ctx, "client.counts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("client.counts: %w", err)
+	}
+
+	var unreads []*Unread
+	for _, group := range []string{"channels", "mpims", "ims"} {
+		items, _ := resp[group].([]any)
+		for _, it := range items {
+			item, _ := it.(map[string]any)
+			hasUnreads, _ := item["has_unreads"].(bool)
+			dms, _ := item["dm_count"].(float64)
+			id, _ := item["id"].(string)
+			if id == "" || (!hasUnreads && dms == 0) {
+				continue
+			}
+			u := &Unread{ChannelID: id}
+			u.LastRead, _ = item["last_read"].(string)
+			mentions, _ := item["mention_count"].(float64)
+			u.MentionCount = int(max(mentions, dms))
+			unreads = append(unreads, u)
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(enrichConcurrency)
+	for _, u := range unreads {
+		g.Go(func() error {
+			return fillUnread(gctx, client, u, opts)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	unreads = slices.DeleteFunc(unreads, func(u *Unread) bool {
+		return u.UnreadCount == 0 && u.MentionCount == 0
+	})
+	slices.SortFunc(unreads, func(a, b *Unread) int {
+		return cmp.Or(b.UnreadCount-a.UnreadCount, cmp.Compare(a.Name, b.Name), cmp.Compare(a.ChannelID, b.ChannelID))
+	})
+	return unreads, nil
+}
+
+// fillUnread looks up a conversation's name and unread count, and with opts.Fetch its unread messages.
+func fillUnread(ctx context.Context, client APIClient, u *Unread, opts UnreadOptions) error {
+	channel, err := GetChannelInfo(ctx, client, u.ChannelID)
+	if err != nil {
+		return err
+	}
+	u.Name, _ = channel["name"].(string)
+	u.User, _ = channel["user"].(string)
+	count, _ := channel["unread_count_display"].(float64)
+	u.UnreadCount = int(count)
+	if last, _ := channel["last_read"].(string); last != "" {
+		u.LastRead = last
+	}
+	if t, err := ParseTimestamp(u.LastRead); err == nil && t.Unix() > 0 {
+		u.LastReadAt = t.UTC().Format(time.RFC3339)
+	}
+
+	if !opts.Fetch {
+		return nil
+	}
+	u.Messages, err = CollectMessages(IterChannelHistory(ctx, client, u.ChannelID, HistoryOptions{
+		Oldest: u.LastRead,
+		Limit:  opts.Limit,
+	}))
+	if err != nil {
+		return err
+	}
+	if u.UnreadCount == 0 && opts.Limit == 0 {
+		u.UnreadCount = len(u.Messages)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// unreadsAPI serves client.counts, conversations.info, and conversations.history for three conversations.
+type unreadsAPI struct {
+	mu      sync.Mutex
+	oldests map[string]string // channel -> oldest param of history calls
+}
+
+func (m *unreadsAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	switch method {
+	case "client.counts":
+		return map[string]any{
+			"ok": true,
+			"channels": []any{
+				map[string]any{"id": "C1", "last_read": "1770165000.000100", "has_unreads": true, "mention_count": float64(1)},
+				map[string]any{"id": "C2", "last_read": "1770165000.000100", "has_unreads": false},
+				map[string]any{"id": "C3", "last_read": "1770165000.000100", "has_unreads": true},
+			},
+			"ims": []any{
+				map[string]any{"id": "D1", "last_read": "1770165000.000100", "has_unreads": true, "dm_count": float64(2)},
+			},
+		}, nil
+	case "conversations.info":
+		info := map[string]map[string]any{
+			"C1": {"id": "C1", "name": "eng", "unread_count_display": float64(3)},
+			"C3": {"id": "C3", "name": "random", "unread_count_display": float64(0)},
+			"D1": {"id": "D1", "user": "U2", "unread_count_display": float64(5)},
+		}
+		return map[string]any{"ok": true, "channel": info[params["channel"]]}, nil
+	case "conversations.history":
+		m.mu.Lock()
+		m.oldests[params["channel"]] = params["oldest"]
+		m.mu.Unlock()
+		return map[string]any{"ok": true, "messages": []any{
+			map[string]any{"ts": "1770165100.000100", "text": "new"},
+		}}, nil
+	}
+	return nil, errors.New("unexpected method " + method)
+}
+
+func TestListUnreads(t *testing.T) {
+	api := &unreadsAPI{oldests: make(map[string]string)}
+	unreads, err := slack.ListUnreads(t.Context(), api, slack.UnreadOptions{Fetch: true, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// C2 has no unreads and C3 has none left to count; D1 has the most.
+	if len(unreads) != 2 || unreads[0].ChannelID != "D1" || unreads[1].ChannelID != "C1" {
+		t.Fatalf("got %+v, want D1 then C1", unreads)
+	}
+	if u := unreads[0]; u.User != "U2" || u.UnreadCount != 5 || u.MentionCount != 2 {
+		t.Errorf("got DM %+v", u)
+	}
+	if u := unreads[1]; u.Name != "eng" || u.UnreadCount != 3 || u.MentionCount != 1 ||
+		u.LastReadAt != "2026-02-04T00:30:00Z" || len(u.Messages) != 1 {
+		t.Errorf("got channel %+v", u)
+	}
+	if api.oldests["C1"] != "1770165000.000100" {
+		t.Errorf("history fetched from %q, want last_read", api.oldests["C1"])
+	}
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrUsergroupNotFound is returned when no user group has the requested handle or name.
+var ErrUsergroupNotFound = fmt.Errorf("user group %w", ErrNotFound)
+
+// usergroupIDPattern matches user group IDs, which Slack calls subteams.
+var usergroupIDPattern = regexp.MustCompile(`^S[A-Z0-9]{6,}$`)
+
+// usergroupMentionPattern matches a pasted user group mention, <!subteam^S0123ABC|@handle>.
+var usergroupMentionPattern = regexp.MustCompile(`^<!subteam\^(S[A-Z0-9]+)(?:\|[^>]*)?>$`)
+
+// Usergroup is a user group (e.g., @backend-team), from usergroups.list.
+type Usergroup struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	UserCount   int    `json:"user_count"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// ListUsergroups lists the workspace's user groups with their member counts, including
+// disabled groups if includeDisabled.
+func ListUsergroups(ctx context.Context, client APIClient, includeDisabled bool) ([]Usergroup, error) {
+	params := map[string]string{"include_count": "true"}
+	if includeDisabled {
+		params["include_disabled"] = "true"
+	}
+	resp, err := client.API(ctx, "usergroups.list", params)
+	if err != nil {
+		return nil, fmt.Errorf("usergroups.list: %w", err)
+	}
+	list, _ := resp["usergroups"].([]any)
+	groups := make([]Usergroup, 0, len(list))
+	for _, item := range list {
+		g, _ := item.(map[string]any)
+		if g == nil {
+			continue
+		}
+		var ug Usergroup
+		ug.ID, _ = g["id"].(string)
+		ug.Handle, _ = g["handle"].(string)
+		ug.Name, _ = g["name"].(string)
+		ug.Description, _ = g["description"].(string)
+		count, _ := g["user_count"].(float64)
+		ug.UserCount = int(count)
+		deleted, _ := g["date_delete"].(float64)
+		ug.Disabled = deleted != 0
+		groups = append(groups, ug)
+	}
+	return groups, nil
+}
+
+// ResolveUsergroupID resolves a user group reference to its ID: an ID (S...), a pasted
+// mention, or a handle ("@backend-team" or "backend-team") or name, ignoring case.
+// Disabled groups are found too, so that their former members can be audited.
+func ResolveUsergroupID(ctx context.Context, client APIClient, ref string) (string, error) {
+	if m := usergroupMentionPattern.FindStringSubmatch(ref); m != nil {
+		return m[1], nil
+	}
+	if usergroupIDPattern.MatchString(ref) {
+		return ref, nil
+	}
+	groups, err := ListUsergroups(ctx, client, true)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimPrefix(ref, "@")
+	for _, g := range groups {
+		if strings.EqualFold(g.Handle, name) {
+			return g.ID, nil
+		}
+	}
+	for _, g := range groups {
+		if strings.EqualFold(g.Name, name) {
+			return g.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUsergroupNotFound, ref)
+}
+
+// UsergroupMembers lists a user group's members, looked up as in ChannelMembers.
+func UsergroupMembers(ctx context.Context, client *Client, groupID string) ([]Member, error) {
+	ids, err := usergroupMemberIDs(ctx, client, groupID)
+	if err != nil {
+		return nil, err
+	}
+	directory, err := ListUsers(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return describeMembers(ctx, client, ids, directory)
+}
+
+// usergroupMemberIDs calls usergroups.users.list, which returns every member at once.
+func usergroupMemberIDs(ctx context.Context, client APIClient, groupID string) ([]string, error) {
+	resp, err := client.API(ctx, "usergroups.users.list", map[string]string{
+		"usergroup":        groupID,
+		"include_disabled": "true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("usergroups.users.list: %w", err)
+	}
+	list, _ := resp["users"].([]any)
+	ids := make([]string, 0, len(list))
+	for _, u := range list {
+		if id, _ := u.(string); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// usergroupsAPI serves usergroups.list with an active and a disabled group.
+type usergroupsAPI struct{}
+
+func (usergroupsAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	if method != "usergroups.list" || params["include_count"] != "true" {
+		return nil, fmt.Errorf("unexpected %s %v", method, params)
+	}
+	return map[string]any{"ok": true, "usergroups": []any{
+		map[string]any{"id": "S0BACKEND", "handle": "backend-team", "name": "Backend", "user_count": float64(4), "date_delete": float64(0)},
+		map[string]any{"id": "S0ONCALL1", "handle": "oncall-old", "name": "Old on-call", "user_count": float64(0), "date_delete": float64(1700000000)},
+	}}, nil
+}
+
+func TestListUsergroups(t *testing.T) {
+	groups, err := slack.ListUsergroups(t.Context(), usergroupsAPI{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].Handle != "backend-team" || groups[0].UserCount != 4 || groups[0].Disabled || !groups[1].Disabled {
+		t.Errorf("groups = %+v", groups)
+	}
+}
+
+func TestResolveUsergroupID(t *testing.T) {
+	tests := []struct{ ref, want string }{
+		{"@backend-team", "S0BACKEND"},
+		{"Backend-Team", "S0BACKEND"},
+		{"old on-call", "S0ONCALL1"}, // by name, and disabled
+		{"S0123ABCD", "S0123ABCD"},
+		{"<!subteam^S0BACKEND|@backend-team>", "S0BACKEND"},
+	}
+	for _, tt := range tests {
+		got, err := slack.ResolveUsergroupID(t.Context(), usergroupsAPI{}, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveUsergroupID(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+	if _, err := slack.ResolveUsergroupID(t.Context(), usergroupsAPI{}, "@frontend"); !errors.Is(err, slack.ErrUsergroupNotFound) {
+		t.Errorf("ResolveUsergroupID(@frontend) error = %v, want ErrUsergroupNotFound", err)
+	}
+}
@@ -2,54 +2,497 @@ package slack
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// UserProvider resolves Slack user IDs to display names.
+// prefetchConcurrency bounds the number of concurrent users.info calls made by Prefetch.
+const prefetchConcurrency = 8
+
+var mentionPattern = regexp.MustCompile(`<@([UW][A-Z0-9]+)(?:\|[^>]*)?>`)
+
+// UserProvider resolves Slack user IDs to display names, and bot IDs (B...) to the names
+// of their apps (e.g., "GitHub").
 // It implements the rneatherway/slack/pkg/markdown.UserProvider interface.
+// Concurrent lookups of the same uncached ID share a single users.info or bots.info call.
 type UserProvider struct {
-	client *Client
-	mu     sync.Mutex
-	cache  map[string]string
+	client  APIClient
+	mu      sync.Mutex
+	cache   map[string]string
+	zones   map[string]string // user ID -> IANA time zone, from users.info
+	avatars map[string]string // user or bot ID -> image_72 URL; nil unless CaptureAvatars
+	guests  map[string]string // user ID -> GuestKind, for guests
+	teams   map[string]string // team ID -> name, for LabelExternal
+	apps    map[string]string // app ID -> name, from bot profiles and bots.info
+	// directory maps local and org-level user IDs to users.list members, for
+	// translating IDs on Enterprise Grid; nil until needed.
+	directory     map[string]map[string]any
+	directoryOnce sync.Once
+	group         singleflight.Group
+	homeTeam      string
 }
 
 // NewUserProvider creates a UserProvider backed by the Slack users.info API.
-func NewUserProvider(client *Client) *UserProvider {
+// With a nil client it makes no API calls: names come only from Seed, and other IDs resolve to themselves.
+func NewUserProvider(client APIClient) *UserProvider {
 	return &UserProvider{
 		client: client,
 		cache:  make(map[string]string),
+		zones:  make(map[string]string),
+		guests: make(map[string]string),
+		teams:  make(map[string]string),
+		apps:   make(map[string]string),
+	}
+}
+
+// LabelExternal makes the names of users outside homeTeamID carry their organization's
+// name, e.g. "alice (Acme Corp)", so that internal and external participants in Slack
+// Connect channels can be told apart. Call it before resolving any names.
+func (u *UserProvider) LabelExternal(homeTeamID string) {
+	u.homeTeam = homeTeamID
+}
+
+// CaptureAvatars makes the provider keep the avatar image URL (72px) of each user and bot
+// it resolves, for AvatarForID. Call it before resolving any names.
+func (u *UserProvider) CaptureAvatars() {
+	u.avatars = make(map[string]string)
+}
+
+// AvatarForID returns the avatar image URL of a user or bot resolved since CaptureAvatars
+// (e.g., by Prefetch), or "" if none is known. It makes no API calls.
+func (u *UserProvider) AvatarForID(id string) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.avatars[id]
+}
+
+// GuestKindForID returns GuestMultiChannel or GuestSingleChannel for a guest resolved
+// by the provider (from users.info or an embedded user_profile), or else "".
+// It makes no API calls.
+func (u *UserProvider) GuestKindForID(id string) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.guests[id]
+}
+
+// storeGuest records whether a user is a guest.
+func (u *UserProvider) storeGuest(id string, user map[string]any) {
+	kind := GuestKind(user)
+	if kind == "" {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.guests[id] = kind
+}
+
+// storeAvatar records a user or bot's avatar, if avatars are captured.
+func (u *UserProvider) storeAvatar(id string, images map[string]any) {
+	url, _ := images["image_72"].(string)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.avatars != nil && url != "" {
+		u.avatars[id] = url
 	}
 }
 
 // UsernameForID resolves a Slack user ID to a display name.
 func (u *UserProvider) UsernameForID(id string) (string, error) {
+	if name, ok := u.cached(id); ok {
+		return name, nil
+	}
+	return u.lookup(context.Background(), id), nil
+}
+
+// Seed caches author names from the user_profile and bot_profile Slack embeds in messages,
+// which needs no API calls. With LabelExternal, external authors are left to users.info,
+// which reports their team. A user_profile does not say whether its user is deactivated,
+// so that comes from the users.list directory, if it is loaded or cached (see Prefetch).
+func (u *UserProvider) Seed(messages []map[string]any) {
+	for _, msg := range messages {
+		if botID, _ := msg["bot_id"].(string); botID != "" {
+			profile, _ := msg["bot_profile"].(map[string]any)
+			icons, _ := profile["icons"].(map[string]any)
+			u.storeAvatar(botID, icons)
+			if name := u.botName(profile); name != "" {
+				if _, ok := u.cached(botID); !ok {
+					u.store(botID, name)
+				}
+			}
+		}
+		userID, _ := msg["user"].(string)
+		profile, _ := msg["user_profile"].(map[string]any)
+		if userID == "" || profile == nil {
+			continue
+		}
+		u.storeAvatar(userID, profile)
+		u.storeGuest(userID, profile)
+		if team, _ := msg["user_team"].(string); u.homeTeam != "" && team != "" && team != u.homeTeam {
+			continue
+		}
+		if _, ok := u.cached(userID); ok {
+			continue
+		}
+		for _, field := range []string{"display_name", "real_name", "name"} {
+			if name, _ := profile[field].(string); name != "" {
+				if deleted, _ := u.cachedDirectoryUser(userID)["deleted"].(bool); deleted {
+					name += " (deactivated)"
+				}
+				u.store(userID, name)
+				break
+			}
+		}
+	}
+}
+
+// Prefetch resolves every message author (user or bot) and mentioned user concurrently,
+// so that rendering the messages afterwards is served entirely from the cache.
+// Authors with an embedded user_profile or bot_profile are resolved without users.info
+// calls; which of them are deactivated comes from one users.list listing (cached for a
+// day, and skipped when no author needs it).
+func (u *UserProvider) Prefetch(ctx context.Context, messages []map[string]any) {
+	if u.seedsProfiles(messages) {
+		u.loadDirectory(ctx)
+	}
+	u.Seed(messages)
+	sem := make(chan struct{}, prefetchConcurrency)
+	var wg sync.WaitGroup
+	for _, id := range collectUserIDs(messages) {
+		if _, ok := u.cached(id); ok {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			u.lookup(ctx, id)
+		})
+	}
+	wg.Wait()
+}
+
+// seedsProfiles reports whether Seed would name an author of messages from their
+// embedded user_profile.
+func (u *UserProvider) seedsProfiles(messages []map[string]any) bool {
+	for _, msg := range messages {
+		userID, _ := msg["user"].(string)
+		if profile, _ := msg["user_profile"].(map[string]any); userID == "" || profile == nil {
+			continue
+		}
+		if _, ok := u.cached(userID); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UserProvider) cached(id string) (string, bool) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
+	name, ok := u.cache[id]
+	return name, ok
+}
 
-	if name, ok := u.cache[id]; ok {
+func (u *UserProvider) store(id, name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.cache[id] = name
+}
+
+// lookup resolves and caches id, collapsing concurrent lookups of the same ID into one call.
+func (u *UserProvider) lookup(ctx context.Context, id string) string {
+	v, _, _ := u.group.Do(id, func() (any, error) {
+		if name, ok := u.cached(id); ok {
+			return name, nil
+		}
+		name := u.fetch(ctx, id)
+		u.store(id, name)
 		return name, nil
+	})
+	name, _ := v.(string)
+	return name
+}
+
+// fetch calls users.info (bots.info for bot IDs), falling back to the raw ID on error.
+// Deactivated users are named "alice (deactivated)".
+//
+// Users in a cached users.list directory (see cache warm) are resolved from it without
+// calling users.info. On Enterprise Grid a user has a local ID (U...) and an org-level
+// ID (W...), and messages may carry either. An ID users.info does not know is translated through the
+// users.list directory, and what is learned is kept under all of the user's IDs.
+func (u *UserProvider) fetch(ctx context.Context, id string) string {
+	if u.client == nil {
+		return id
+	}
+	if strings.HasPrefix(id, "B") {
+		return u.fetchBot(ctx, id)
+	}
+	user := u.cachedDirectoryUser(id)
+	if user == nil {
+		resp, err := u.client.API(ctx, "users.info", map[string]string{"user": id})
+		user, _ = resp["user"].(map[string]any)
+		if isUserNotFound(err) {
+			user = u.translateUser(ctx, id)
+		} else if err != nil {
+			return id
+		}
+	}
+	if user == nil {
+		return id
+	}
+
+	ids := userIDs(id, user)
+	profile, _ := user["profile"].(map[string]any)
+	tz, _ := user["tz"].(string)
+	for _, alias := range ids {
+		u.storeAvatar(alias, profile)
+		u.storeGuest(alias, user)
+		u.mu.Lock()
+		u.zones[alias] = tz // "" records that the user has no time zone
+		u.mu.Unlock()
+	}
+
+	name := DisplayName(user)
+	if team, _ := user["team_id"].(string); u.homeTeam != "" && team != "" && team != u.homeTeam {
+		name = fmt.Sprintf("%s (%s)", name, u.teamName(ctx, team))
+	}
+	if deleted, _ := user["deleted"].(bool); deleted {
+		name += " (deactivated)"
+	}
+	for _, alias := range ids[1:] {
+		if _, ok := u.cached(alias); !ok {
+			u.store(alias, name)
+		}
+	}
+	return name
+}
+
+// AuthorLocations returns the time zones of the users who wrote messages, keyed by user
+// ID, looking up with users.info (concurrently) those not already fetched. Users whose
+// time zone is unknown are left out.
+func (u *UserProvider) AuthorLocations(ctx context.Context, messages []map[string]any) map[string]*time.Location {
+	var ids []string
+	for _, msg := range messages {
+		if id, _ := msg["user"].(string); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	sem := make(chan struct{}, prefetchConcurrency)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		u.mu.Lock()
+		_, ok := u.zones[id]
+		u.mu.Unlock()
+		if ok {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// Fetch even if the name is cached (e.g., seeded from user_profile, which
+			// has no time zone), keeping the cached name.
+			_, _, _ = u.group.Do("tz:"+id, func() (any, error) {
+				name := u.fetch(ctx, id)
+				if _, ok := u.cached(id); !ok {
+					u.store(id, name)
+				}
+				return nil, nil
+			})
+		})
 	}
+	wg.Wait()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	locations := make(map[string]*time.Location)
+	for _, id := range ids {
+		tz := u.zones[id]
+		if tz == "" {
+			continue
+		}
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			slog.Info("unknown time zone", "user", id, "tz", tz, "error", err)
+			continue
+		}
+		locations[id] = loc
+	}
+	return locations
+}
+
+// fetchBot calls bots.info, returning the bot's app name, or "bot <id>" on error.
+func (u *UserProvider) fetchBot(ctx context.Context, id string) string {
+	resp, err := u.client.API(ctx, "bots.info", map[string]string{"bot": id})
+	if err != nil {
+		slog.Info("could not look up bot", "bot", id, "error", err)
+		return "bot " + id
+	}
+	bot, _ := resp["bot"].(map[string]any)
+	icons, _ := bot["icons"].(map[string]any)
+	u.storeAvatar(id, icons)
+	if name := u.botName(bot); name != "" {
+		return name
+	}
+	return "bot " + id
+}
+
+// botName names a bot_profile or bots.info bot, recording the name of its app for
+// messages that carry only an app_id. Workflow Builder bots are named after their
+// workflow, as "Workflow: Standup reminder".
+func (u *UserProvider) botName(bot map[string]any) string {
+	name, _ := bot["name"].(string)
+	if name == "" {
+		return ""
+	}
+	if workflow, _ := bot["is_workflow_bot"].(bool); workflow {
+		name = "Workflow: " + name
+	}
+	if appID, _ := bot["app_id"].(string); appID != "" {
+		u.mu.Lock()
+		u.apps[appID] = name
+		u.mu.Unlock()
+	}
+	return name
+}
+
+// appName returns the name of an app seen in a bot profile, or "app <id>".
+func (u *UserProvider) appName(id string) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if name, ok := u.apps[id]; ok {
+		return name
+	}
+	return "app " + id
+}
 
-	resp, err := u.client.API(context.Background(), "users.info", map[string]string{"user": id})
+// teamName resolves a team ID to its name with team.info, falling back to the ID.
+func (u *UserProvider) teamName(ctx context.Context, id string) string {
+	v, _, _ := u.group.Do("team:"+id, func() (any, error) {
+		u.mu.Lock()
+		name, ok := u.teams[id]
+		u.mu.Unlock()
+		if ok {
+			return name, nil
+		}
+
+		name = id
+		if resp, err := u.client.API(ctx, "team.info", map[string]string{"team": id}); err == nil {
+			team, _ := resp["team"].(map[string]any)
+			if n, _ := team["name"].(string); n != "" {
+				name = n
+			}
+		}
+		u.mu.Lock()
+		u.teams[id] = name
+		u.mu.Unlock()
+		return name, nil
+	})
+	name, _ := v.(string)
+	return name
+}
+
+// HomeTeamID returns the ID of the workspace the client is authenticated to (auth.test).
+func HomeTeamID(ctx context.Context, client APIClient) (string, error) {
+	resp, err := client.API(ctx, "auth.test", nil)
 	if err != nil {
-		// Fall back to raw ID on error
-		u.cache[id] = id
-		return id, nil
+		return "", fmt.Errorf("auth.test: %w", err)
 	}
+	teamID, _ := resp["team_id"].(string)
+	return teamID, nil
+}
 
+// GetUser fetches a user object via users.info.
+func GetUser(ctx context.Context, client APIClient, id string) (map[string]any, error) {
+	resp, err := client.API(ctx, "users.info", map[string]string{"user": id})
+	if err != nil {
+		return nil, fmt.Errorf("users.info: %w", err)
+	}
 	user, _ := resp["user"].(map[string]any)
 	if user == nil {
-		u.cache[id] = id
-		return id, nil
+		return nil, fmt.Errorf("users.info: no user in response for %s", id)
 	}
+	return user, nil
+}
 
-	name := resolveDisplayName(user)
-	u.cache[id] = name
-	return name, nil
+// ErrUserNotFound is returned when no user has the requested email address.
+var ErrUserNotFound = fmt.Errorf("user %w", ErrNotFound)
+
+// LookupUserByEmail fetches the user with an email address via users.lookupByEmail.
+// Tokens that may not call it (such as cookie-based ones) fall back to scanning
+// users.list for a matching profile email, which is slower in large workspaces.
+func LookupUserByEmail(ctx context.Context, client APIClient, email string) (map[string]any, error) {
+	resp, err := client.API(ctx, "users.lookupByEmail", map[string]string{"email": email})
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		if user, _ := resp["user"].(map[string]any); user != nil {
+			return user, nil
+		}
+		return nil, fmt.Errorf("users.lookupByEmail: no user in response for %s", email)
+	case errors.As(err, &apiErr) && apiErr.Code == "users_not_found":
+		return nil, fmt.Errorf("%w with email %s", ErrUserNotFound, email)
+	case !errors.As(err, &apiErr) || apiErr.Code == "ratelimited":
+		return nil, fmt.Errorf("users.lookupByEmail: %w", err)
+	}
+
+	slog.Info("users.lookupByEmail unavailable, scanning users.list", "error", err)
+	for member, err := range (pager{method: "users.list", field: "members"}).all(ctx, client) {
+		if err != nil {
+			return nil, fmt.Errorf("users.list: %w", err)
+		}
+		profile, _ := member["profile"].(map[string]any)
+		if e, _ := profile["email"].(string); e != "" && strings.EqualFold(e, email) {
+			return member, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with email %s", ErrUserNotFound, email)
 }
 
-// resolveDisplayName picks the best display name from a user object.
-func resolveDisplayName(user map[string]any) string {
+// collectUserIDs returns the distinct author (user or bot) and mentioned user IDs in
+// messages, in first-seen order.
+func collectUserIDs(messages []map[string]any) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	addMentions := func(text string) {
+		for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			add(m[1])
+		}
+	}
+
+	for _, msg := range messages {
+		userID, _ := msg["user"].(string)
+		add(userID)
+		if botID, _ := msg["bot_id"].(string); userID == "" && !hasUsername(msg) {
+			add(botID)
+		}
+		text, _ := msg["text"].(string)
+		addMentions(text)
+		attachments, _ := msg["attachments"].([]any)
+		for _, a := range attachments {
+			att, _ := a.(map[string]any)
+			attText, _ := att["text"].(string)
+			addMentions(attText)
+		}
+	}
+	return ids
+}
+
+// DisplayName picks the best display name from a user object.
+func DisplayName(user map[string]any) string {
 	profile, _ := user["profile"].(map[string]any)
 	if profile != nil {
 		if dn, _ := profile["display_name"].(string); dn != "" {
@@ -71,22 +514,47 @@ func resolveDisplayName(user map[string]any) string {
 	return "unknown"
 }
 
-// UsernameForMessage returns the display name for a message's author.
+// UsernameForMessage returns the display name for a message's author. A user_profile
+// embedded in the message is used (and cached) before calling users.info, as in Prefetch.
+// Bot messages are attributed to the name they were posted under (bot_profile or
+// username), or else to their app's name from bots.info; workflow messages to their
+// workflow ("Workflow: Standup reminder"). Messages with only an app_id are attributed
+// to an app seen in another message's bot profile.
 func (u *UserProvider) UsernameForMessage(msg map[string]any) (string, error) {
 	if userID, _ := msg["user"].(string); userID != "" {
+		if _, ok := u.cached(userID); !ok {
+			msgs := []map[string]any{msg}
+			if u.seedsProfiles(msgs) {
+				u.loadDirectory(context.Background())
+			}
+			u.Seed(msgs)
+		}
 		return u.UsernameForID(userID)
 	}
 	if botID, _ := msg["bot_id"].(string); botID != "" {
-		// Try bot profile name first
 		if profile, _ := msg["bot_profile"].(map[string]any); profile != nil {
-			if name, _ := profile["name"].(string); name != "" {
+			if name := u.botName(profile); name != "" {
 				return name, nil
 			}
 		}
-		return "bot " + botID, nil
+		if username, _ := msg["username"].(string); username != "" {
+			return username, nil
+		}
+		return u.UsernameForID(botID)
 	}
 	if username, _ := msg["username"].(string); username != "" {
 		return username, nil
 	}
+	if appID, _ := msg["app_id"].(string); appID != "" {
+		return u.appName(appID), nil
+	}
 	return "unknown", nil
 }
+
+// hasUsername reports whether a bot message carries the name it was posted under.
+func hasUsername(msg map[string]any) bool {
+	profile, _ := msg["bot_profile"].(map[string]any)
+	name, _ := profile["name"].(string)
+	username, _ := msg["username"].(string)
+	return name != "" || username != ""
+}
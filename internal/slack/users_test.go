@@ -0,0 +1,405 @@
+package slack_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// usersAPI serves users.info from a fixed directory and records requested IDs.
+type usersAPI struct {
+	mu        sync.Mutex
+	directory map[string]string
+	requested []string
+	delay     time.Duration
+}
+
+func (m *usersAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	if method != "users.info" {
+		return nil, &slack.APIError{Method: method, Code: "unknown_method"}
+	}
+	time.Sleep(m.delay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := params["user"]
+	m.requested = append(m.requested, id)
+	return map[string]any{
+		"ok": true,
+		"user": map[string]any{
+			"id":      id,
+			"profile": map[string]any{"display_name": m.directory[id]},
+		},
+	}, nil
+}
+
+func TestUserProvider_Prefetch(t *testing.T) {
+	api := &usersAPI{directory: map[string]string{"U1": "alice", "U2": "bob", "U3": "carol"}}
+	users := slack.NewUserProvider(api)
+
+	messages := []map[string]any{
+		{"user": "U1", "text": "hey <@U2>, see <@U3|carol>"},
+		{"user": "U2", "text": "thanks <@U1>"},
+		{"bot_id": "B1", "text": "deploy done"},
+	}
+	users.Prefetch(t.Context(), messages)
+
+	got := append([]string(nil), api.requested...)
+	sort.Strings(got)
+	want := []string{"U1", "U2", "U3"}
+	if len(got) != len(want) {
+		t.Fatalf("requested %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("requested %v, want %v", got, want)
+		}
+	}
+
+	// Rendering afterwards must be served from the cache.
+	name, err := users.UsernameForID("U3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "carol" {
+		t.Errorf("UsernameForID(U3) = %q, want carol", name)
+	}
+	if n := len(api.requested); n != 3 {
+		t.Errorf("made %d users.info calls, want 3", n)
+	}
+}
+
+// Concurrent lookups of the same user share one users.info call.
+func TestUserProvider_ConcurrentLookupsShareCall(t *testing.T) {
+	api := &usersAPI{directory: map[string]string{"U1": "alice"}, delay: 20 * time.Millisecond}
+	users := slack.NewUserProvider(api)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			name, err := users.UsernameForID("U1")
+			if err != nil || name != "alice" {
+				t.Errorf("UsernameForID(U1) = %q, %v; want alice", name, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := len(api.requested); n != 1 {
+		t.Errorf("made %d users.info calls, want 1", n)
+	}
+}
+
+// Without a client, names come only from embedded profiles and other IDs pass through.
+func TestUserProvider_NoClient(t *testing.T) {
+	users := slack.NewUserProvider(nil)
+	users.Seed([]map[string]any{
+		{"user": "U1", "user_profile": map[string]any{"display_name": "", "real_name": "Alice Smith"}},
+		{"user": "U2", "text": "no profile"},
+	})
+
+	for id, want := range map[string]string{"U1": "Alice Smith", "U2": "U2"} {
+		name, err := users.UsernameForID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != want {
+			t.Errorf("UsernameForID(%s) = %q, want %q", id, name, want)
+		}
+	}
+}
+
+// An author's embedded user_profile names them without a users.info call, even unseeded.
+func TestUserProvider_UsernameForMessageProfile(t *testing.T) {
+	api := &usersAPI{directory: map[string]string{"U1": "alice (users.info)", "U2": "bob"}}
+	users := slack.NewUserProvider(api)
+
+	for _, tt := range []struct {
+		msg  map[string]any
+		want string
+	}{
+		{map[string]any{"user": "U1", "user_profile": map[string]any{"display_name": "alice"}}, "alice"},
+		{map[string]any{"user": "U1"}, "alice"},
+		{map[string]any{"user": "U2"}, "bob"},
+	} {
+		if got, _ := users.UsernameForMessage(tt.msg); got != tt.want {
+			t.Errorf("UsernameForMessage(%v) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+	if !slices.Equal(api.requested, []string{"U2"}) {
+		t.Errorf("users.info requested for %v, want only U2", api.requested)
+	}
+}
+
+// connectAPI serves users.info and team.info for a Slack Connect channel shared with T2.
+type connectAPI struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (m *connectAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[method]++
+	if method == "team.info" {
+		return map[string]any{"ok": true, "team": map[string]any{"id": params["team"], "name": "Acme Corp"}}, nil
+	}
+	users := map[string]map[string]any{
+		"U1": {"id": "U1", "team_id": "T1", "profile": map[string]any{"display_name": "alice"}},
+		"W2": {"id": "W2", "team_id": "T2", "profile": map[string]any{"display_name": "bob"}},
+		"W3": {"id": "W3", "team_id": "T2", "profile": map[string]any{"display_name": "carol"}},
+	}
+	return map[string]any{"ok": true, "user": users[params["user"]]}, nil
+}
+
+func TestUserProvider_LabelExternal(t *testing.T) {
+	api := &connectAPI{calls: make(map[string]int)}
+	users := slack.NewUserProvider(api)
+	users.LabelExternal("T1")
+
+	// An embedded profile of an external author must not hide their team.
+	users.Seed([]map[string]any{
+		{"user": "W2", "user_team": "T2", "user_profile": map[string]any{"display_name": "bob"}},
+	})
+
+	for id, want := range map[string]string{"U1": "alice", "W2": "bob (Acme Corp)", "W3": "carol (Acme Corp)"} {
+		name, err := users.UsernameForID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != want {
+			t.Errorf("UsernameForID(%s) = %q, want %q", id, name, want)
+		}
+	}
+	if n := api.calls["team.info"]; n != 1 {
+		t.Errorf("made %d team.info calls, want 1", n)
+	}
+}
+
+// emailAPI serves users.list, and users.lookupByEmail unless the token may not call it.
+type emailAPI struct {
+	lookupErr error
+	calls     []string
+}
+
+func (m *emailAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	m.calls = append(m.calls, method)
+	members := []any{
+		map[string]any{"id": "U1", "name": "alice", "profile": map[string]any{"email": "alice@example.com"}},
+		map[string]any{"id": "U2", "name": "bob", "profile": map[string]any{"email": "Bob@Example.com"}},
+	}
+	switch method {
+	case "users.lookupByEmail":
+		if m.lookupErr != nil {
+			return nil, m.lookupErr
+		}
+		for _, u := range members {
+			if u.(map[string]any)["profile"].(map[string]any)["email"] == params["email"] {
+				return map[string]any{"ok": true, "user": u}, nil
+			}
+		}
+		return nil, &slack.APIError{Method: method, Code: "users_not_found"}
+	case "users.list":
+		return map[string]any{"ok": true, "members": members}, nil
+	}
+	return nil, &slack.APIError{Method: method, Code: "unknown_method"}
+}
+
+func TestLookupUserByEmail(t *testing.T) {
+	api := &emailAPI{}
+	user, err := slack.LookupUserByEmail(t.Context(), api, "alice@example.com")
+	if err != nil || user["id"] != "U1" {
+		t.Fatalf("LookupUserByEmail = %v, %v; want U1", user, err)
+	}
+	if _, err := slack.LookupUserByEmail(t.Context(), api, "carol@example.com"); !errors.Is(err, slack.ErrUserNotFound) {
+		t.Errorf("unknown email: err = %v, want ErrUserNotFound", err)
+	}
+	if slices.Contains(api.calls, "users.list") {
+		t.Errorf("calls = %v, want no users.list scan when users.lookupByEmail works", api.calls)
+	}
+
+	// Without users.lookupByEmail, users.list is scanned, ignoring case.
+	api = &emailAPI{lookupErr: &slack.APIError{Method: "users.lookupByEmail", Code: "not_allowed_token_type"}}
+	user, err = slack.LookupUserByEmail(t.Context(), api, "bob@example.com")
+	if err != nil || user["id"] != "U2" {
+		t.Fatalf("fallback: LookupUserByEmail = %v, %v; want U2", user, err)
+	}
+	if _, err := slack.LookupUserByEmail(t.Context(), api, "carol@example.com"); !errors.Is(err, slack.ErrUserNotFound) {
+		t.Errorf("fallback, unknown email: err = %v, want ErrUserNotFound", err)
+	}
+}
+
+// botsAPI serves bots.info from a fixed directory and records requested bot IDs.
+type botsAPI struct {
+	mu        sync.Mutex
+	requested []string
+}
+
+func (m *botsAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if method != "bots.info" {
+		return nil, &slack.APIError{Method: method, Code: "unknown_method"}
+	}
+	m.requested = append(m.requested, params["bot"])
+	if params["bot"] != "B1" {
+		return nil, &slack.APIError{Method: method, Code: "bot_not_found"}
+	}
+	return map[string]any{"ok": true, "bot": map[string]any{"id": "B1", "name": "GitHub", "app_id": "A1"}}, nil
+}
+
+func TestUserProvider_Bots(t *testing.T) {
+	api := &botsAPI{}
+	users := slack.NewUserProvider(api)
+	messages := []map[string]any{
+		{"bot_id": "B1", "subtype": "bot_message", "text": "PR merged"},
+		{"bot_id": "B1", "subtype": "bot_message", "text": "PR opened"},
+		{"bot_id": "B2", "bot_profile": map[string]any{"name": "PagerDuty"}, "text": "Incident"},
+		{"bot_id": "B3", "username": "deploy-hook", "text": "Deployed"},
+		{"bot_id": "B4", "text": "?"},
+	}
+	users.Prefetch(t.Context(), messages)
+
+	want := []string{"GitHub", "GitHub", "PagerDuty", "deploy-hook", "bot B4"}
+	for i, msg := range messages {
+		if got, _ := users.UsernameForMessage(msg); got != want[i] {
+			t.Errorf("UsernameForMessage(%v) = %q, want %q", msg["text"], got, want[i])
+		}
+	}
+	sort.Strings(api.requested)
+	if !slices.Equal(api.requested, []string{"B1", "B4"}) {
+		t.Errorf("bots.info requested for %v, want [B1 B4]", api.requested)
+	}
+
+	// Bot IDs given on their own (e.g., report authors) resolve too.
+	if got, _ := users.UsernameForID("B2"); got != "PagerDuty" {
+		t.Errorf("UsernameForID(B2) = %q, want PagerDuty", got)
+	}
+}
+
+func TestUserProvider_WorkflowsAndApps(t *testing.T) {
+	api := &botsAPI{}
+	users := slack.NewUserProvider(api)
+	messages := []map[string]any{
+		{"bot_id": "B5", "bot_profile": map[string]any{"name": "Standup reminder", "app_id": "A5", "is_workflow_bot": true}, "text": "Time for standup"},
+		{"app_id": "A5", "text": "Standup summary"},
+		{"bot_id": "B1", "text": "PR merged"},
+		{"app_id": "A1", "text": "Deploy approved"},
+		{"app_id": "A9", "text": "?"},
+	}
+	users.Prefetch(t.Context(), messages)
+
+	want := []string{"Workflow: Standup reminder", "Workflow: Standup reminder", "GitHub", "GitHub", "app A9"}
+	for i, msg := range messages {
+		if got, _ := users.UsernameForMessage(msg); got != want[i] {
+			t.Errorf("UsernameForMessage(%v) = %q, want %q", msg["text"], got, want[i])
+		}
+	}
+}
+
+// zonesAPI serves users.info with time zones (U3, deactivated, has none) and counts calls.
+type zonesAPI struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (m *zonesAPI) API(_ context.Context, _ string, params map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	zones := map[string]string{"U1": "UTC", "U2": "Mars/Olympus_Mons"}
+	return map[string]any{"ok": true, "user": map[string]any{
+		"id": params["user"], "name": params["user"], "tz": zones[params["user"]],
+		"deleted": params["user"] == "U3",
+	}}, nil
+}
+
+func TestUserProvider_AuthorLocations(t *testing.T) {
+	api := &zonesAPI{}
+	users := slack.NewUserProvider(api)
+	messages := []map[string]any{
+		{"user": "U1", "text": "hi", "user_profile": map[string]any{"display_name": "alice"}},
+		{"user": "U2", "text": "hello"},
+		{"user": "U3", "text": "hey"},
+		{"user": "U1", "text": "again"},
+	}
+
+	users.Seed(messages)
+	locations := users.AuthorLocations(t.Context(), messages)
+	if len(locations) != 1 || locations["U1"] != time.UTC {
+		t.Errorf("AuthorLocations = %v, want only U1 in UTC", locations)
+	}
+	if api.calls != 3 {
+		t.Errorf("users.info called %d times, want 3", api.calls)
+	}
+	// The name seeded from user_profile is kept.
+	if name, _ := users.UsernameForID("U1"); name != "alice" {
+		t.Errorf("UsernameForID(U1) = %q, want alice", name)
+	}
+
+	users.AuthorLocations(t.Context(), messages)
+	if api.calls != 3 {
+		t.Errorf("users.info called %d times after a second AuthorLocations, want 3", api.calls)
+	}
+}
+
+func TestUserProvider_Deactivated(t *testing.T) {
+	users := slack.NewUserProvider(&zonesAPI{})
+	for id, want := range map[string]string{"U1": "U1", "U3": "U3 (deactivated)"} {
+		if got, _ := users.UsernameForID(id); got != want {
+			t.Errorf("UsernameForID(%s) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+// deactivatedAPI serves users.list, in which U3 is deactivated, and counts users.info calls.
+type deactivatedAPI struct {
+	mu    sync.Mutex
+	info  int
+	lists int
+}
+
+func (m *deactivatedAPI) API(_ context.Context, method string, _ map[string]string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if method == "users.list" {
+		m.lists++
+		return map[string]any{"ok": true, "members": []any{
+			map[string]any{"id": "U1", "name": "alice"},
+			map[string]any{"id": "U3", "name": "carol", "deleted": true},
+		}}, nil
+	}
+	m.info++
+	return nil, &slack.APIError{Method: method, Code: "user_not_found"}
+}
+
+// A deactivated author is marked even when their message embeds a user_profile, with one
+// users.list call for all authors instead of users.info for each.
+func TestUserProvider_DeactivatedWithProfile(t *testing.T) {
+	api := &deactivatedAPI{}
+	users := slack.NewUserProvider(api)
+	messages := []map[string]any{
+		{"user": "U1", "text": "hi", "user_profile": map[string]any{"display_name": "alice"}},
+		{"user": "U3", "text": "bye", "user_profile": map[string]any{"display_name": "carol"}},
+	}
+	users.Prefetch(t.Context(), messages)
+	for i, want := range []string{"alice", "carol (deactivated)"} {
+		if got, _ := users.UsernameForMessage(messages[i]); got != want {
+			t.Errorf("UsernameForMessage(%s) = %q, want %q", messages[i]["user"], got, want)
+		}
+	}
+
+	// UsernameForMessage alone does the same.
+	users = slack.NewUserProvider(api)
+	if got, _ := users.UsernameForMessage(messages[1]); got != "carol (deactivated)" {
+		t.Errorf("UsernameForMessage = %q, want \"carol (deactivated)\"", got)
+	}
+	if api.info != 0 || api.lists != 2 {
+		t.Errorf("made %d users.info and %d users.list calls, want 0 and 2 (one per provider)", api.info, api.lists)
+	}
+}
@@ -0,0 +1,152 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// usersDirectoryKey is the key of the users.list directory in the client's user cache.
+const usersDirectoryKey = "users.list"
+
+// UserMatch is a user found by SearchUsers, with the profile field that matched best.
+type UserMatch struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	RealName    string `json:"real_name,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+	IsBot       bool   `json:"is_bot,omitempty"`
+	MatchedOn   string `json:"matched_on"`
+}
+
+// ListUsers returns every member of the workspace from users.list. The directory is
+// cached for a day, since scanning it takes many calls in large workspaces.
+func ListUsers(ctx context.Context, client *Client) ([]map[string]any, error) {
+	if members, ok := CachedUsers(client); ok {
+		return members, nil
+	}
+	return RefreshUsers(ctx, client)
+}
+
+// CachedUsers returns the users.list directory if it is cached, without API calls.
+func CachedUsers(client *Client) ([]map[string]any, bool) {
+	data, ok := client.users.Get(usersDirectoryKey)
+	if !ok {
+		return nil, false
+	}
+	var members []map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, false
+	}
+	slog.Debug("user directory cache hit", "users", len(members))
+	return members, true
+}
+
+// RefreshUsers is ListUsers without the cache: it pages through all of users.list,
+// then caches the result for ListUsers.
+func RefreshUsers(ctx context.Context, client *Client) ([]map[string]any, error) {
+	var members []map[string]any
+	for member, err := range (pager{method: "users.list", field: "members"}).all(ctx, client) {
+		if err != nil {
+			return nil, fmt.Errorf("users.list: %w", err)
+		}
+		members = append(members, member)
+	}
+	if data, err := json.Marshal(members); err == nil {
+		if err := client.users.Set(usersDirectoryKey, data); err != nil {
+			slog.Info("could not cache users", "error", err)
+		}
+	}
+	return members, nil
+}
+
+// SearchUsers ranks the users matching a name fragment on their username, display name,
+// real name, or title (ignoring case), best first: exact matches, then prefixes (of the
+// field or of a word in it), then substrings, then near misses. Titles only match
+// exactly, by prefix, or as a substring. Within a rank, active people come before
+// deactivated users and bots, then usernames sort alphabetically.
+func SearchUsers(users []map[string]any, query string) []UserMatch {
+	type scored struct {
+		match UserMatch
+		tier  int
+	}
+
+	query = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if query == "" {
+		return nil
+	}
+	var matches []scored
+	for _, u := range users {
+		profile, _ := u["profile"].(map[string]any)
+		m := UserMatch{}
+		m.ID, _ = u["id"].(string)
+		m.Name, _ = u["name"].(string)
+		m.DisplayName, _ = profile["display_name"].(string)
+		m.RealName, _ = profile["real_name"].(string)
+		if m.RealName == "" {
+			m.RealName, _ = u["real_name"].(string)
+		}
+		m.Title, _ = profile["title"].(string)
+		m.Deactivated, _ = u["deleted"].(bool)
+		m.IsBot, _ = u["is_bot"].(bool)
+
+		best := tierNone
+		fields := []struct{ name, value string }{
+			{"name", m.Name}, {"display_name", m.DisplayName}, {"real_name", m.RealName}, {"title", m.Title},
+		}
+		for _, f := range fields {
+			if tier := matchTier(strings.ToLower(f.value), query, f.name != "title"); tier < best {
+				best, m.MatchedOn = tier, f.name
+			}
+		}
+		if best != tierNone {
+			matches = append(matches, scored{m, best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if ai, bi := a.match.Deactivated || a.match.IsBot, b.match.Deactivated || b.match.IsBot; ai != bi {
+			return bi
+		}
+		return a.match.Name < b.match.Name
+	})
+	result := make([]UserMatch, len(matches))
+	for i, m := range matches {
+		result[i] = m.match
+	}
+	return result
+}
+
+// matchTier ranks how well a lowercase field value matches query, using fuzzyMatch's tiers.
+// A word of the value starting with query counts as a prefix match.
+func matchTier(value, query string, typos bool) int {
+	switch {
+	case value == "":
+		return tierNone
+	case value == query:
+		return tierExact
+	case strings.HasPrefix(value, query):
+		return tierPrefix
+	}
+	for _, w := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '-' || r == '_' || r == '.' }) {
+		if strings.HasPrefix(w, query) {
+			return tierPrefix
+		}
+	}
+	if strings.Contains(value, query) {
+		return tierSubstring
+	}
+	if typos && editDistance(value, query) <= min(max(len(query)/3, 1), 3) {
+		return tierTypo
+	}
+	return tierNone
+}
@@ -0,0 +1,51 @@
+package slack_test
+
+import (
+	"testing"
+
+	"github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestSearchUsers(t *testing.T) {
+	users := []map[string]any{
+		{"id": "U1", "name": "asmith", "profile": map[string]any{"display_name": "Alice", "real_name": "Alice Smith", "title": "Engineer"}},
+		{"id": "U2", "name": "ali", "profile": map[string]any{"real_name": "Ali Khan"}},
+		{"id": "U3", "name": "bob", "profile": map[string]any{"real_name": "Bob Dalis", "title": "Sales lead"}},
+		{"id": "U4", "name": "alibot", "is_bot": true, "profile": map[string]any{"real_name": "Ali Bot"}},
+		{"id": "U5", "name": "alex", "deleted": true, "profile": map[string]any{"real_name": "Alex Old"}},
+		{"id": "U6", "name": "carol", "profile": map[string]any{"real_name": "Carol", "title": "Support lead"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string // IDs, best first
+		on    string   // the first match's matched_on
+	}{
+		{"ali", []string{"U2", "U1", "U4", "U3"}, "name"},
+		{"@Alice", []string{"U1"}, "display_name"},
+		{"smith", []string{"U1"}, "real_name"},
+		{"lead", []string{"U3", "U6"}, "title"},
+		{"alx", []string{"U2", "U5"}, "name"},
+		{"zed", nil, ""},
+	}
+	for _, tt := range tests {
+		got := slack.SearchUsers(users, tt.query)
+		var ids []string
+		for _, m := range got {
+			ids = append(ids, m.ID)
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("SearchUsers(%q) = %v, want %v", tt.query, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("SearchUsers(%q) = %v, want %v", tt.query, ids, tt.want)
+				break
+			}
+		}
+		if len(got) > 0 && got[0].MatchedOn != tt.on {
+			t.Errorf("SearchUsers(%q)[0].MatchedOn = %q, want %q", tt.query, got[0].MatchedOn, tt.on)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PostJSON POSTs a JSON body to an HTTP endpoint outside Slack, such as a webhook. No
+// credentials are sent. Like API calls, it is retried with backoff when rate limited (429),
+// on server errors (5xx), and on transient network failures; any other non-2xx status
+// is an error.
+func (c *Client) PostJSON(ctx context.Context, link string, body []byte) error {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("post: not an HTTP URL: %q", link)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, link, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post %s: %w", u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	c.stats.recordCall("webhook.post", len(body), time.Since(start))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post %s: %s", u.Redacted(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package slack_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+func TestPostJSON(t *testing.T) {
+	var calls int
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		got = r.Header.Get("Content-Type") + " " + string(body)
+		if r.URL.Path == "/reject" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := islack.NewClientNoCreds("test", islack.WithMaxRetries(1))
+	if err := client.PostJSON(t.Context(), srv.URL+"/hook", []byte(`{"ok":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || got != `application/json {"ok":true}` {
+		t.Errorf("got %d calls, last with %q; want the body re-sent after the 503", calls, got)
+	}
+
+	if err := client.PostJSON(t.Context(), srv.URL+"/reject", nil); err == nil {
+		t.Error("want an error for a 400")
+	}
+	if err := client.PostJSON(t.Context(), "ftp://example.com/hook", nil); err == nil {
+		t.Error("want an error for a non-HTTP URL")
+	}
+}
@@ -0,0 +1,61 @@
+// Package tail follows a conversation, handing on each message as it is posted.
+package tail
+
+import (
+	"context"
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Envelope is what tail emits for each new message: the message, with its text rendered
+// as markdown in text_rendered, and where it was posted.
+type Envelope struct {
+	ChannelID string         `json:"channel_id"`
+	Channel   string         `json:"channel,omitempty"` // the name it was given by, if any
+	Permalink string         `json:"permalink"`
+	Message   map[string]any `json:"message"`
+}
+
+// Options controls Follow.
+type Options struct {
+	Oldest   string        // only messages after this timestamp, e.g., when following started
+	Interval time.Duration // time between polls
+}
+
+// Follow polls a channel's history every Interval and calls handle with each message
+// posted after Oldest, oldest first. Thread replies are not followed, except those also
+// sent to the channel. It returns nil once ctx is done, or the first error of a poll or
+// of handle.
+func Follow(ctx context.Context, client islack.APIClient, channelID string, opts Options, handle func(msg map[string]any) error) error {
+	oldest := opts.Oldest
+	for {
+		window := islack.HistoryOptions{Oldest: oldest}
+		messages, err := islack.CollectMessages(islack.IterChannelHistory(ctx, client, channelID, window))
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			if err := handle(msg); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if ts, _ := msg["ts"].(string); ts > oldest {
+				oldest = ts
+			}
+		}
+
+		timer := time.NewTimer(opts.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
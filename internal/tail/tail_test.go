@@ -0,0 +1,69 @@
+package tail_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/internal/tail"
+)
+
+// pollAPI serves each poll its own page of history, and records the oldest asked for.
+type pollAPI struct {
+	polls   [][]any
+	oldests []string
+}
+
+func (a *pollAPI) API(_ context.Context, method string, params map[string]string) (map[string]any, error) {
+	if method != "conversations.history" {
+		return nil, errors.New("unexpected call " + method)
+	}
+	a.oldests = append(a.oldests, params["oldest"])
+	var messages []any
+	if len(a.polls) > 0 {
+		messages, a.polls = a.polls[0], a.polls[1:]
+	}
+	return map[string]any{"ok": true, "messages": messages}, nil
+}
+
+func TestFollow(t *testing.T) {
+	api := &pollAPI{polls: [][]any{
+		{map[string]any{"ts": "1770000002.000000", "text": "b"}, map[string]any{"ts": "1770000001.000000", "text": "a"}},
+		{},
+		{map[string]any{"ts": "1770000003.000000", "text": "c"}},
+	}}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var texts []string
+	err := tail.Follow(ctx, api, "C1", tail.Options{Oldest: "1770000000.000000", Interval: time.Millisecond}, func(msg map[string]any) error {
+		texts = append(texts, msg["text"].(string))
+		if len(texts) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(texts, []string{"a", "b", "c"}) {
+		t.Errorf("got messages %v, want each once, oldest first", texts)
+	}
+	want := []string{"1770000000.000000", "1770000002.000000", "1770000002.000000"}
+	if !slices.Equal(api.oldests, want) {
+		t.Errorf("polled after %v, want %v", api.oldests, want)
+	}
+}
+
+func TestFollow_HandlerError(t *testing.T) {
+	api := &pollAPI{polls: [][]any{{map[string]any{"ts": "1770000001.000000"}}}}
+	errStop := errors.New("stop")
+	err := tail.Follow(t.Context(), api, "C1", tail.Options{Interval: time.Millisecond}, func(map[string]any) error {
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("got %v, want the handler's error", err)
+	}
+}
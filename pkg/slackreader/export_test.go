@@ -0,0 +1,4 @@
+package slackreader
+
+// NewMessage exposes newMessage to tests.
+var NewMessage = newMessage
@@ -0,0 +1,39 @@
+package slackreader
+
+import (
+	"time"
+
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Message is a Slack message with its commonly used fields decoded.
+type Message struct {
+	TS         string    // Slack timestamp, unique within the channel
+	Time       time.Time // TS as a time
+	ThreadTS   string    // parent timestamp for thread roots and replies
+	User       string    // author's user ID (empty for bots)
+	BotID      string
+	Subtype    string
+	Text       string
+	ReplyCount int
+	Raw        map[string]any // the message object as Slack returned it
+}
+
+// IsReply reports whether the message is a reply inside a thread (not the root).
+func (m Message) IsReply() bool {
+	return m.ThreadTS != "" && m.ThreadTS != m.TS
+}
+
+func newMessage(raw map[string]any) Message {
+	m := Message{Raw: raw}
+	m.TS, _ = raw["ts"].(string)
+	m.Time, _ = islack.ParseTimestamp(m.TS)
+	m.ThreadTS, _ = raw["thread_ts"].(string)
+	m.User, _ = raw["user"].(string)
+	m.BotID, _ = raw["bot_id"].(string)
+	m.Subtype, _ = raw["subtype"].(string)
+	m.Text, _ = raw["text"].(string)
+	replyCount, _ := raw["reply_count"].(float64)
+	m.ReplyCount = int(replyCount)
+	return m
+}
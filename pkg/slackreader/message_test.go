@@ -0,0 +1,27 @@
+package slackreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sethrylan/slack-reader/pkg/slackreader"
+)
+
+func TestNewMessage(t *testing.T) {
+	m := slackreader.NewMessage(map[string]any{
+		"ts":        "1770165200.000100",
+		"thread_ts": "1770165109.628379",
+		"user":      "U0123ABCD",
+		"text":      "hello",
+	})
+
+	if m.User != "U0123ABCD" || m.Text != "hello" {
+		t.Errorf("got %+v, want user and text decoded", m)
+	}
+	if want := time.Unix(1770165200, 100_000); !m.Time.Equal(want) {
+		t.Errorf("got time %v, want %v", m.Time, want)
+	}
+	if !m.IsReply() {
+		t.Error("IsReply() = false, want true")
+	}
+}
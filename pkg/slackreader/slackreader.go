@@ -0,0 +1,175 @@
+// Package slackreader reads Slack channels, threads, and messages using the cookie-based
+// credentials of a local Slack Desktop install. It is the library behind the slack-reader CLI.
+//
+// All functions return errors rather than exiting, and all listings are iterators that
+// fetch pages lazily, so callers can stop early without paying for the rest.
+package slackreader
+
+import (
+	"context"
+	"iter"
+
+	"github.com/sethrylan/slack-reader/internal/output"
+	islack "github.com/sethrylan/slack-reader/internal/slack"
+)
+
+// Option configures a Client.
+type Option = islack.Option
+
+// Client options.
+var (
+	WithResponseCache     = islack.WithResponseCache
+	WithRequestTimeout    = islack.WithRequestTimeout
+	WithMaxRetries        = islack.WithMaxRetries
+	WithMaxIdleConns      = islack.WithMaxIdleConns
+	WithIdleConnTimeout   = islack.WithIdleConnTimeout
+	WithTLSConfig         = islack.WithTLSConfig
+	WithFuzzyChannelNames = islack.WithFuzzyChannelNames
+)
+
+// HistoryOptions controls which messages History and Thread return: a window of Slack
+// timestamps (Oldest, Latest, Inclusive), a Limit or Unlimited, and the PageSize requested.
+// Set Resume to receive a cursor that, passed as Cursor later, continues the listing.
+type HistoryOptions = islack.HistoryOptions
+
+// APIError is returned when Slack responds with ok=false; Code is Slack's error code.
+type APIError = islack.APIError
+
+// Errors reported by Client methods.
+var (
+	ErrRateLimited     = islack.ErrRateLimited
+	ErrMessageNotFound = islack.ErrMessageNotFound
+	ErrNotFound        = islack.ErrNotFound // matches ErrMessageNotFound and unknown channels
+	ErrNetwork         = islack.ErrNetwork
+)
+
+// Client reads from one Slack workspace. It is safe for concurrent use.
+type Client struct {
+	api   *islack.Client
+	users *islack.UserProvider
+}
+
+// New returns a Client for workspace (the team domain, e.g., "myteam" for myteam.slack.com),
+// authenticated with Slack Desktop's cookies or the SLACK_TOKEN and SLACK_COOKIES environment variables.
+func New(workspace string, opts ...Option) (*Client, error) {
+	api, err := islack.NewClient(workspace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: api, users: islack.NewUserProvider(api)}, nil
+}
+
+// API calls a Slack Web API method directly, for anything this package doesn't wrap.
+func (c *Client) API(ctx context.Context, method string, params map[string]string) (map[string]any, error) {
+	return c.api.API(ctx, method, params)
+}
+
+// ResolveChannel resolves a channel name ("#general" or "general") or ID to an ID.
+func (c *Client) ResolveChannel(ctx context.Context, channel string) (string, error) {
+	return islack.ResolveChannelID(ctx, c.api, channel)
+}
+
+// Message fetches a single message, including thread replies.
+func (c *Client) Message(ctx context.Context, channel, ts string) (*Message, error) {
+	channelID, err := c.ResolveChannel(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	result, err := islack.GetMessage(ctx, c.api, channelID, ts, "")
+	if err != nil {
+		return nil, err
+	}
+	msg := newMessage(result.Message)
+	return &msg, nil
+}
+
+// History streams a channel's messages, newest first.
+func (c *Client) History(ctx context.Context, channel string, opts HistoryOptions) iter.Seq2[Message, error] {
+	return c.messages(ctx, channel, func(channelID string) iter.Seq2[map[string]any, error] {
+		return islack.IterChannelHistory(ctx, c.api, channelID, opts)
+	})
+}
+
+// Thread streams a thread's messages, root first.
+func (c *Client) Thread(ctx context.Context, channel, threadTS string, opts HistoryOptions) iter.Seq2[Message, error] {
+	return c.messages(ctx, channel, func(channelID string) iter.Seq2[map[string]any, error] {
+		return islack.IterThread(ctx, c.api, channelID, threadTS, opts)
+	})
+}
+
+func (c *Client) messages(ctx context.Context, channel string, list func(channelID string) iter.Seq2[map[string]any, error]) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		channelID, err := c.ResolveChannel(ctx, channel)
+		if err != nil {
+			yield(Message{}, err)
+			return
+		}
+		for raw, err := range list(channelID) {
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+			if !yield(newMessage(raw), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Conversations streams the conversations the current user belongs to,
+// or every conversation in the workspace if all is set.
+func (c *Client) Conversations(ctx context.Context, all bool) iter.Seq2[Channel, error] {
+	seq := islack.IterUserConversations(ctx, c.api, "", islack.ConversationOptions{})
+	if all {
+		seq = islack.IterAllConversations(ctx, c.api, islack.ConversationOptions{})
+	}
+	return func(yield func(Channel, error) bool) {
+		for raw, err := range seq {
+			if err != nil {
+				yield(Channel{}, err)
+				return
+			}
+			if !yield(newChannel(raw), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Username resolves a user ID to a display name, caching the result for the Client's lifetime.
+func (c *Client) Username(id string) (string, error) {
+	return c.users.UsernameForID(id)
+}
+
+// Markdown renders messages (in the order given) as GitHub-flavored markdown,
+// resolving authors and mentions to display names.
+func (c *Client) Markdown(ctx context.Context, messages []Message) (string, error) {
+	raw := make([]map[string]any, len(messages))
+	for i, m := range messages {
+		raw[i] = m.Raw
+	}
+	c.users.Prefetch(ctx, raw)
+	return output.FormatMarkdown(raw, c.users)
+}
+
+// Channel is a Slack conversation: a channel, private channel, DM, or group DM.
+type Channel struct {
+	ID         string
+	Name       string // empty for DMs
+	IsPrivate  bool
+	IsIM       bool
+	IsMPIM     bool
+	IsArchived bool
+	Raw        map[string]any // the conversation object as Slack returned it
+}
+
+func newChannel(raw map[string]any) Channel {
+	c := Channel{Raw: raw}
+	c.ID, _ = raw["id"].(string)
+	c.Name, _ = raw["name"].(string)
+	c.IsPrivate, _ = raw["is_private"].(bool)
+	c.IsIM, _ = raw["is_im"].(bool)
+	c.IsMPIM, _ = raw["is_mpim"].(bool)
+	c.IsArchived, _ = raw["is_archived"].(bool)
+	return c
+}